@@ -53,6 +53,131 @@ var (
 		Code:    http.StatusBadRequest,
 		Message: "invalid filter value",
 	}
+	// Wrong sort options.
+	ErrWrongSortOptions = Error{
+		Code:    http.StatusBadRequest,
+		Message: "wrong sort options",
+	}
+	// Invalid or tampered pagination cursor.
+	ErrInvalidCursor = Error{
+		Code:    http.StatusBadRequest,
+		Message: "invalid cursor",
+	}
+	// Invalid document version.
+	ErrInvalidVersion = Error{
+		Code:    http.StatusBadRequest,
+		Message: "invalid document version",
+	}
+	// Wrong grant options.
+	ErrWrongGrantOptions = Error{
+		Code:    http.StatusBadRequest,
+		Message: "wrong grant options",
+	}
+	// Share link is malformed or signed with other secret.
+	ErrInvalidShareLink = Error{
+		Code:    http.StatusNotFound,
+		Message: "invalid share link",
+	}
+	// Share link is expired.
+	ErrShareLinkExpired = Error{
+		Code:    http.StatusGone,
+		Message: "share link expired",
+	}
+	// Share link downloads limit is reached.
+	ErrShareLinkExhausted = Error{
+		Code:    http.StatusGone,
+		Message: "share link downloads limit reached",
+	}
+	// Wrong share link password.
+	ErrWrongSharePassword = Error{
+		Code:    http.StatusForbidden,
+		Message: "wrong share link password",
+	}
+	// Wrong share link options.
+	ErrWrongShareLinkOptions = Error{
+		Code:    http.StatusBadRequest,
+		Message: "wrong share link options",
+	}
+	// File size doesn't match size in metadata.
+	ErrFileSizeMismatch = Error{
+		Code:    http.StatusBadRequest,
+		Message: "file size mismatch",
+	}
+	// File checksum doesn't match checksum in metadata.
+	ErrChecksumMismatch = Error{
+		Code:    http.StatusBadRequest,
+		Message: "file checksum mismatch",
+	}
+	// Presigned URLs are not supported by storage backend.
+	ErrPresignNotSupported = Error{
+		Code:    http.StatusNotImplemented,
+		Message: "presigned urls are not supported by storage backend",
+	}
+	// File of presigned upload is not uploaded yet.
+	ErrFileNotUploaded = Error{
+		Code:    http.StatusConflict,
+		Message: "file is not uploaded",
+	}
+	// Wrong resumable upload options.
+	ErrWrongUploadOptions = Error{
+		Code:    http.StatusBadRequest,
+		Message: "wrong upload options",
+	}
+	// Upload size exceeds the limit.
+	ErrUploadTooLarge = Error{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: "upload is too large",
+	}
+	// Upload offset doesn't match the number of received bytes.
+	ErrUploadOffsetMismatch = Error{
+		Code:    http.StatusConflict,
+		Message: "upload offset mismatch",
+	}
+	// Upload is being written by another request.
+	ErrUploadLocked = Error{
+		Code:    http.StatusLocked,
+		Message: "upload is locked",
+	}
+	// Storage quota of document owner is exceeded.
+	ErrQuotaExceeded = Error{
+		Code:    http.StatusInsufficientStorage,
+		Message: "storage quota exceeded",
+	}
+	// Wrong quota options.
+	ErrWrongQuotaOptions = Error{
+		Code:    http.StatusBadRequest,
+		Message: "wrong quota options",
+	}
+	// Document was changed since the version known to client.
+	ErrPreconditionFailed = Error{
+		Code:    http.StatusPreconditionFailed,
+		Message: "document was changed",
+	}
+	// Document expiration time is not in the future.
+	ErrWrongExpiration = Error{
+		Code:    http.StatusBadRequest,
+		Message: "expiration time must be in the future",
+	}
+	// Wrong folder name or parent folder.
+	ErrWrongFolderOptions = Error{
+		Code:    http.StatusBadRequest,
+		Message: "wrong folder options",
+	}
+	// Folder with the same path already exists.
+	ErrFolderExists = Error{
+		Code:    http.StatusConflict,
+		Message: "folder already exists",
+	}
+	// Folder has subfolders or documents.
+	ErrFolderNotEmpty = Error{
+		Code:    http.StatusConflict,
+		Message: "folder is not empty",
+	}
+	// Empty search query or wrong search page.
+	ErrWrongSearchOptions = Error{
+		Code:    http.StatusBadRequest,
+		Message: "wrong search options",
+	}
 
 	// HTTP errors.
 
@@ -61,6 +186,16 @@ var (
 		Code:    http.StatusBadRequest,
 		Message: "invalid content type",
 	}
+	// Unsupported upload content type.
+	ErrUnsupportedUploadContentType = Error{
+		Code:    http.StatusUnsupportedMediaType,
+		Message: "unsupported upload content type",
+	}
+	// Unsupported tus protocol version.
+	ErrUnsupportedTusVersion = Error{
+		Code:    http.StatusPreconditionFailed,
+		Message: "unsupported tus version",
+	}
 	// Invalid request body.
 	ErrAuthorizationHeaderNotFound = Error{
 		Code:    http.StatusUnauthorized,
@@ -2,20 +2,34 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	docctrl "github.com/FlutterDizaster/file-server/internal/controllers/document"
 	userctrl "github.com/FlutterDizaster/file-server/internal/controllers/user"
+	"github.com/FlutterDizaster/file-server/internal/docfilter"
+	"github.com/FlutterDizaster/file-server/internal/expirer"
 	jwtresolver "github.com/FlutterDizaster/file-server/internal/jwt-resolver"
 	"github.com/FlutterDizaster/file-server/internal/migrator"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/purger"
+	"github.com/FlutterDizaster/file-server/internal/reconciler"
+	"github.com/FlutterDizaster/file-server/internal/repository/comprepo"
+	"github.com/FlutterDizaster/file-server/internal/repository/cryptorepo"
+	"github.com/FlutterDizaster/file-server/internal/repository/fsrepo"
 	"github.com/FlutterDizaster/file-server/internal/repository/miniorepo"
 	"github.com/FlutterDizaster/file-server/internal/repository/postgresrepo"
 	"github.com/FlutterDizaster/file-server/internal/repository/redisrepo"
+	"github.com/FlutterDizaster/file-server/internal/rewrapper"
 	"github.com/FlutterDizaster/file-server/internal/server"
 	"github.com/FlutterDizaster/file-server/internal/server/handler"
+	"github.com/FlutterDizaster/file-server/internal/sharelink"
 	"github.com/FlutterDizaster/file-server/internal/validator"
+	"github.com/FlutterDizaster/file-server/internal/verifier"
 	"github.com/FlutterDizaster/file-server/pkg/configloader"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -23,11 +37,50 @@ const (
 	shutdownMaxTime = 5 * time.Second
 )
 
+const (
+	// commandVerify verifies stored files against their checksums and exits.
+	commandVerify = "verify"
+	// commandRewrap re-wraps data keys of stored files with the current master key and exits.
+	commandRewrap = "rewrap"
+)
+
+const (
+	// storageBackendMinio stores files in Minio object storage.
+	storageBackendMinio = "minio"
+	// storageBackendFS stores files in the local filesystem.
+	storageBackendFS = "fs"
+)
+
 // Service represents the application service.
 type Service interface {
 	Start(ctx context.Context) error
 }
 
+// fileRepository is a file storage backend used by the application.
+type fileRepository interface {
+	docctrl.FileRepository
+	docctrl.UploadStager
+	reconciler.FileRepository
+	cryptorepo.FileRepository
+}
+
+// services runs multiple services at once.
+// If one of the services fails, all other services are stopped.
+type services []Service
+
+// Start starts all services and blocks until all of them are stopped.
+func (s services) Start(ctx context.Context) error {
+	eg, egCtx := errgroup.WithContext(ctx)
+
+	for _, service := range s {
+		eg.Go(func() error {
+			return service.Start(egCtx)
+		})
+	}
+
+	return eg.Wait()
+}
+
 //nolint:lll // struct tags too long
 type Settings struct {
 	PostgresConnectionString string `desc:"postgres connection string" env:"DATABASE_DSN"       name:"database-dsn"       short:"d"`
@@ -42,12 +95,45 @@ type Settings struct {
 	MinioBucket    string `desc:"minio bucket"     env:"MINIO_BUCKET"     name:"minio-bucket"     short:"b"`
 	MinioUseSSL    bool   `desc:"minio use ssl"    env:"MINIO_USE_SSL"    name:"minio-use-ssl"    short:"u"`
 
+	StorageBackend string `desc:"file storage backend (minio, fs), default minio" env:"STORAGE_BACKEND" name:"storage-backend" default:"minio"`
+	FSStorageRoot  string `desc:"fs storage root directory"                      env:"FS_STORAGE_ROOT" name:"fs-storage-root"`
+
+	EncryptionKeys string `desc:"master keys <id>:<base64 key> separated by comma, the first is current" env:"ENCRYPTION_KEYS" name:"encryption-keys"`
+
+	CompressionRules string `desc:"compressed mime types <mime type>:<gzip|zstd> separated by comma" env:"COMPRESSION_RULES" name:"compression-rules"`
+
 	AdminToken string `desc:"admin token" env:"ADMIN_TOKEN" name:"admin-token"`
 
 	JWTSecret string `desc:"jwt secret"                      env:"JWT_SECRET" name:"jwt-secret" short:"j"`
 	JWTIssuer string `desc:"jwt issuer, default file-server" env:"JWT_ISSUER" name:"jwt-issuer"           default:"file-server"`
 	JWTTTL    string `desc:"jwt ttl, default 24h"            env:"JWT_TTL"    name:"jwt-ttl"              default:"24h"`
 
+	CursorSecret string `desc:"pagination cursor signing secret, default jwt secret" env:"CURSOR_SECRET" name:"cursor-secret"`
+
+	ShareLinkSecret string `desc:"share link signing secret, default jwt secret" env:"SHARE_LINK_SECRET"  name:"share-link-secret"`
+	ShareLinkTTL    string `desc:"default share link ttl, default 24h"           env:"SHARE_LINK_TTL"     name:"share-link-ttl"     default:"24h"`
+	ShareLinkMaxTTL string `desc:"max share link ttl, default 720h"              env:"SHARE_LINK_MAX_TTL" name:"share-link-max-ttl" default:"720h"`
+
+	PresignTTL string `desc:"presigned storage urls ttl, default 15m" env:"PRESIGN_TTL" name:"presign-ttl" default:"15m"`
+
+	ChecksumMD5 bool `desc:"compute md5 checksum of uploaded files" env:"CHECKSUM_MD5" name:"checksum-md5"`
+
+	ReconcileInterval string `desc:"failed uploads cleanup interval, default 10m"     env:"RECONCILE_INTERVAL" name:"reconcile-interval" default:"10m"`
+	PendingUploadTTL  string `desc:"time before pending upload is failed, default 1h" env:"PENDING_UPLOAD_TTL" name:"pending-upload-ttl" default:"1h"`
+
+	TrashRetention string `desc:"time before trashed document is purged, default 720h" env:"TRASH_RETENTION" name:"trash-retention" default:"720h"`
+	PurgeInterval  string `desc:"trash purge interval, default 1h"                   env:"PURGE_INTERVAL"  name:"purge-interval"  default:"1h"`
+
+	ExpirationInterval string `desc:"expired documents cleanup interval, default 1m" env:"EXPIRATION_INTERVAL" name:"expiration-interval" default:"1m"`
+
+	UnreferencedBlobTTL string `desc:"time before not referenced blob is removed, default 1h" env:"UNREFERENCED_BLOB_TTL" name:"unreferenced-blob-ttl" default:"1h"`
+
+	ResumableUploadTTL     string `desc:"time before unfinished resumable upload is removed, default 24h" env:"RESUMABLE_UPLOAD_TTL"      name:"resumable-upload-ttl"      default:"24h"`
+	MaxResumableUploadSize int64  `desc:"max resumable upload size, default 10Gb"                         env:"MAX_RESUMABLE_UPLOAD_SIZE" name:"max-resumable-upload-size" default:"10737418240"`
+
+	DefaultQuotaBytes     int64 `desc:"default storage quota in bytes, 0 is unlimited" env:"DEFAULT_QUOTA_BYTES"     name:"default-quota-bytes"     default:"0"`
+	DefaultQuotaDocuments int   `desc:"default documents quota, 0 is unlimited"        env:"DEFAULT_QUOTA_DOCUMENTS" name:"default-quota-documents" default:"0"`
+
 	HTTPAddr                 string `desc:"http address, default localhost"             env:"HTTP_ADDR"            name:"http-addr"            short:"a" default:"localhost"`
 	HTTPPort                 string `desc:"http port, default 8080"                     env:"HTTP_PORT"            name:"http-port"            short:"p" default:"8080"`
 	HandlerMaxUploadFileSize int64  `desc:"handler max upload file size, default 200Mb" env:"MAX_UPLOAD_FILE_SIZE" name:"max-upload-file-size"           default:"209715200"`
@@ -83,11 +169,31 @@ func New(ctx context.Context) (Service, error) {
 		return nil, err
 	}
 
-	minioRepo, err := newMinioRepository(ctx, settings)
+	fileRepo, err := newFileRepository(ctx, settings)
 	if err != nil {
 		return nil, err
 	}
 
+	docFileRepo, cryptoRepo, err := newDocumentsFileRepository(settings, fileRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admin commands
+	if args := configloader.Args(); len(args) > 0 {
+		switch args[0] {
+		case commandVerify:
+			return newVerifier(settings, docFileRepo, postgresRepo), nil
+		case commandRewrap:
+			if cryptoRepo == nil {
+				return nil, errors.New("encryption is not enabled")
+			}
+			return newRewrapper(cryptoRepo, postgresRepo), nil
+		default:
+			return nil, fmt.Errorf("unknown command: %s", args[0])
+		}
+	}
+
 	// new resolver and validator
 	resolver, err := newJWTResolver(settings)
 	if err != nil {
@@ -100,12 +206,17 @@ func New(ctx context.Context) (Service, error) {
 	}
 
 	// new controllers
-	documentsController := newDocumentsController(
-		minioRepo,
+	documentsController, err := newDocumentsController(
+		settings,
+		docFileRepo,
+		fileRepo,
 		postgresRepo,
 		postgresRepo,
 		redisRepo,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	userController := newUserController(
 		postgresRepo,
@@ -119,12 +230,30 @@ func New(ctx context.Context) (Service, error) {
 		userController,
 		documentsController,
 		settings.HandlerMaxUploadFileSize,
+		settings.MaxResumableUploadSize,
+		settings.AdminToken,
 	)
 
 	// new server
 	server := newServer(settings, handler)
 
-	return server, nil
+	// new background workers
+	uploadReconciler, err := newReconciler(settings, fileRepo, postgresRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	trashPurger, err := newPurger(settings, fileRepo, postgresRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	docExpirer, err := newExpirer(settings, fileRepo, postgresRepo, redisRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	return services{server, uploadReconciler, trashPurger, docExpirer}, nil
 }
 
 func newPostgresRepository(
@@ -150,6 +279,31 @@ func newRedisRepository(
 	return redisrepo.New(ctx, repoSettings)
 }
 
+func newFileRepository(
+	ctx context.Context,
+	settings Settings,
+) (fileRepository, error) {
+	switch settings.StorageBackend {
+	case storageBackendMinio:
+		return newMinioRepository(ctx, settings)
+	case storageBackendFS:
+		return newFSRepository(ctx, settings)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", settings.StorageBackend)
+	}
+}
+
+func newFSRepository(
+	ctx context.Context,
+	settings Settings,
+) (*fsrepo.FSRepository, error) {
+	repoSettings := fsrepo.Settings{
+		Root: settings.FSStorageRoot,
+	}
+
+	return fsrepo.New(ctx, repoSettings)
+}
+
 func newMinioRepository(
 	ctx context.Context,
 	settings Settings,
@@ -165,6 +319,56 @@ func newMinioRepository(
 	return miniorepo.New(ctx, repoSettings)
 }
 
+// newDocumentsFileRepository wraps file repository with encryption if master keys are configured
+// and with compression if compression rules are configured. Files are compressed before encryption.
+// Returns wrapped repository and encrypting repository, which is nil if encryption is disabled.
+func newDocumentsFileRepository(
+	settings Settings,
+	fileRepo fileRepository,
+) (docctrl.FileRepository, *cryptorepo.CryptoRepository, error) {
+	var (
+		docFileRepo docctrl.FileRepository = fileRepo
+		cryptoRepo  *cryptorepo.CryptoRepository
+	)
+
+	if settings.EncryptionKeys != "" {
+		keys, err := cryptorepo.ParseMasterKeys(settings.EncryptionKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		repoSettings := cryptorepo.Settings{
+			Repo:       fileRepo,
+			MasterKeys: keys,
+		}
+
+		cryptoRepo, err = cryptorepo.New(repoSettings)
+		if err != nil {
+			return nil, nil, err
+		}
+		docFileRepo = cryptoRepo
+	}
+
+	if settings.CompressionRules != "" {
+		rules, err := comprepo.ParseRules(settings.CompressionRules)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		repoSettings := comprepo.Settings{
+			Repo:  docFileRepo,
+			Rules: rules,
+		}
+
+		docFileRepo, err = comprepo.New(repoSettings)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return docFileRepo, cryptoRepo, nil
+}
+
 func newJWTResolver(settings Settings) (*jwtresolver.JWTResolver, error) {
 	ttl, err := time.ParseDuration(settings.JWTTTL)
 	if err != nil {
@@ -179,24 +383,80 @@ func newJWTResolver(settings Settings) (*jwtresolver.JWTResolver, error) {
 	return jwtresolver.New(jwtSettings), nil
 }
 
+func newCursorSigner(settings Settings) *docfilter.CursorSigner {
+	secret := settings.CursorSecret
+	if secret == "" {
+		secret = settings.JWTSecret
+	}
+
+	return docfilter.NewCursorSigner(secret)
+}
+
+func newShareLinkSigner(settings Settings) *sharelink.Signer {
+	secret := settings.ShareLinkSecret
+	if secret == "" {
+		secret = settings.JWTSecret
+	}
+
+	return sharelink.New(secret)
+}
+
 func newValidator(settings Settings) (*validator.Validator, error) {
 	return validator.New(settings.AdminToken)
 }
 
 func newDocumentsController(
+	settings Settings,
 	fileRepo docctrl.FileRepository,
+	uploads docctrl.UploadStager,
 	userRepo docctrl.UserRepository,
 	metaRepo docctrl.MetadataRepository,
 	cache docctrl.MetadataCache,
-) *docctrl.DocumentsController {
+) (*docctrl.DocumentsController, error) {
+	shareLinkTTL, err := time.ParseDuration(settings.ShareLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	shareLinkMaxTTL, err := time.ParseDuration(settings.ShareLinkMaxTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	presignTTL, err := time.ParseDuration(settings.PresignTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only some storage backends support presigned URLs.
+	// Encrypted and compressed files can't be accessed directly.
+	presigner, _ := fileRepo.(docctrl.Presigner)
+
+	// Files are encoded only if compression is enabled.
+	encoder, _ := fileRepo.(docctrl.FileEncoder)
+
 	controllerSettings := docctrl.Settings{
-		FileRepo: fileRepo,
-		MetaRepo: metaRepo,
-		UserRepo: userRepo,
-		Cache:    cache,
+		FileRepo:        fileRepo,
+		MetaRepo:        metaRepo,
+		UserRepo:        userRepo,
+		Cache:           cache,
+		Uploads:         uploads,
+		Cursors:         newCursorSigner(settings),
+		ShareLinks:      newShareLinkSigner(settings),
+		ShareLinkTTL:    shareLinkTTL,
+		ShareLinkMaxTTL: shareLinkMaxTTL,
+		Presigner:       presigner,
+		PresignTTL:      presignTTL,
+		Encoder:         encoder,
+		ComputeMD5:      settings.ChecksumMD5,
+		SkipFileText:    settings.EncryptionKeys != "",
+		DefaultQuota: models.Quota{
+			MaxBytes:     settings.DefaultQuotaBytes,
+			MaxDocuments: settings.DefaultQuotaDocuments,
+		},
 	}
 
-	return docctrl.New(controllerSettings)
+	return docctrl.New(controllerSettings), nil
 }
 
 func newUserController(
@@ -218,17 +478,130 @@ func newHandler(
 	userCtrl handler.UserController,
 	docCtrl handler.DocumentsController,
 	maxUploadSize int64,
+	maxResumableUploadSize int64,
+	adminToken string,
 ) *handler.Handler {
 	handlerSettings := handler.Settings{
-		JWTResolver:       resolver,
-		UserCtrl:          userCtrl,
-		DocumentsCtrl:     docCtrl,
-		MaxUploadFileSize: maxUploadSize,
+		JWTResolver:            resolver,
+		UserCtrl:               userCtrl,
+		DocumentsCtrl:          docCtrl,
+		MaxUploadFileSize:      maxUploadSize,
+		MaxResumableUploadSize: maxResumableUploadSize,
+		AdminToken:             adminToken,
 	}
 
 	return handler.New(handlerSettings)
 }
 
+func newReconciler(
+	settings Settings,
+	fileRepo reconciler.FileRepository,
+	metaRepo reconciler.MetadataRepository,
+) (*reconciler.Reconciler, error) {
+	interval, err := time.ParseDuration(settings.ReconcileInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingTTL, err := time.ParseDuration(settings.PendingUploadTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadTTL, err := time.ParseDuration(settings.ResumableUploadTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	blobTTL, err := time.ParseDuration(settings.UnreferencedBlobTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	reconcilerSettings := reconciler.Settings{
+		FileRepo:   fileRepo,
+		MetaRepo:   metaRepo,
+		Interval:   interval,
+		PendingTTL: pendingTTL,
+		UploadTTL:  uploadTTL,
+		BlobTTL:    blobTTL,
+	}
+
+	return reconciler.New(reconcilerSettings), nil
+}
+
+func newPurger(
+	settings Settings,
+	fileRepo purger.FileRepository,
+	metaRepo purger.MetadataRepository,
+) (*purger.Purger, error) {
+	interval, err := time.ParseDuration(settings.PurgeInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	retention, err := time.ParseDuration(settings.TrashRetention)
+	if err != nil {
+		return nil, err
+	}
+
+	purgerSettings := purger.Settings{
+		FileRepo:  fileRepo,
+		MetaRepo:  metaRepo,
+		Interval:  interval,
+		Retention: retention,
+	}
+
+	return purger.New(purgerSettings), nil
+}
+
+func newExpirer(
+	settings Settings,
+	fileRepo expirer.FileRepository,
+	metaRepo expirer.MetadataRepository,
+	cache expirer.MetadataCache,
+) (*expirer.Expirer, error) {
+	interval, err := time.ParseDuration(settings.ExpirationInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	expirerSettings := expirer.Settings{
+		FileRepo: fileRepo,
+		MetaRepo: metaRepo,
+		Cache:    cache,
+		Interval: interval,
+	}
+
+	return expirer.New(expirerSettings), nil
+}
+
+func newVerifier(
+	settings Settings,
+	fileRepo verifier.FileRepository,
+	metaRepo verifier.MetadataRepository,
+) *verifier.Verifier {
+	verifierSettings := verifier.Settings{
+		FileRepo:   fileRepo,
+		MetaRepo:   metaRepo,
+		ComputeMD5: settings.ChecksumMD5,
+	}
+
+	return verifier.New(verifierSettings)
+}
+
+func newRewrapper(
+	fileRepo rewrapper.FileRepository,
+	metaRepo rewrapper.MetadataRepository,
+) *rewrapper.Rewrapper {
+	rewrapperSettings := rewrapper.Settings{
+		FileRepo: fileRepo,
+		MetaRepo: metaRepo,
+	}
+
+	return rewrapper.New(rewrapperSettings)
+}
+
 func newServer(settings Settings, handler http.Handler) *server.Server {
 	serverSettings := server.Settings{
 		Addr:    settings.HTTPAddr,
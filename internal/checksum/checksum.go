@@ -0,0 +1,88 @@
+package checksum
+
+import (
+	"crypto/md5" //nolint:gosec // used for S3 ETag parity, not for security
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+)
+
+// Hasher computes checksums of data written to it.
+// Must be initialized with New function.
+type Hasher struct {
+	sha256 hash.Hash
+	md5    hash.Hash
+	writer io.Writer
+}
+
+// New creates new Hasher.
+// SHA-256 is always computed, MD5 is computed only if withMD5 is true.
+func New(withMD5 bool) *Hasher {
+	h := &Hasher{
+		sha256: sha256.New(),
+	}
+
+	h.writer = h.sha256
+	if withMD5 {
+		h.md5 = md5.New() //nolint:gosec // used for S3 ETag parity, not for security
+		h.writer = io.MultiWriter(h.sha256, h.md5)
+	}
+
+	return h
+}
+
+// Write implements io.Writer interface.
+func (h *Hasher) Write(p []byte) (int, error) {
+	return h.writer.Write(p)
+}
+
+// SHA256 returns hex encoded SHA-256 checksum of written data.
+func (h *Hasher) SHA256() string {
+	return hex.EncodeToString(h.sha256.Sum(nil))
+}
+
+// MD5 returns hex encoded MD5 checksum of written data.
+// Returns empty string if MD5 is not computed.
+func (h *Hasher) MD5() string {
+	if h.md5 == nil {
+		return ""
+	}
+	return hex.EncodeToString(h.md5.Sum(nil))
+}
+
+// Equal reports whether hex encoded checksums are equal ignoring case.
+func Equal(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// Digest returns value of Digest header for hex encoded checksums.
+// Empty checksums are skipped, empty string is returned if both are empty.
+func Digest(sha256Hex, md5Hex string) string {
+	var values []string
+
+	if value, ok := encodeDigest(sha256Hex); ok {
+		values = append(values, "sha-256="+value)
+	}
+	if value, ok := encodeDigest(md5Hex); ok {
+		values = append(values, "md5="+value)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// encodeDigest converts hex encoded checksum to base64 used in Digest header.
+func encodeDigest(hexSum string) (string, bool) {
+	if hexSum == "" {
+		return "", false
+	}
+
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return "", false
+	}
+
+	return base64.StdEncoding.EncodeToString(sum), true
+}
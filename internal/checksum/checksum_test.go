@@ -0,0 +1,50 @@
+package checksum
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasher(t *testing.T) {
+	type test struct {
+		name       string
+		data       string
+		withMD5    bool
+		wantSHA256 string
+		wantMD5    string
+		wantDigest string
+	}
+	tests := []test{
+		{
+			name:       "sha256 only",
+			data:       "test data",
+			wantSHA256: "916f0027a575074ce72a331777c3478d6513f786a591bd892da1a577bf2335f9",
+			wantDigest: "sha-256=kW8AJ6V1B0znKjMXd8NHjWUT94alkb2JLaGld78jNfk=",
+		},
+		{
+			name:       "with md5",
+			data:       "test data",
+			withMD5:    true,
+			wantSHA256: "916f0027a575074ce72a331777c3478d6513f786a591bd892da1a577bf2335f9",
+			wantMD5:    "eb733a00c0c9d336e65691a37ab54293",
+			wantDigest: "sha-256=kW8AJ6V1B0znKjMXd8NHjWUT94alkb2JLaGld78jNfk=,md5=63M6AMDJ0zbmVpGjerVCkw==",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(tt.withMD5)
+
+			_, err := io.Copy(h, strings.NewReader(tt.data))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantSHA256, h.SHA256())
+			assert.Equal(t, tt.wantMD5, h.MD5())
+			assert.Equal(t, tt.wantDigest, Digest(h.SHA256(), h.MD5()))
+			assert.True(t, Equal(strings.ToUpper(tt.wantSHA256), h.SHA256()))
+		})
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"io"
+	"log/slog"
+	"time"
 
 	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/checksum"
 	"github.com/FlutterDizaster/file-server/internal/docfilter"
+	"github.com/FlutterDizaster/file-server/internal/docfilter/filters"
 	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/sharelink"
 	"github.com/google/uuid"
 )
 
@@ -15,7 +20,8 @@ import (
 type FileRepository interface {
 	// UploadFile upload file to repository.
 	// Returns error if upload failed.
-	UploadFile(ctx context.Context, file io.Reader, meta models.Metadata) error
+	// Returns size of stored file if upload was successful.
+	UploadFile(ctx context.Context, file io.Reader, meta models.Metadata) (int64, error)
 
 	// GetFile get file from repository.
 	// Returns error if get failed.
@@ -23,25 +29,305 @@ type FileRepository interface {
 	GetFile(ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error)
 
 	// DeleteFile delete file from repository.
+	// Blob of deduplicated file is not deleted.
 	// Returns error if delete failed.
-	DeleteFile(ctx context.Context, id string) error
+	DeleteFile(ctx context.Context, meta models.Metadata) error
+
+	// StoreBlob move file to content addressed blob meta.SHA256.
+	// Returns error if move failed.
+	StoreBlob(ctx context.Context, meta models.Metadata) error
 }
 
 // MetadataRepository used to upload, download and delete metadata.
 type MetadataRepository interface {
 	// UploadMetadata upload metadata to repository.
+	// Returns ErrQuotaExceeded if document doesn't fit into quota of its owner.
 	// Returns error if upload failed.
 	// Returns file id if upload was successful.
-	UploadMetadata(ctx context.Context, meta models.Metadata) (uuid.UUID, error)
+	UploadMetadata(ctx context.Context, meta models.Metadata, quota models.Quota) (uuid.UUID, error)
 
-	// GetMetadataByUserID get metadata from repository.
+	// GetMetadataByUserID get metadata of documents user owns or documents shared with user.
 	// Returns error if get failed.
 	// Returns []models.Metadata if get was successful.
 	GetMetadataByUserID(ctx context.Context, userID uuid.UUID) ([]models.Metadata, error)
 
-	// DeleteMetadata delete metadata from repository.
+	// GetMetadataByQuery get metadata visible to user that matches compiled filter query.
+	// Documents user owns and documents shared with user are visible.
+	// Returns error if get failed.
+	// Returns []models.Metadata if get was successful.
+	GetMetadataByQuery(
+		ctx context.Context,
+		userID uuid.UUID,
+		query docfilter.SQLQuery,
+	) ([]models.Metadata, error)
+
+	// CountMetadataByQuery count metadata visible to user that matches compiled filter query.
+	// Returns error if count failed.
+	// Returns number of matching metadata if count was successful.
+	CountMetadataByQuery(
+		ctx context.Context,
+		userID uuid.UUID,
+		query docfilter.SQLQuery,
+	) (int, error)
+
+	// GetMetadataByID get metadata of document regardless of its owner.
+	// Returns ErrNotFound if document does not exist.
+	GetMetadataByID(ctx context.Context, id uuid.UUID) (models.Metadata, error)
+
+	// GetMetadataByPath get metadata of the newest document of user with given name in folder with given path.
+	// Returns ErrNotFound if document does not exist.
+	GetMetadataByPath(ctx context.Context, ownerID uuid.UUID, folderPath, name string) (models.Metadata, error)
+
+	// SearchMetadata search documents visible to user by names, tags, JSON data and extracted text of files.
+	// Returns error if search failed.
+	// Returns page of found documents, most relevant first, if search was successful.
+	SearchMetadata(
+		ctx context.Context,
+		userID uuid.UUID,
+		query string,
+		limit, offset int,
+	) ([]models.SearchResult, error)
+
+	// AddShareLink add share link to repository.
+	// Returns error if add failed.
+	// Returns link id if add was successful.
+	AddShareLink(ctx context.Context, link models.ShareLink) (uuid.UUID, error)
+
+	// GetShareLink get share link from repository.
+	// Returns ErrNotFound if link does not exist.
+	GetShareLink(ctx context.Context, id uuid.UUID) (models.ShareLink, error)
+
+	// CountShareLinkDownload increment downloads counter of share link.
+	// Returns ErrShareLinkExhausted if downloads limit is reached.
+	CountShareLinkDownload(ctx context.Context, id uuid.UUID) error
+
+	// RemoveShareLinks remove share links of document.
+	// If createdBy is not nil, only links created by this user are removed.
+	// Returns error if remove failed.
+	RemoveShareLinks(ctx context.Context, id uuid.UUID, createdBy *uuid.UUID) error
+
+	// GetGrantees get ids of users document is shared with.
+	// Returns error if get failed.
+	GetGrantees(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+
+	// GrantAccess grant access of given level to document to users with given logins
+	// on behalf of user grantedBy, or of document owner if grantedBy is nil.
+	// Access granted by other user can be changed only on behalf of owner.
+	// Returns error if grant failed.
+	// Returns ids of users access was granted to if grant was successful.
+	GrantAccess(
+		ctx context.Context,
+		id uuid.UUID,
+		logins []string,
+		level models.AccessLevel,
+		grantedBy *uuid.UUID,
+	) ([]uuid.UUID, error)
+
+	// RevokeAccess revoke access to document from users with given logins.
+	// If grantedBy is not nil, only access granted by this user is revoked.
+	// Returns error if revoke failed.
+	// Returns ids of users access was revoked from if revoke was successful.
+	RevokeAccess(ctx context.Context, id uuid.UUID, logins []string, grantedBy *uuid.UUID) ([]uuid.UUID, error)
+
+	// GetAccessLevel get level of access to document granted to user.
+	// Returns ErrNotFound if access is not granted.
+	GetAccessLevel(ctx context.Context, id, userID uuid.UUID) (models.AccessLevel, error)
+
+	// UpdateMetadata change metadata of document and its current version in place.
+	// Nil fields of patch are not changed.
+	// If updated is not nil, document is changed only if it was not updated since then.
+	// Returns ErrNotFound if document does not exist.
+	// Returns ErrPreconditionFailed if document was updated.
+	UpdateMetadata(ctx context.Context, id uuid.UUID, patch models.MetadataPatch, updated *time.Time) error
+
+	// DeleteMetadata move metadata to trash.
+	// If updated is not nil, document is deleted only if it was not updated since then.
+	// Returns ErrPreconditionFailed if document was updated.
+	// Returns error if delete failed.
+	DeleteMetadata(ctx context.Context, id, userID uuid.UUID, updated *time.Time) error
+
+	// GetTrash get trashed documents owned by user.
+	// Returns error if get failed.
+	GetTrash(ctx context.Context, userID uuid.UUID) ([]models.Metadata, error)
+
+	// RestoreMetadata move trashed document owned by user back from trash.
+	// Returns ErrNotFound if document is not in trash of user.
+	RestoreMetadata(ctx context.Context, id, userID uuid.UUID) error
+
+	// PurgeMetadata permanently remove trashed or expired document with all its versions.
+	// Returns ErrNotFound if document is not trashed or expired.
+	// Returns error if purge failed.
+	PurgeMetadata(ctx context.Context, id uuid.UUID) error
+
+	// AddVersion add new pending version of document with id meta.ID.
+	// Returns ErrQuotaExceeded if version doesn't fit into quota of document owner.
+	// Returns error if add failed.
+	// Returns version number if add was successful.
+	AddVersion(ctx context.Context, meta models.Metadata, quota models.Quota) (int, error)
+
+	// GetPendingVersion get the latest pending version of document.
+	// Returns ErrNotFound if document has no pending versions.
+	GetPendingVersion(ctx context.Context, id uuid.UUID) (models.Metadata, error)
+
+	// SetUploadedFile set file size, stored size and checksums of pending version with id meta.ID
+	// and check that the file fits into quota of its owner.
+	// Returns ErrQuotaExceeded if quota is exceeded.
+	// Returns error if set failed.
+	SetUploadedFile(ctx context.Context, meta models.Metadata, quota models.Quota) error
+
+	// LinkBlob reference existing blob meta.SHA256 from version with id meta.ID.
+	// Returns false if blob does not exist.
+	LinkBlob(ctx context.Context, meta models.Metadata) (bool, error)
+
+	// AddBlob save blob meta.SHA256 and reference it from version with id meta.ID.
+	// store is called to store blob file while concurrent uploads of the same blob wait.
+	// Returns false if blob already exists, store is not called then.
+	// Returns error if add failed.
+	AddBlob(ctx context.Context, meta models.Metadata, store func() error) (bool, error)
+
+	// PromoteVersion make version meta.Version the current version of document with id meta.ID.
+	// If meta.ExpiresAt is set, expiration time of document is changed too.
+	// If updated is not nil, version is promoted only if document was not updated since then.
+	// Returns ErrPreconditionFailed if document was updated.
+	// Returns error if promote failed.
+	PromoteVersion(ctx context.Context, meta models.Metadata, updated *time.Time) error
+
+	// RestoreVersion make already uploaded version the current version of document.
+	// If updated is not nil, version is restored only if document was not updated since then.
+	// Returns ErrNotFound if version does not exist or is not uploaded yet.
+	// Returns ErrPreconditionFailed if document was updated.
+	RestoreVersion(ctx context.Context, id uuid.UUID, version int, updated *time.Time) error
+
+	// RemoveVersion permanently remove version from repository.
+	// Metadata of never promoted document is removed too.
+	// Returns error if remove failed.
+	RemoveVersion(ctx context.Context, id uuid.UUID, version int) error
+
+	// GetVersions get all uploaded versions of document.
+	// Returns error if get failed.
+	// Returns []models.Metadata if get was successful.
+	GetVersions(ctx context.Context, id uuid.UUID) ([]models.Metadata, error)
+
+	// GetVersion get uploaded version of document.
+	// Returns error if get failed.
+	// Returns models.Metadata if get was successful.
+	GetVersion(ctx context.Context, id uuid.UUID, version int) (models.Metadata, error)
+
+	// AddUpload add resumable upload to repository.
+	// Upload length is reserved in quota of its owner until upload is removed.
+	// Returns ErrQuotaExceeded if quota is exceeded.
+	// Returns error if add failed.
+	// Returns upload with filled id if add was successful.
+	AddUpload(ctx context.Context, upload models.Upload, quota models.Quota) (models.Upload, error)
+
+	// SetUploadStorage save id of staged upload in file repository.
+	// Returns error if save failed.
+	SetUploadStorage(ctx context.Context, id uuid.UUID, storageID string) error
+
+	// GetUpload get resumable upload from repository.
+	// Returns ErrNotFound if upload does not exist.
+	GetUpload(ctx context.Context, id uuid.UUID) (models.Upload, error)
+
+	// LockUpload lock resumable upload for writing for lease duration.
+	// Returns ErrUploadLocked if upload is already locked.
+	// Returns locked upload if lock was successful.
+	LockUpload(ctx context.Context, id uuid.UUID, lease time.Duration) (models.Upload, error)
+
+	// UnlockUpload save offset of resumable upload and release the lock.
+	// Returns error if unlock failed.
+	UnlockUpload(ctx context.Context, id uuid.UUID, offset int64) error
+
+	// RemoveUpload remove resumable upload from repository.
+	// Returns error if remove failed.
+	RemoveUpload(ctx context.Context, id uuid.UUID) error
+
+	// GetQuota get storage limits set for user.
+	// Nil limits are not set.
+	// Returns error if get failed.
+	GetQuota(ctx context.Context, userID uuid.UUID) (models.QuotaRequest, error)
+
+	// SetQuota set storage limits of user.
+	// Returns error if set failed.
+	SetQuota(ctx context.Context, userID uuid.UUID, quota models.QuotaRequest) error
+
+	// GetUsage get storage usage of user.
+	// Returns error if get failed.
+	GetUsage(ctx context.Context, userID uuid.UUID) (models.Usage, error)
+
+	// AddFolder add folder to repository.
+	// Returns ErrWrongFolderOptions if parent folder does not exist.
+	// Returns ErrFolderExists if parent folder already has folder with the same name.
+	// Returns folder with filled id and path if add was successful.
+	AddFolder(ctx context.Context, folder models.Folder) (models.Folder, error)
+
+	// GetFolder get folder owned by user.
+	// Returns ErrNotFound if folder does not exist.
+	GetFolder(ctx context.Context, id, ownerID uuid.UUID) (models.Folder, error)
+
+	// GetFolders get all folders owned by user.
+	// Returns error if get failed.
+	GetFolders(ctx context.Context, ownerID uuid.UUID) ([]models.Folder, error)
+
+	// UpdateFolder rename folder owned by user or move it to another parent folder.
+	// Returns ErrNotFound if folder does not exist.
+	// Returns ErrWrongFolderOptions if new parent folder does not exist or is inside the folder.
+	// Returns ErrFolderExists if new parent folder already has folder with the same name.
+	UpdateFolder(ctx context.Context, id, ownerID uuid.UUID, patch models.FolderPatch) (models.Folder, error)
+
+	// DeleteFolder delete empty folder owned by user.
+	// Returns ErrNotFound if folder does not exist.
+	// Returns ErrFolderNotEmpty if folder has subfolders or documents.
+	DeleteFolder(ctx context.Context, id, ownerID uuid.UUID) error
+
+	// GetFolderGrantees get ids of users documents of folder and its subfolders are shared with.
+	// Returns error if get failed.
+	GetFolderGrantees(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+}
+
+// UploadStager used to stage chunks of resumable uploads in file repository.
+type UploadStager interface {
+	// CreateUpload prepare staging of upload.
+	// Returns error if create failed.
+	// Returns id of staged upload if create was successful.
+	CreateUpload(ctx context.Context, upload models.Upload) (string, error)
+
+	// WriteUpload append data to staged upload starting at upload.Offset.
+	// Returns new offset of staged upload, it is valid even if error is returned.
+	WriteUpload(ctx context.Context, upload models.Upload, data io.Reader) (int64, error)
+
+	// CompleteUpload assemble staged upload.
+	// Returns error if complete failed.
+	// Returns io.ReadCloser of the whole uploaded file if complete was successful.
+	CompleteUpload(ctx context.Context, upload models.Upload) (io.ReadCloser, error)
+
+	// RemoveUpload remove staged upload data.
+	// Returns error if remove failed.
+	RemoveUpload(ctx context.Context, upload models.Upload) error
+}
+
+// Presigner used to create presigned URLs for direct access to file repository.
+type Presigner interface {
+	// PresignUpload create URL to upload file with PUT request.
+	// Returns error if create failed.
+	PresignUpload(ctx context.Context, meta models.Metadata, ttl time.Duration) (string, error)
+
+	// PresignDownload create URL to download file with GET request.
+	// Returns error if create failed.
+	PresignDownload(ctx context.Context, meta models.Metadata, ttl time.Duration) (string, error)
+
+	// OpenStagedFile open file uploaded with presigned URL.
+	// Returns ErrNotFound if file does not exist.
+	OpenStagedFile(ctx context.Context, meta models.Metadata) (io.ReadCloser, error)
+
+	// DeleteStagedFile delete file uploaded with presigned URL.
 	// Returns error if delete failed.
-	DeleteMetadata(ctx context.Context, id, userID uuid.UUID) error
+	DeleteStagedFile(ctx context.Context, meta models.Metadata) error
+}
+
+// FileEncoder used to get encoding of stored files.
+type FileEncoder interface {
+	// FileEncoding return encoding of the document file stored by file repository.
+	FileEncoding(meta models.Metadata) string
 }
 
 // UserRepository used to get user by login.
@@ -68,9 +354,29 @@ type MetadataCache interface {
 	GetUserCache(ctx context.Context, id uuid.UUID) ([]models.Metadata, error)
 }
 
+// CursorSigner used to sign and verify pagination cursors.
+type CursorSigner interface {
+	// Encode encodes and signs cursor.
+	Encode(cursor docfilter.Cursor) string
+
+	// Decode verifies and decodes cursor.
+	// Returns error if cursor is invalid.
+	Decode(token string) (docfilter.Cursor, error)
+}
+
+// ShareLinkSigner used to sign and verify share link tokens.
+type ShareLinkSigner interface {
+	// Sign encodes and signs share link.
+	Sign(link sharelink.Link) string
+
+	// Parse verifies and decodes share link token.
+	// Returns error if token is invalid or expired.
+	Parse(token string) (sharelink.Link, error)
+}
+
 // Settings used to create DocumentsController.
 // Settings must be provided to New function.
-// All fields are required and cant be nil.
+// All fields except Presigner and Encoder are required and cant be nil.
 type Settings struct {
 	// FileRepo used to upload, download and delete files.
 	FileRepo FileRepository
@@ -83,6 +389,42 @@ type Settings struct {
 
 	// Cache used to cache metadata.
 	Cache MetadataCache
+
+	// Uploads used to stage chunks of resumable uploads.
+	Uploads UploadStager
+
+	// Presigner used to create presigned URLs.
+	// Optional, presigned URLs are not supported if nil.
+	Presigner Presigner
+
+	// PresignTTL is a presigned URLs lifetime.
+	PresignTTL time.Duration
+
+	// Encoder used to get encoding of stored files.
+	// Optional, files are stored as is if nil.
+	Encoder FileEncoder
+
+	// Cursors used to sign and verify pagination cursors.
+	Cursors CursorSigner
+
+	// ShareLinks used to sign and verify share link tokens.
+	ShareLinks ShareLinkSigner
+
+	// ShareLinkTTL is a default share link lifetime.
+	ShareLinkTTL time.Duration
+
+	// ShareLinkMaxTTL is a maximum share link lifetime.
+	ShareLinkMaxTTL time.Duration
+
+	// ComputeMD5 enables MD5 checksum of uploaded files in addition to SHA-256.
+	ComputeMD5 bool
+
+	// SkipFileText disables full-text search by text of files.
+	// Must be set if files are encrypted, as extracted text is stored in plaintext.
+	SkipFileText bool
+
+	// DefaultQuota is a storage limit of users without their own limits.
+	DefaultQuota models.Quota
 }
 
 // DocumentsController used to upload, download and delete documents.
@@ -92,6 +434,22 @@ type DocumentsController struct {
 	metaRepo MetadataRepository
 	userRepo UserRepository
 	cache    MetadataCache
+	uploads  UploadStager
+	cursors  CursorSigner
+
+	shareLinks      ShareLinkSigner
+	shareLinkTTL    time.Duration
+	shareLinkMaxTTL time.Duration
+
+	presigner  Presigner
+	presignTTL time.Duration
+
+	encoder FileEncoder
+
+	computeMD5   bool
+	skipFileText bool
+
+	defaultQuota models.Quota
 }
 
 // New creates new DocumentsController.
@@ -103,6 +461,22 @@ func New(settings Settings) *DocumentsController {
 		metaRepo: settings.MetaRepo,
 		userRepo: settings.UserRepo,
 		cache:    settings.Cache,
+		uploads:  settings.Uploads,
+		cursors:  settings.Cursors,
+
+		shareLinks:      settings.ShareLinks,
+		shareLinkTTL:    settings.ShareLinkTTL,
+		shareLinkMaxTTL: settings.ShareLinkMaxTTL,
+
+		presigner:  settings.Presigner,
+		presignTTL: settings.PresignTTL,
+
+		encoder: settings.Encoder,
+
+		computeMD5:   settings.ComputeMD5,
+		skipFileText: settings.SkipFileText,
+
+		defaultQuota: settings.DefaultQuota,
 	}
 
 	return ctrl
@@ -113,93 +487,416 @@ func New(settings Settings) *DocumentsController {
 // Returns nil if upload was successful.
 // If meta.File is true, file cant be nil.
 // If meta.File is false, meta.JSON must be provided.
+//
+// Binary documents are uploaded in two steps. Metadata is saved in pending status first,
+// then file is uploaded and metadata is marked as ready.
+// If file upload fails, metadata and partially uploaded file are removed.
+//
+// Document must fit into storage quota of the owner, quota is checked before the file is read
+// and again after file of unknown size is uploaded.
+// Returns ErrQuotaExceeded if quota is exceeded.
+// Returns ErrWrongExpiration if expiration time is set and is not in the future.
 func (c *DocumentsController) UploadDocument(
 	ctx context.Context,
 	meta models.Metadata,
 	file io.Reader,
 ) error {
-	// Invalidate user cache
-	if err := c.cache.InvalidateUserCache(ctx, *meta.OwnerID); err != nil {
+	if err := checkExpiration(&meta); err != nil {
 		return err
 	}
 
+	return c.uploadDocument(ctx, meta, file, 0)
+}
+
+// uploadDocument uploads document like UploadDocument does, expiration time must be already checked.
+// reserved is the number of bytes already reserved for the document in quota of the owner,
+// they are counted in usage until the document is uploaded, so quota is extended by them.
+func (c *DocumentsController) uploadDocument(
+	ctx context.Context,
+	meta models.Metadata,
+	file io.Reader,
+	reserved int64,
+) error {
+	if err := checkTags(&meta); err != nil {
+		return err
+	}
+
+	if err := c.checkFolder(ctx, &meta, *meta.OwnerID); err != nil {
+		return err
+	}
+
+	meta.Status = models.MetadataStatusReady
+	if meta.File {
+		meta.Status = models.MetadataStatusPending
+	}
+
+	quota, err := c.getQuota(ctx, *meta.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if quota.MaxBytes > 0 {
+		quota.MaxBytes += reserved
+	}
+
 	// Save metadata to repository
-	id, err := c.metaRepo.UploadMetadata(ctx, meta)
+	id, err := c.metaRepo.UploadMetadata(ctx, meta, quota)
 	if err != nil {
 		return err
 	}
 
 	meta.ID = &id
+	meta.Version = 1
 
 	// If file is binary then upload it to repository
 	if meta.File {
-		// Upload file to repository
-		err = c.fileRepo.UploadFile(ctx, file, meta)
+		err = c.storeVersion(ctx, meta, quota, file, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Invalidate cache of owner and grantees
+	return c.invalidateDocumentCache(ctx, id, *meta.OwnerID)
+}
+
+// storeVersion uploads file of pending version to repository and makes the version current.
+// If meta.FileSize is negative, file size is unknown and is counted while uploading,
+// the file must fit into remaining quota of the owner then, quota is checked again after upload.
+// Otherwise file must have exactly meta.FileSize bytes.
+// If updated is not nil, version becomes current only if the document was not updated since then.
+// If any step fails, version and partially uploaded file are removed.
+func (c *DocumentsController) storeVersion(
+	ctx context.Context,
+	meta models.Metadata,
+	quota models.Quota,
+	file io.Reader,
+	updated *time.Time,
+) error {
+	var err error
+
+	if meta.File && meta.FileSize < 0 {
+		file, err = c.limitToQuota(ctx, *meta.OwnerID, quota, file)
+	}
+
+	// Upload file to repository
+	if meta.File && err == nil {
+		err = c.uploadFile(ctx, meta, quota, file)
+	}
+
+	// Mark version as current
+	if err == nil {
+		err = c.metaRepo.PromoteVersion(ctx, meta, updated)
+	}
+
+	if err != nil {
+		c.compensateUpload(ctx, meta)
+		return err
+	}
+
+	return nil
+}
+
+// uploadFile uploads file of pending version to repository and checks its size and checksums.
+// Checksums in meta, if any, are expected checksums of the file.
+// Size, stored size, encoding, checksums and text of uploaded file are saved to version metadata
+// and the file is deduplicated. Text is not saved if file text search is disabled.
+// Uploaded file must fit into quota, it is checked when file size is saved.
+func (c *DocumentsController) uploadFile(
+	ctx context.Context,
+	meta models.Metadata,
+	quota models.Quota,
+	file io.Reader,
+) error {
+	meta.Encoding = models.FileEncodingIdentity
+	if c.encoder != nil {
+		meta.Encoding = c.encoder.FileEncoding(meta)
+	}
+
+	hasher := checksum.New(c.computeMD5 || meta.MD5 != "")
+	counter := &countingReader{r: io.TeeReader(file, hasher)}
+
+	// Beginning of text-like file is kept for full-text search
+	var text *textBuffer
+	if !c.skipFileText && isTextLike(meta) {
+		text = &textBuffer{}
+		counter.r = io.TeeReader(counter.r, text)
+	}
+
+	storedSize, err := c.fileRepo.UploadFile(ctx, counter, meta)
+	if err != nil {
+		return err
+	}
+
+	if meta.FileSize >= 0 {
+		// Repository may stop reading after meta.FileSize bytes
+		var extra int64
+		extra, err = io.Copy(io.Discard, io.LimitReader(counter, 1))
+		if err != nil {
+			return err
+		}
+
+		if extra > 0 || counter.n != meta.FileSize {
+			return apperrors.ErrFileSizeMismatch
+		}
+	}
+
+	if meta.SHA256 != "" && !checksum.Equal(meta.SHA256, hasher.SHA256()) ||
+		meta.MD5 != "" && !checksum.Equal(meta.MD5, hasher.MD5()) {
+		return apperrors.ErrChecksumMismatch
+	}
+
+	meta.FileSize = counter.n
+	meta.StoredSize = storedSize
+	meta.SHA256 = hasher.SHA256()
+	meta.MD5 = hasher.MD5()
+
+	if text != nil {
+		meta.ContentText = extractText(meta, text.Bytes())
+	}
+
+	err = c.metaRepo.SetUploadedFile(ctx, meta, quota)
+	if err != nil {
+		return err
+	}
+
+	return c.dedupFile(ctx, meta)
+}
+
+// dedupFile replaces uploaded file of pending version with content addressed blob.
+// If blob with the same checksum exists, the file is removed and the blob is referenced,
+// otherwise the file is moved to new blob.
+// Existing blob file is never replaced, as its encoding may differ from encoding of the file.
+func (c *DocumentsController) dedupFile(ctx context.Context, meta models.Metadata) error {
+	for {
+		linked, err := c.metaRepo.LinkBlob(ctx, meta)
 		if err != nil {
 			return err
 		}
+
+		if linked {
+			break
+		}
+
+		added, err := c.metaRepo.AddBlob(ctx, meta, func() error {
+			return c.fileRepo.StoreBlob(ctx, meta)
+		})
+		if err != nil {
+			return err
+		}
+
+		if added {
+			return nil
+		}
+
+		// Blob was added by concurrent upload of the same content, link it
+	}
+
+	// File is not needed anymore, if remove fails it will be removed by reconciler
+	if err := c.fileRepo.DeleteFile(ctx, meta); err != nil {
+		slog.Error(
+			"Error while removing deduplicated file",
+			slog.String("id", meta.ID.String()),
+			slog.Int("version", meta.Version),
+			slog.Any("err", err),
+		)
 	}
 
 	return nil
 }
 
+// compensateUpload removes file and metadata of failed upload.
+// Errors are only logged, remaining data will be removed by reconciler.
+func (c *DocumentsController) compensateUpload(ctx context.Context, meta models.Metadata) {
+	// Request context may be already canceled
+	ctx = context.WithoutCancel(ctx)
+
+	if meta.File {
+		if err := c.fileRepo.DeleteFile(ctx, meta); err != nil {
+			slog.Error(
+				"Error while removing file of failed upload",
+				slog.String("id", meta.ID.String()),
+				slog.Int("version", meta.Version),
+				slog.Any("err", err),
+			)
+		}
+	}
+
+	if err := c.metaRepo.RemoveVersion(ctx, *meta.ID, meta.Version); err != nil {
+		slog.Error(
+			"Error while removing metadata of failed upload",
+			slog.String("id", meta.ID.String()),
+			slog.Int("version", meta.Version),
+			slog.Any("err", err),
+		)
+	}
+}
+
 // GetFilesInfo returns list of documents for given user.
-// If req.Login is empty, userID will be used to find files info.
-// If req.Login is not empty then it will be used to find user ID.
+// Documents user owns and documents shared with user are visible.
+// req.Scope selects own ("own"), shared with user ("shared") or all ("all") visible documents.
+// If req.Login is not empty then only visible documents owned by user with this login are listed.
+// Default scope is "all" if req.Login is not empty and "own" otherwise.
 // If req.Key and req.Value are not empty then they will be used to filter documents.
+// If req.Filters are not empty then they are combined with req.Operator and used to filter documents.
+// If req.Sort is not empty then documents are sorted by given keys, otherwise default order is used.
+// If req.Cursor is not empty then only documents after cursor are returned.
 // If req.Limit or req.Offset are not zero then they will be used to limit and offset documents.
-// If cache is empty then it will be filled with data from repository.
+// If user cache exists then cached data is filtered in memory.
+// Otherwise, filters are pushed down to repository, and filters that
+// can't be compiled to SQL are applied to the query result.
 // Returns error if get failed.
-// Returns []models.Metadata if get was successful.
+// Returns models.ResponseFilesList with documents page, next page cursor and
+// total number of matching documents if get was successful.
 func (c *DocumentsController) GetFilesInfo(
 	ctx context.Context,
 	userID uuid.UUID,
 	req models.FilesListRequest,
-) ([]models.Metadata, error) {
-	// Assign user ID
-	id := userID
+) (models.ResponseFilesList, error) {
+	// Create filter
+	filter, err := c.newListFilter(req)
+	if err != nil {
+		return models.ResponseFilesList{}, err
+	}
+
+	// Documents of other user can only be shared with user
+	scope := req.Scope
+	if scope == "" && req.Login != "" {
+		scope = ScopeAll
+	}
+
+	err = addScopeFilter(filter, userID, scope)
+	if err != nil {
+		return models.ResponseFilesList{}, err
+	}
+
+	// Filter by owner login
 	if req.Login != "" {
 		user, err := c.userRepo.GetUserByLogin(ctx, req.Login)
 		if err != nil {
-			return nil, err
+			return models.ResponseFilesList{}, err
+		}
+
+		err = filter.AddFilter(string(filters.FilterKeyOwner), user.ID.String())
+		if err != nil {
+			return models.ResponseFilesList{}, err
 		}
-		id = user.ID
 	}
 
-	// Try to get data from cache
+	var (
+		docs  []models.Metadata
+		total int
+	)
+
+	// Try to filter cached data
 	metadata, err := c.cache.GetUserCache(ctx, userID)
 	switch {
+	case err == nil:
+		metadata = removeExpired(metadata)
+		docs = filter.FilterData(metadata)
+		total = filter.Count(metadata)
 	case errors.Is(err, apperrors.ErrNotFound):
-		// If cache is empty then get data from repository
-		metadata, err = c.metaRepo.GetMetadataByUserID(ctx, id)
+		// If cache is empty then filter data in repository
+		docs, total, err = c.queryFilesInfo(ctx, userID, filter)
 		if err != nil {
-			return nil, err
+			return models.ResponseFilesList{}, err
 		}
+	default:
+		return models.ResponseFilesList{}, err
+	}
 
-		// Save data to cache
-		err = c.cache.SaveUserCache(ctx, userID, metadata)
+	return c.newFilesList(filter, docs, total, req.Limit), nil
+}
+
+// newListFilter creates filter for list request.
+// Filter limit is one more than requested to find out if there is the next page.
+func (c *DocumentsController) newListFilter(req models.FilesListRequest) (*docfilter.DocumentsFilter, error) {
+	filter := docfilter.New(req.Limit+1, req.Offset)
+	if req.Key != "" {
+		err := filter.AddFilter(req.Key, req.Value)
 		if err != nil {
 			return nil, err
 		}
-	case err != nil:
-		return nil, err
 	}
 
-	// Create filter
-	filter := docfilter.New(req.Limit, req.Offset)
-	err = filter.AddFilter(req.Key, req.Value)
+	err := filter.AddFilterClauses(req.Operator, req.Filters)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter metadata
-	metadata = filter.FilterData(metadata)
+	for _, sort := range req.Sort {
+		err = filter.AddSort(sort.Key, sort.Order)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// Return filtered data
-	return metadata, nil
+	if req.Cursor != "" {
+		cursor, err := c.cursors.Decode(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		err = filter.SetCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+// queryFilesInfo gets filtered documents page and total number of matching documents from repository.
+func (c *DocumentsController) queryFilesInfo(
+	ctx context.Context,
+	userID uuid.UUID,
+	filter *docfilter.DocumentsFilter,
+) ([]models.Metadata, int, error) {
+	query, rest := filter.SQL()
+	metadata, err := c.metaRepo.GetMetadataByQuery(ctx, userID, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Apply filters that can't be compiled to SQL
+	if rest != nil {
+		return rest.FilterData(metadata), rest.Count(metadata), nil
+	}
+
+	total, err := c.metaRepo.CountMetadataByQuery(ctx, userID, filter.CountSQL())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return metadata, total, nil
+}
+
+// newFilesList creates documents list response.
+// docs may contain one document more than limit, then it is cut off
+// and cursor pointing to the last returned document is set.
+func (c *DocumentsController) newFilesList(
+	filter *docfilter.DocumentsFilter,
+	docs []models.Metadata,
+	total int,
+	limit int,
+) models.ResponseFilesList {
+	list := models.ResponseFilesList{
+		Docs:  docs,
+		Total: total,
+	}
+
+	if len(docs) > limit {
+		list.Docs = docs[:limit]
+		if limit > 0 {
+			list.NextCursor = c.cursors.Encode(filter.Cursor(list.Docs[limit-1]))
+		}
+	}
+
+	return list
 }
 
 // GetFileInfo get metadata for given document id.
+// Document must be owned by user or shared with user.
 // First try to get data from cache.
 // If cache is empty then get data from repository.
 // Save data to cache.
@@ -238,7 +935,7 @@ func (c *DocumentsController) GetFileInfo(
 	}
 
 	// Filter metadata
-	metadata = filter.FilterData(metadata)
+	metadata = filter.FilterData(removeExpired(metadata))
 
 	// Return filtered data
 	if len(metadata) > 0 {
@@ -258,26 +955,47 @@ func (c *DocumentsController) GetFile(
 	return c.fileRepo.GetFile(ctx, meta)
 }
 
-// DeleteFile delete file and its metadata from repository.
+// DeleteFile moves document to trash.
+// Files of trashed document are kept until it is purged, so it can be restored.
+// Only owner can delete document.
+// If ifMatch is not empty, document is deleted only if its ETag matches ifMatch.
+// Returns ErrPreconditionFailed if ETag does not match.
 // Returns error if delete failed.
 // Returns nil if delete was successful.
-func (c *DocumentsController) DeleteFile(ctx context.Context, id, userID uuid.UUID) error {
-	// Invalidate user cache
-	if err := c.cache.InvalidateUserCache(ctx, userID); err != nil {
+func (c *DocumentsController) DeleteFile(ctx context.Context, id, userID uuid.UUID, ifMatch string) error {
+	// Get document metadata
+	meta, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
 		return err
 	}
 
-	// Delete file from repository
-	err := c.fileRepo.DeleteFile(ctx, id.String())
+	if *meta.OwnerID != userID {
+		return apperrors.ErrAccessDenied
+	}
+
+	updated, err := checkPrecondition(meta, ifMatch)
 	if err != nil {
 		return err
 	}
 
-	// Delete metadata from repository
-	err = c.metaRepo.DeleteMetadata(ctx, id, userID)
-	if err != nil {
+	// Invalidate cache of owner and grantees
+	if err = c.invalidateDocumentCache(ctx, id, userID); err != nil {
 		return err
 	}
 
-	return nil
+	// Move metadata to trash
+	return c.metaRepo.DeleteMetadata(ctx, id, userID, updated)
+}
+
+// countingReader is a wrapper for io.Reader that counts read bytes.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader interface.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
 }
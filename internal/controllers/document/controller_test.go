@@ -0,0 +1,327 @@
+package docctrl
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/sharelink"
+	"github.com/google/uuid"
+)
+
+// fakeGrant is access granted to user, grantedBy is nil if access is granted by owner.
+type fakeGrant struct {
+	level     models.AccessLevel
+	grantedBy *uuid.UUID
+}
+
+// fakeMetaRepo keeps documents, grants and users in memory.
+// Methods not used by tests are not implemented and panic.
+type fakeMetaRepo struct {
+	MetadataRepository
+
+	docs     []models.Metadata
+	versions []models.Metadata
+	grants   map[uuid.UUID]map[uuid.UUID]fakeGrant
+	users    map[string]uuid.UUID
+	links    []models.ShareLink
+	quotas   map[uuid.UUID]models.QuotaRequest
+	usage    map[uuid.UUID]models.Usage
+}
+
+func newFakeMetaRepo(docs ...models.Metadata) *fakeMetaRepo {
+	return &fakeMetaRepo{
+		docs:   docs,
+		grants: make(map[uuid.UUID]map[uuid.UUID]fakeGrant),
+		users:  make(map[string]uuid.UUID),
+		quotas: make(map[uuid.UUID]models.QuotaRequest),
+		usage:  make(map[uuid.UUID]models.Usage),
+	}
+}
+
+// grant grants access of given level to the document to user on behalf of owner.
+func (f *fakeMetaRepo) grant(id, userID uuid.UUID, level models.AccessLevel) {
+	f.setGrant(id, userID, fakeGrant{level: level})
+}
+
+func (f *fakeMetaRepo) setGrant(id, userID uuid.UUID, grant fakeGrant) {
+	if f.grants[id] == nil {
+		f.grants[id] = make(map[uuid.UUID]fakeGrant)
+	}
+	f.grants[id][userID] = grant
+}
+
+// addUser adds user with given login and returns its id.
+func (f *fakeMetaRepo) addUser(login string) uuid.UUID {
+	id := uuid.New()
+	f.users[login] = id
+	return id
+}
+
+// doc returns document with given id.
+func (f *fakeMetaRepo) doc(id uuid.UUID) models.Metadata {
+	for _, meta := range f.docs {
+		if *meta.ID == id {
+			return meta
+		}
+	}
+	return models.Metadata{}
+}
+
+func (f *fakeMetaRepo) GetMetadataByID(_ context.Context, id uuid.UUID) (models.Metadata, error) {
+	meta := f.doc(id)
+	if meta.ID == nil {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+	return meta, nil
+}
+
+func (f *fakeMetaRepo) GetMetadataByUserID(_ context.Context, userID uuid.UUID) ([]models.Metadata, error) {
+	var docs []models.Metadata
+	for _, meta := range f.docs {
+		if _, ok := f.grants[*meta.ID][userID]; ok || *meta.OwnerID == userID {
+			docs = append(docs, meta)
+		}
+	}
+	return docs, nil
+}
+
+// UpdateMetadata changes name, MIME type, public flag and expiration time of the document.
+func (f *fakeMetaRepo) UpdateMetadata(
+	_ context.Context,
+	id uuid.UUID,
+	patch models.MetadataPatch,
+	updated *time.Time,
+) error {
+	i := slices.IndexFunc(f.docs, func(doc models.Metadata) bool { return *doc.ID == id })
+	if i < 0 {
+		return apperrors.ErrNotFound
+	}
+	if updated != nil && !f.docs[i].Updated.Equal(*updated) {
+		return apperrors.ErrPreconditionFailed
+	}
+
+	meta := &f.docs[i]
+	if patch.Name != nil {
+		meta.Name = *patch.Name
+	}
+	if patch.Mime != nil {
+		meta.Mime = *patch.Mime
+	}
+	if patch.Public != nil {
+		meta.Public = *patch.Public
+	}
+	if patch.ExpiresAt != nil {
+		meta.ExpiresAt = patch.ExpiresAt
+		if meta.ExpiresAt.IsZero() {
+			meta.ExpiresAt = nil
+		}
+	}
+	meta.Updated = time.Now()
+	return nil
+}
+
+func (f *fakeMetaRepo) AddVersion(_ context.Context, meta models.Metadata, _ models.Quota) (int, error) {
+	meta.Version = f.doc(*meta.ID).Version + len(f.versions) + 1
+	f.versions = append(f.versions, meta)
+	return meta.Version, nil
+}
+
+// PromoteVersion makes added version current, expiration time is set for the whole document.
+func (f *fakeMetaRepo) PromoteVersion(_ context.Context, meta models.Metadata, updated *time.Time) error {
+	i := slices.IndexFunc(f.docs, func(doc models.Metadata) bool { return *doc.ID == *meta.ID })
+	if i < 0 {
+		return apperrors.ErrNotFound
+	}
+	if updated != nil && !f.docs[i].Updated.Equal(*updated) {
+		return apperrors.ErrPreconditionFailed
+	}
+
+	expiresAt := f.docs[i].ExpiresAt
+	if meta.ExpiresAt != nil {
+		expiresAt = meta.ExpiresAt
+		if expiresAt.IsZero() {
+			expiresAt = nil
+		}
+	}
+
+	meta.Status = models.MetadataStatusReady
+	meta.ExpiresAt = expiresAt
+	meta.Updated = time.Now()
+	f.docs[i] = meta
+	return nil
+}
+
+func (f *fakeMetaRepo) GetVersion(_ context.Context, id uuid.UUID, version int) (models.Metadata, error) {
+	for _, meta := range f.versions {
+		if *meta.ID == id && meta.Version == version && meta.Status == models.MetadataStatusReady {
+			return meta, nil
+		}
+	}
+	return models.Metadata{}, apperrors.ErrNotFound
+}
+
+// RestoreVersion makes uploaded version current.
+func (f *fakeMetaRepo) RestoreVersion(ctx context.Context, id uuid.UUID, version int, updated *time.Time) error {
+	meta, err := f.GetVersion(ctx, id, version)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(f.docs, func(doc models.Metadata) bool { return *doc.ID == id })
+	if updated != nil && !f.docs[i].Updated.Equal(*updated) {
+		return apperrors.ErrPreconditionFailed
+	}
+
+	meta.Updated = time.Now()
+	f.docs[i] = meta
+	return nil
+}
+
+func (f *fakeMetaRepo) GetGrantees(_ context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var grantees []uuid.UUID
+	for userID := range f.grants[id] {
+		grantees = append(grantees, userID)
+	}
+	return grantees, nil
+}
+
+func (f *fakeMetaRepo) GrantAccess(
+	_ context.Context,
+	id uuid.UUID,
+	logins []string,
+	level models.AccessLevel,
+	grantedBy *uuid.UUID,
+) ([]uuid.UUID, error) {
+	var users []uuid.UUID
+	for _, login := range logins {
+		userID, ok := f.users[login]
+		if !ok || userID == *f.doc(id).OwnerID {
+			return nil, apperrors.ErrWrongGrantOptions
+		}
+
+		current, ok := f.grants[id][userID]
+		if ok && grantedBy != nil && (current.grantedBy == nil || *current.grantedBy != *grantedBy) {
+			return nil, apperrors.ErrWrongGrantOptions
+		}
+
+		f.setGrant(id, userID, fakeGrant{level: level, grantedBy: grantedBy})
+		users = append(users, userID)
+	}
+	return users, nil
+}
+
+func (f *fakeMetaRepo) RevokeAccess(
+	_ context.Context,
+	id uuid.UUID,
+	logins []string,
+	grantedBy *uuid.UUID,
+) ([]uuid.UUID, error) {
+	var users []uuid.UUID
+	for _, login := range logins {
+		userID := f.users[login]
+
+		current, ok := f.grants[id][userID]
+		if !ok || grantedBy != nil && (current.grantedBy == nil || *current.grantedBy != *grantedBy) {
+			continue
+		}
+
+		delete(f.grants[id], userID)
+		users = append(users, userID)
+	}
+	return users, nil
+}
+
+func (f *fakeMetaRepo) GetAccessLevel(_ context.Context, id, userID uuid.UUID) (models.AccessLevel, error) {
+	current, ok := f.grants[id][userID]
+	if !ok {
+		return "", apperrors.ErrNotFound
+	}
+	return current.level, nil
+}
+
+func (f *fakeMetaRepo) AddShareLink(_ context.Context, link models.ShareLink) (uuid.UUID, error) {
+	link.ID = uuid.New()
+	f.links = append(f.links, link)
+	return link.ID, nil
+}
+
+func (f *fakeMetaRepo) GetShareLink(_ context.Context, id uuid.UUID) (models.ShareLink, error) {
+	for _, link := range f.links {
+		if link.ID == id {
+			return link, nil
+		}
+	}
+	return models.ShareLink{}, apperrors.ErrNotFound
+}
+
+func (f *fakeMetaRepo) RemoveShareLinks(_ context.Context, id uuid.UUID, createdBy *uuid.UUID) error {
+	f.links = slices.DeleteFunc(f.links, func(link models.ShareLink) bool {
+		return link.MetaID == id && (createdBy == nil || link.CreatedBy == *createdBy)
+	})
+	return nil
+}
+
+func (f *fakeMetaRepo) GetQuota(_ context.Context, userID uuid.UUID) (models.QuotaRequest, error) {
+	return f.quotas[userID], nil
+}
+
+func (f *fakeMetaRepo) GetUsage(_ context.Context, userID uuid.UUID) (models.Usage, error) {
+	return f.usage[userID], nil
+}
+
+// fakeCache keeps cached documents of users in memory and records invalidated users.
+type fakeCache struct {
+	cache       map[uuid.UUID][]models.Metadata
+	invalidated []uuid.UUID
+}
+
+func (f *fakeCache) InvalidateUserCache(_ context.Context, id uuid.UUID) error {
+	delete(f.cache, id)
+	f.invalidated = append(f.invalidated, id)
+	return nil
+}
+
+func (f *fakeCache) SaveUserCache(_ context.Context, id uuid.UUID, meta []models.Metadata) error {
+	f.cache[id] = slices.Clone(meta)
+	return nil
+}
+
+func (f *fakeCache) GetUserCache(_ context.Context, id uuid.UUID) ([]models.Metadata, error) {
+	meta, ok := f.cache[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return meta, nil
+}
+
+// newTestController creates controller with given metadata repository and empty cache.
+func newTestController(metaRepo *fakeMetaRepo) (*DocumentsController, *fakeCache) {
+	cache := &fakeCache{cache: make(map[uuid.UUID][]models.Metadata)}
+
+	ctrl := New(Settings{
+		MetaRepo:        metaRepo,
+		Cache:           cache,
+		ShareLinks:      sharelink.New("secret"),
+		ShareLinkTTL:    time.Hour,
+		ShareLinkMaxTTL: time.Hour,
+	})
+
+	return ctrl, cache
+}
+
+// newDoc returns metadata of ready JSON document with given name owned by ownerID.
+func newDoc(name string, ownerID uuid.UUID) models.Metadata {
+	id := uuid.New()
+	return models.Metadata{
+		ID:      &id,
+		OwnerID: &ownerID,
+		Name:    name,
+		Mime:    "application/json",
+		JSON:    "{}",
+		Version: 1,
+		Status:  models.MetadataStatusReady,
+	}
+}
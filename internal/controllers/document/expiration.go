@@ -0,0 +1,51 @@
+package docctrl
+
+import (
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// checkExpiration checks that expiration time of document, if set, is in the future.
+// Expiration time is converted to UTC, as it is stored without time zone.
+// Zero time is allowed, it clears expiration time of existing document.
+// Returns ErrWrongExpiration if document is already expired.
+func checkExpiration(meta *models.Metadata) error {
+	if meta.ExpiresAt == nil {
+		return nil
+	}
+
+	if meta.ExpiresAt.IsZero() {
+		meta.ExpiresAt = &time.Time{}
+		return nil
+	}
+
+	expiresAt := meta.ExpiresAt.UTC()
+	if !expiresAt.After(time.Now()) {
+		return apperrors.ErrWrongExpiration
+	}
+	meta.ExpiresAt = &expiresAt
+
+	return nil
+}
+
+// expired reports whether document is expired at given time.
+func expired(meta models.Metadata, now time.Time) bool {
+	return meta.ExpiresAt != nil && !meta.ExpiresAt.After(now)
+}
+
+// removeExpired returns documents that are not expired yet.
+// Cached documents can expire before they are removed by expirer.
+func removeExpired(metadata []models.Metadata) []models.Metadata {
+	now := time.Now()
+
+	var result []models.Metadata
+	for _, meta := range metadata {
+		if !expired(meta, now) {
+			result = append(result, meta)
+		}
+	}
+
+	return result
+}
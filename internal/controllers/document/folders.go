@@ -0,0 +1,127 @@
+package docctrl
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// CreateFolder creates folder of user.
+// Folder without parent or with nil UUID parent is created in the root folder.
+// Returns ErrWrongFolderOptions if name is invalid or parent folder does not exist.
+// Returns ErrFolderExists if parent folder already has folder with the same name.
+// Returns created folder if create was successful.
+func (c *DocumentsController) CreateFolder(
+	ctx context.Context,
+	userID uuid.UUID,
+	folder models.Folder,
+) (models.Folder, error) {
+	if !validFolderName(folder.Name) {
+		return models.Folder{}, apperrors.ErrWrongFolderOptions
+	}
+
+	if folder.ParentID != nil && *folder.ParentID == uuid.Nil {
+		folder.ParentID = nil
+	}
+
+	folder.ID = nil
+	folder.OwnerID = &userID
+
+	return c.metaRepo.AddFolder(ctx, folder)
+}
+
+// GetFolders returns all folders of user ordered by path.
+// Returns error if get failed.
+func (c *DocumentsController) GetFolders(ctx context.Context, userID uuid.UUID) ([]models.Folder, error) {
+	return c.metaRepo.GetFolders(ctx, userID)
+}
+
+// UpdateFolder renames folder of user or moves it to another parent folder.
+// Documents of the folder and its subfolders change their paths,
+// so cache of user and of all users these documents are shared with is invalidated.
+// Returns ErrWrongFolderOptions if name is invalid, new parent folder does not exist or is inside the folder.
+// Returns ErrFolderExists if new parent folder already has folder with the same name.
+// Returns changed folder if update was successful.
+func (c *DocumentsController) UpdateFolder(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	patch models.FolderPatch,
+) (models.Folder, error) {
+	if patch.Name != nil && !validFolderName(*patch.Name) {
+		return models.Folder{}, apperrors.ErrWrongFolderOptions
+	}
+
+	folder, err := c.metaRepo.UpdateFolder(ctx, id, userID, patch)
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	// Invalidate cache of owner and grantees
+	grantees, err := c.metaRepo.GetFolderGrantees(ctx, id)
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	for _, granteeID := range append(grantees, userID) {
+		if err = c.cache.InvalidateUserCache(ctx, granteeID); err != nil {
+			return models.Folder{}, err
+		}
+	}
+
+	return folder, nil
+}
+
+// DeleteFolder deletes empty folder of user.
+// Returns ErrNotFound if folder does not exist.
+// Returns ErrFolderNotEmpty if folder has subfolders or documents.
+func (c *DocumentsController) DeleteFolder(ctx context.Context, id, userID uuid.UUID) error {
+	return c.metaRepo.DeleteFolder(ctx, id, userID)
+}
+
+// GetFileInfoByPath returns metadata of the document of user with given path,
+// for example "projects/alpha/report.pdf".
+// If folder has several documents with the same name, the newest one is returned.
+// Returns ErrNotFound if document does not exist.
+func (c *DocumentsController) GetFileInfoByPath(
+	ctx context.Context,
+	userID uuid.UUID,
+	docPath string,
+) (models.Metadata, error) {
+	dir, name := path.Split(path.Clean("/" + docPath))
+	if name == "" {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+
+	return c.metaRepo.GetMetadataByPath(ctx, userID, path.Clean(dir), name)
+}
+
+// checkFolder checks that folder of the document is owned by given user.
+// Nil UUID folder is the root folder, it is replaced with nil.
+// Returns ErrWrongFolderOptions if folder does not exist.
+func (c *DocumentsController) checkFolder(ctx context.Context, meta *models.Metadata, ownerID uuid.UUID) error {
+	if meta.FolderID == nil {
+		return nil
+	}
+
+	if *meta.FolderID == uuid.Nil {
+		meta.FolderID = nil
+		return nil
+	}
+
+	_, err := c.metaRepo.GetFolder(ctx, *meta.FolderID, ownerID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return apperrors.ErrWrongFolderOptions
+	}
+
+	return err
+}
+
+// validFolderName reports whether name can be used as a folder path element.
+func validFolderName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.Contains(name, "/")
+}
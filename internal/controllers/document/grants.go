@@ -0,0 +1,129 @@
+package docctrl
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// GrantAccess grants access of req.Level to the document with given id
+// to users with req.Logins. Level of already granted access is replaced.
+// Empty req.Level is the same as "read".
+// Only owner and users with reshare access can grant access.
+// Users with reshare access can't change access granted by other users.
+// Returns ErrWrongGrantOptions if logins are empty, level is unknown,
+// some user does not exist, is the document owner or has access granted by other user.
+func (c *DocumentsController) GrantAccess(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	req models.GrantsRequest,
+) error {
+	if req.Level == "" {
+		req.Level = models.AccessLevelRead
+	}
+
+	if len(req.Logins) == 0 || accessRank(req.Level) == 0 {
+		return apperrors.ErrWrongGrantOptions
+	}
+
+	meta, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = c.checkAccess(ctx, meta, userID, models.AccessLevelReshare); err != nil {
+		return err
+	}
+
+	users, err := c.metaRepo.GrantAccess(ctx, id, req.Logins, req.Level, grantor(meta, userID))
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache of owner and grantees
+	return c.invalidateDocumentCache(ctx, id, *meta.OwnerID, users...)
+}
+
+// RevokeAccess removes access to the document with given id from users with req.Logins.
+// Unknown logins and users without access are ignored.
+// Owner can revoke any access, users with reshare access can revoke only access they granted.
+// Returns ErrWrongGrantOptions if logins are empty.
+func (c *DocumentsController) RevokeAccess(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	req models.GrantsRequest,
+) error {
+	if len(req.Logins) == 0 {
+		return apperrors.ErrWrongGrantOptions
+	}
+
+	meta, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = c.checkAccess(ctx, meta, userID, models.AccessLevelReshare); err != nil {
+		return err
+	}
+
+	users, err := c.metaRepo.RevokeAccess(ctx, id, req.Logins, grantor(meta, userID))
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache of owner, remaining grantees and users lost access
+	return c.invalidateDocumentCache(ctx, id, *meta.OwnerID, users...)
+}
+
+// grantor returns id of user access is granted on behalf of, or nil if user is the document owner.
+func grantor(meta models.Metadata, userID uuid.UUID) *uuid.UUID {
+	if *meta.OwnerID == userID {
+		return nil
+	}
+
+	return &userID
+}
+
+// checkAccess checks that user has at least given level of access to the document.
+// Owner has full access.
+// Returns ErrAccessDenied if access level is not enough.
+func (c *DocumentsController) checkAccess(
+	ctx context.Context,
+	meta models.Metadata,
+	userID uuid.UUID,
+	level models.AccessLevel,
+) error {
+	if *meta.OwnerID == userID {
+		return nil
+	}
+
+	granted, err := c.metaRepo.GetAccessLevel(ctx, *meta.ID, userID)
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return apperrors.ErrAccessDenied
+	case err != nil:
+		return err
+	}
+
+	if accessRank(granted) < accessRank(level) {
+		return apperrors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+// accessRank returns rank of access level.
+// Higher level includes all lower levels. Unknown level has zero rank.
+func accessRank(level models.AccessLevel) int {
+	levels := []models.AccessLevel{
+		models.AccessLevelRead,
+		models.AccessLevelWrite,
+		models.AccessLevelReshare,
+	}
+
+	return slices.Index(levels, level) + 1
+}
@@ -0,0 +1,239 @@
+package docctrl
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessRank(t *testing.T) {
+	assert.Less(t, accessRank(models.AccessLevelRead), accessRank(models.AccessLevelWrite))
+	assert.Less(t, accessRank(models.AccessLevelWrite), accessRank(models.AccessLevelReshare))
+	assert.Positive(t, accessRank(models.AccessLevelRead))
+	assert.Zero(t, accessRank("admin"))
+	assert.Zero(t, accessRank(""))
+}
+
+func TestDocumentsController_checkAccess(t *testing.T) {
+	ownerID := uuid.New()
+	readerID := uuid.New()
+	writerID := uuid.New()
+	doc := newDoc("doc.json", ownerID)
+
+	metaRepo := newFakeMetaRepo(doc)
+	metaRepo.grant(*doc.ID, readerID, models.AccessLevelRead)
+	metaRepo.grant(*doc.ID, writerID, models.AccessLevelWrite)
+	ctrl, _ := newTestController(metaRepo)
+
+	type test struct {
+		name    string
+		userID  uuid.UUID
+		level   models.AccessLevel
+		wantErr error
+	}
+	tests := []test{
+		{
+			name:   "owner has full access",
+			userID: ownerID,
+			level:  models.AccessLevelReshare,
+		},
+		{
+			name:   "same level",
+			userID: readerID,
+			level:  models.AccessLevelRead,
+		},
+		{
+			name:   "higher level includes lower",
+			userID: writerID,
+			level:  models.AccessLevelRead,
+		},
+		{
+			name:    "level is not enough",
+			userID:  writerID,
+			level:   models.AccessLevelReshare,
+			wantErr: apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "access is not granted",
+			userID:  uuid.New(),
+			level:   models.AccessLevelRead,
+			wantErr: apperrors.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ctrl.checkAccess(context.Background(), doc, tt.userID, tt.level)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDocumentsController_GrantAccess(t *testing.T) {
+	type test struct {
+		name      string
+		granter   string
+		req       models.GrantsRequest
+		wantErr   error
+		wantLevel models.AccessLevel
+	}
+	tests := []test{
+		{
+			name:      "owner grants read by default",
+			granter:   "owner",
+			req:       models.GrantsRequest{Logins: []string{"guest"}},
+			wantLevel: models.AccessLevelRead,
+		},
+		{
+			name:      "owner changes access granted by other user",
+			granter:   "owner",
+			req:       models.GrantsRequest{Logins: []string{"invited"}, Level: models.AccessLevelWrite},
+			wantLevel: models.AccessLevelWrite,
+		},
+		{
+			name:      "reshare grantee grants access",
+			granter:   "resharer",
+			req:       models.GrantsRequest{Logins: []string{"guest"}, Level: models.AccessLevelReshare},
+			wantLevel: models.AccessLevelReshare,
+		},
+		{
+			name:    "reshare grantee can't change access granted by owner",
+			granter: "resharer",
+			req:     models.GrantsRequest{Logins: []string{"writer"}, Level: models.AccessLevelRead},
+			wantErr: apperrors.ErrWrongGrantOptions,
+		},
+		{
+			name:    "write grantee can't grant access",
+			granter: "writer",
+			req:     models.GrantsRequest{Logins: []string{"guest"}},
+			wantErr: apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "unknown level",
+			granter: "owner",
+			req:     models.GrantsRequest{Logins: []string{"guest"}, Level: "admin"},
+			wantErr: apperrors.ErrWrongGrantOptions,
+		},
+		{
+			name:    "empty logins",
+			granter: "owner",
+			req:     models.GrantsRequest{Level: models.AccessLevelRead},
+			wantErr: apperrors.ErrWrongGrantOptions,
+		},
+		{
+			name:    "owner can't be grantee",
+			granter: "resharer",
+			req:     models.GrantsRequest{Logins: []string{"owner"}},
+			wantErr: apperrors.ErrWrongGrantOptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metaRepo, doc := newGrantsFixture()
+			ctrl, _ := newTestController(metaRepo)
+
+			err := ctrl.GrantAccess(context.Background(), *doc.ID, metaRepo.users[tt.granter], tt.req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			level, err := metaRepo.GetAccessLevel(context.Background(), *doc.ID, metaRepo.users[tt.req.Logins[0]])
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLevel, level)
+		})
+	}
+}
+
+func TestDocumentsController_RevokeAccess(t *testing.T) {
+	type test struct {
+		name        string
+		revoker     string
+		logins      []string
+		wantErr     error
+		wantRevoked []string
+	}
+	tests := []test{
+		{
+			name:        "owner revokes any access",
+			revoker:     "owner",
+			logins:      []string{"writer", "resharer", "invited"},
+			wantRevoked: []string{"writer", "resharer", "invited"},
+		},
+		{
+			name:        "reshare grantee revokes only access they granted",
+			revoker:     "resharer",
+			logins:      []string{"writer", "invited"},
+			wantRevoked: []string{"invited"},
+		},
+		{
+			name:    "write grantee can't revoke access",
+			revoker: "writer",
+			logins:  []string{"invited"},
+			wantErr: apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "empty logins",
+			revoker: "owner",
+			wantErr: apperrors.ErrWrongGrantOptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metaRepo, doc := newGrantsFixture()
+			ctrl, cache := newTestController(metaRepo)
+
+			err := ctrl.RevokeAccess(
+				context.Background(),
+				*doc.ID,
+				metaRepo.users[tt.revoker],
+				models.GrantsRequest{Logins: tt.logins},
+			)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, login := range tt.logins {
+				_, err = metaRepo.GetAccessLevel(context.Background(), *doc.ID, metaRepo.users[login])
+				if slices.Contains(tt.wantRevoked, login) {
+					assert.ErrorIs(t, err, apperrors.ErrNotFound, login)
+					assert.Contains(t, cache.invalidated, metaRepo.users[login], login)
+				} else {
+					assert.NoError(t, err, login)
+				}
+			}
+		})
+	}
+}
+
+// newGrantsFixture returns repository with document of user "owner" shared with "writer" and "resharer"
+// by owner and with "invited" by "resharer". User "guest" has no access.
+func newGrantsFixture() (*fakeMetaRepo, models.Metadata) {
+	metaRepo := newFakeMetaRepo()
+	ownerID := metaRepo.addUser("owner")
+	resharerID := metaRepo.addUser("resharer")
+
+	doc := newDoc("doc.json", ownerID)
+	metaRepo.docs = append(metaRepo.docs, doc)
+
+	metaRepo.grant(*doc.ID, metaRepo.addUser("writer"), models.AccessLevelWrite)
+	metaRepo.grant(*doc.ID, resharerID, models.AccessLevelReshare)
+	metaRepo.setGrant(*doc.ID, metaRepo.addUser("invited"), fakeGrant{
+		level:     models.AccessLevelRead,
+		grantedBy: &resharerID,
+	})
+	metaRepo.addUser("guest")
+
+	return metaRepo, doc
+}
@@ -0,0 +1,210 @@
+package docctrl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// PresignUpload saves metadata of new binary document in pending status and
+// creates presigned URL to upload its file directly to file repository.
+// meta.FileSize is required, uploaded file size and checksums are checked by CompletePresignedUpload.
+// Document stays pending until CompletePresignedUpload is called,
+// not completed uploads are removed by reconciler.
+// Returns ErrPresignNotSupported if file repository can't create presigned URLs.
+// Returns ErrQuotaExceeded if document doesn't fit into storage quota of the user.
+// Returns models.ResponsePresignedURL with document id and upload URL if presign was successful.
+func (c *DocumentsController) PresignUpload(
+	ctx context.Context,
+	userID uuid.UUID,
+	meta models.Metadata,
+) (models.ResponsePresignedURL, error) {
+	if c.presigner == nil {
+		return models.ResponsePresignedURL{}, apperrors.ErrPresignNotSupported
+	}
+
+	if !meta.File || meta.FileSize < 0 {
+		return models.ResponsePresignedURL{}, apperrors.ErrWrongMetadata
+	}
+
+	if err := checkExpiration(&meta); err != nil {
+		return models.ResponsePresignedURL{}, err
+	}
+
+	if err := checkTags(&meta); err != nil {
+		return models.ResponsePresignedURL{}, err
+	}
+
+	if err := c.checkFolder(ctx, &meta, userID); err != nil {
+		return models.ResponsePresignedURL{}, err
+	}
+
+	meta.OwnerID = &userID
+	meta.Status = models.MetadataStatusPending
+
+	quota, err := c.getQuota(ctx, userID)
+	if err != nil {
+		return models.ResponsePresignedURL{}, err
+	}
+
+	// Save pending metadata to repository
+	id, err := c.metaRepo.UploadMetadata(ctx, meta, quota)
+	if err != nil {
+		return models.ResponsePresignedURL{}, err
+	}
+
+	meta.ID = &id
+	meta.Version = 1
+
+	resp, err := c.presign(ctx, meta, http.MethodPut, c.presigner.PresignUpload)
+	if err != nil {
+		c.compensateUpload(ctx, meta)
+		return models.ResponsePresignedURL{}, err
+	}
+
+	return resp, nil
+}
+
+// CompletePresignedUpload makes document uploaded with presigned URL ready.
+// Only the uploader can complete the upload.
+// Uploaded file is copied from staging object to file repository the same way as uploaded through the server,
+// so its size and checksums are checked, file is deduplicated and its text is extracted.
+// Staging object is removed after completion, so it can't be changed later with the same URL.
+// Returns ErrFileNotUploaded if file is not uploaded yet.
+// Returns ErrFileSizeMismatch if uploaded file size doesn't match metadata
+// and ErrQuotaExceeded if it doesn't fit into quota anymore,
+// uploaded file is removed then, so it can be uploaded again.
+// Returns metadata of completed version if complete was successful.
+func (c *DocumentsController) CompletePresignedUpload(
+	ctx context.Context,
+	id, userID uuid.UUID,
+) (models.Metadata, error) {
+	if c.presigner == nil {
+		return models.Metadata{}, apperrors.ErrPresignNotSupported
+	}
+
+	meta, err := c.metaRepo.GetPendingVersion(ctx, id)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if *meta.OwnerID != userID {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+
+	// Copy uploaded file
+	file, err := c.presigner.OpenStagedFile(ctx, meta)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return models.Metadata{}, apperrors.ErrFileNotUploaded
+	}
+	if err != nil {
+		return models.Metadata{}, err
+	}
+	defer file.Close()
+
+	quota, err := c.getQuota(ctx, userID)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if err = c.uploadFile(ctx, meta, quota, file); err != nil {
+		// Wrong file is removed, so it can be uploaded again
+		if errors.Is(err, apperrors.ErrFileSizeMismatch) || errors.Is(err, apperrors.ErrChecksumMismatch) ||
+			errors.Is(err, apperrors.ErrQuotaExceeded) {
+			c.removePresignedFile(ctx, meta)
+		}
+		return models.Metadata{}, err
+	}
+
+	if err = c.presigner.DeleteStagedFile(ctx, meta); err != nil {
+		return models.Metadata{}, err
+	}
+
+	// Mark version as current
+	if err = c.metaRepo.PromoteVersion(ctx, meta, nil); err != nil {
+		return models.Metadata{}, err
+	}
+
+	// Invalidate cache of owner and grantees
+	if err = c.invalidateDocumentCache(ctx, id, userID); err != nil {
+		return models.Metadata{}, err
+	}
+
+	return meta, nil
+}
+
+// removePresignedFile removes staged and partially copied file of presigned upload.
+// Errors are only logged.
+func (c *DocumentsController) removePresignedFile(ctx context.Context, meta models.Metadata) {
+	if err := c.presigner.DeleteStagedFile(ctx, meta); err != nil {
+		slog.Error(
+			"Error while removing staged file of presigned upload",
+			slog.String("id", meta.ID.String()),
+			slog.Int("version", meta.Version),
+			slog.Any("err", err),
+		)
+	}
+
+	if err := c.fileRepo.DeleteFile(ctx, meta); err != nil {
+		slog.Error(
+			"Error while removing file of presigned upload",
+			slog.String("id", meta.ID.String()),
+			slog.Int("version", meta.Version),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// PresignDownload creates presigned URL to download file of the document directly
+// from file repository.
+// Document must be owned by user or shared with user.
+// Returns ErrPresignNotSupported if file repository can't create presigned URLs.
+// Returns ErrNotFound if document has no file.
+func (c *DocumentsController) PresignDownload(
+	ctx context.Context,
+	id, userID uuid.UUID,
+) (models.ResponsePresignedURL, error) {
+	if c.presigner == nil {
+		return models.ResponsePresignedURL{}, apperrors.ErrPresignNotSupported
+	}
+
+	meta, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return models.ResponsePresignedURL{}, err
+	}
+
+	if !meta.File {
+		return models.ResponsePresignedURL{}, apperrors.ErrNotFound
+	}
+
+	return c.presign(ctx, meta, http.MethodGet, c.presigner.PresignDownload)
+}
+
+// presign creates presigned URL for document file with given presign function.
+func (c *DocumentsController) presign(
+	ctx context.Context,
+	meta models.Metadata,
+	method string,
+	presign func(ctx context.Context, meta models.Metadata, ttl time.Duration) (string, error),
+) (models.ResponsePresignedURL, error) {
+	expiresAt := time.Now().UTC().Add(c.presignTTL).Truncate(time.Second)
+
+	url, err := presign(ctx, meta, c.presignTTL)
+	if err != nil {
+		return models.ResponsePresignedURL{}, err
+	}
+
+	return models.ResponsePresignedURL{
+		ID:        meta.ID.String(),
+		Version:   meta.Version,
+		Method:    method,
+		URL:       url,
+		ExpiresAt: expiresAt.Format(time.DateTime),
+	}, nil
+}
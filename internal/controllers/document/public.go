@@ -0,0 +1,166 @@
+package docctrl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/sharelink"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// GetPublicFileInfo get metadata of public document with given id.
+// Can be used without authentication.
+// Returns ErrNotFound if document does not exist or is not public.
+func (c *DocumentsController) GetPublicFileInfo(ctx context.Context, id uuid.UUID) (models.Metadata, error) {
+	meta, err := c.metaRepo.GetMetadataByID(ctx, id)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if !meta.Public {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+
+	return meta, nil
+}
+
+// CreateShareLink creates signed share link to the document with given id.
+// Only owner and users with reshare access can create share links.
+// If req.TTL is empty, default share link TTL is used.
+// If req.Password is not empty, it is required to open the link.
+// If req.MaxDownloads is not zero, link can be downloaded only given number of times.
+// Returns ErrWrongShareLinkOptions if TTL is invalid or exceeds maximum share link TTL,
+// or downloads limit is negative.
+func (c *DocumentsController) CreateShareLink(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	req models.ShareLinkRequest,
+) (models.ResponseShareLink, error) {
+	ttl := c.shareLinkTTL
+	if req.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 || ttl > c.shareLinkMaxTTL {
+			return models.ResponseShareLink{}, apperrors.ErrWrongShareLinkOptions
+		}
+	}
+
+	if req.MaxDownloads < 0 {
+		return models.ResponseShareLink{}, apperrors.ErrWrongShareLinkOptions
+	}
+
+	meta, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return models.ResponseShareLink{}, err
+	}
+
+	if err = c.checkAccess(ctx, meta, userID, models.AccessLevelReshare); err != nil {
+		return models.ResponseShareLink{}, err
+	}
+
+	link := models.ShareLink{
+		MetaID:       id,
+		CreatedBy:    userID,
+		MaxDownloads: req.MaxDownloads,
+		ExpiresAt:    time.Now().UTC().Add(ttl).Truncate(time.Second),
+	}
+
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return models.ResponseShareLink{}, err
+		}
+		link.PasswordHash = string(hash)
+	}
+
+	link.ID, err = c.metaRepo.AddShareLink(ctx, link)
+	if err != nil {
+		return models.ResponseShareLink{}, err
+	}
+
+	token := c.shareLinks.Sign(sharelink.Link{
+		ID:        link.ID,
+		ExpiresAt: link.ExpiresAt.Unix(),
+	})
+
+	return models.ResponseShareLink{
+		Token:     token,
+		URL:       "/api/share/" + token,
+		ExpiresAt: link.ExpiresAt.Format(time.DateTime),
+	}, nil
+}
+
+// RevokeShareLinks revokes share links to the document with given id.
+// Owner revokes all links, users with reshare access revoke only links they created.
+// Returns ErrAccessDenied if user has no reshare access.
+func (c *DocumentsController) RevokeShareLinks(ctx context.Context, id, userID uuid.UUID) error {
+	meta, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = c.checkAccess(ctx, meta, userID, models.AccessLevelReshare); err != nil {
+		return err
+	}
+
+	return c.metaRepo.RemoveShareLinks(ctx, id, grantor(meta, userID))
+}
+
+// OpenShareLink get metadata of the document share link token points to.
+// Can be used without authentication.
+// Link works only while its creator is the owner or has reshare access to the document.
+// Downloads counter is not changed, CountShareLinkDownload must be called before document is served.
+// Returns ErrInvalidShareLink or ErrShareLinkExpired if token is invalid or link creator lost access
+// and ErrWrongSharePassword if password is wrong.
+func (c *DocumentsController) OpenShareLink(
+	ctx context.Context,
+	token, password string,
+) (models.Metadata, error) {
+	parsed, err := c.shareLinks.Parse(token)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	link, err := c.metaRepo.GetShareLink(ctx, parsed.ID)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if link.PasswordHash != "" {
+		err = bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password))
+		if err != nil {
+			return models.Metadata{}, apperrors.ErrWrongSharePassword
+		}
+	}
+
+	meta, err := c.metaRepo.GetMetadataByID(ctx, link.MetaID)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	err = c.checkAccess(ctx, meta, link.CreatedBy, models.AccessLevelReshare)
+	if errors.Is(err, apperrors.ErrAccessDenied) {
+		return models.Metadata{}, apperrors.ErrInvalidShareLink
+	}
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	return meta, nil
+}
+
+// CountShareLinkDownload increments downloads counter of share link opened with OpenShareLink.
+// Returns ErrInvalidShareLink or ErrShareLinkExpired if token is invalid
+// and ErrShareLinkExhausted if downloads limit is reached.
+func (c *DocumentsController) CountShareLinkDownload(ctx context.Context, token string) error {
+	parsed, err := c.shareLinks.Parse(token)
+	if err != nil {
+		return err
+	}
+
+	return c.metaRepo.CountShareLinkDownload(ctx, parsed.ID)
+}
@@ -0,0 +1,117 @@
+package docctrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentsController_OpenShareLink(t *testing.T) {
+	type test struct {
+		name    string
+		creator string
+		revoke  string
+		wantErr error
+	}
+	tests := []test{
+		{
+			name:    "link of owner",
+			creator: "owner",
+		},
+		{
+			name:    "link of reshare grantee",
+			creator: "resharer",
+		},
+		{
+			name:    "creator lost access",
+			creator: "resharer",
+			revoke:  "resharer",
+			wantErr: apperrors.ErrInvalidShareLink,
+		},
+		{
+			name:    "revoking other user access keeps link",
+			creator: "resharer",
+			revoke:  "writer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			metaRepo, doc := newGrantsFixture()
+			ctrl, _ := newTestController(metaRepo)
+
+			link, err := ctrl.CreateShareLink(ctx, *doc.ID, metaRepo.users[tt.creator], models.ShareLinkRequest{})
+			require.NoError(t, err)
+
+			if tt.revoke != "" {
+				req := models.GrantsRequest{Logins: []string{tt.revoke}}
+				require.NoError(t, ctrl.RevokeAccess(ctx, *doc.ID, metaRepo.users["owner"], req))
+			}
+
+			meta, err := ctrl.OpenShareLink(ctx, link.Token, "")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, *doc.ID, *meta.ID)
+		})
+	}
+}
+
+func TestDocumentsController_RevokeShareLinks(t *testing.T) {
+	type test struct {
+		name      string
+		revoker   string
+		wantErr   error
+		wantLinks []string
+	}
+	tests := []test{
+		{
+			name:    "owner revokes all links",
+			revoker: "owner",
+		},
+		{
+			name:      "reshare grantee revokes only own links",
+			revoker:   "resharer",
+			wantLinks: []string{"owner"},
+		},
+		{
+			name:      "write grantee can't revoke links",
+			revoker:   "writer",
+			wantErr:   apperrors.ErrAccessDenied,
+			wantLinks: []string{"owner", "resharer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			metaRepo, doc := newGrantsFixture()
+			ctrl, _ := newTestController(metaRepo)
+
+			links := make(map[string]string)
+			for _, creator := range []string{"owner", "resharer"} {
+				link, err := ctrl.CreateShareLink(ctx, *doc.ID, metaRepo.users[creator], models.ShareLinkRequest{})
+				require.NoError(t, err)
+				links[creator] = link.Token
+			}
+
+			err := ctrl.RevokeShareLinks(ctx, *doc.ID, metaRepo.users[tt.revoker])
+			require.ErrorIs(t, err, tt.wantErr)
+
+			var working []string
+			for creator, token := range links {
+				if _, err = ctrl.OpenShareLink(ctx, token, ""); err == nil {
+					working = append(working, creator)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantLinks, working)
+		})
+	}
+}
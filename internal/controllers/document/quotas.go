@@ -0,0 +1,140 @@
+package docctrl
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// GetUsage returns storage usage and limits of user.
+// Returns error if get failed.
+func (c *DocumentsController) GetUsage(ctx context.Context, userID uuid.UUID) (models.Usage, error) {
+	quota, err := c.getQuota(ctx, userID)
+	if err != nil {
+		return models.Usage{}, err
+	}
+
+	usage, err := c.metaRepo.GetUsage(ctx, userID)
+	if err != nil {
+		return models.Usage{}, err
+	}
+	usage.Quota = quota
+
+	return usage, nil
+}
+
+// GetUserUsage returns storage usage and limits of user with given login.
+// Used by administrators.
+// Returns ErrNotFound if user does not exist.
+func (c *DocumentsController) GetUserUsage(ctx context.Context, login string) (models.Usage, error) {
+	userID, err := c.getUserID(ctx, login)
+	if err != nil {
+		return models.Usage{}, err
+	}
+
+	return c.GetUsage(ctx, userID)
+}
+
+// SetUserQuota sets storage limits of user with given login.
+// Nil limits are reset to default limits, zero limit means no limit.
+// Used by administrators.
+// Returns ErrWrongQuotaOptions if limits are negative.
+// Returns ErrNotFound if user does not exist.
+// Returns storage usage and new limits of user if set was successful.
+func (c *DocumentsController) SetUserQuota(
+	ctx context.Context,
+	login string,
+	req models.QuotaRequest,
+) (models.Usage, error) {
+	if req.MaxBytes != nil && *req.MaxBytes < 0 || req.MaxDocuments != nil && *req.MaxDocuments < 0 {
+		return models.Usage{}, apperrors.ErrWrongQuotaOptions
+	}
+
+	userID, err := c.getUserID(ctx, login)
+	if err != nil {
+		return models.Usage{}, err
+	}
+
+	if err = c.metaRepo.SetQuota(ctx, userID, req); err != nil {
+		return models.Usage{}, err
+	}
+
+	return c.GetUsage(ctx, userID)
+}
+
+// getUserID returns id of user with given login.
+// Returns ErrNotFound if user does not exist.
+func (c *DocumentsController) getUserID(ctx context.Context, login string) (uuid.UUID, error) {
+	user, err := c.userRepo.GetUserByLogin(ctx, login)
+	if errors.Is(err, apperrors.ErrWrongCredentials) {
+		return uuid.Nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return user.ID, nil
+}
+
+// getQuota returns storage limits of user.
+// Default limits are used for limits not set for the user.
+func (c *DocumentsController) getQuota(ctx context.Context, userID uuid.UUID) (models.Quota, error) {
+	quota := c.defaultQuota
+
+	userQuota, err := c.metaRepo.GetQuota(ctx, userID)
+	if err != nil {
+		return models.Quota{}, err
+	}
+
+	if userQuota.MaxBytes != nil {
+		quota.MaxBytes = *userQuota.MaxBytes
+	}
+
+	if userQuota.MaxDocuments != nil {
+		quota.MaxDocuments = *userQuota.MaxDocuments
+	}
+
+	return quota, nil
+}
+
+// limitToQuota returns reader of file that fails with ErrQuotaExceeded
+// if file doesn't fit into remaining storage quota of user.
+// Used for files of unknown size, size of other files is checked before upload.
+func (c *DocumentsController) limitToQuota(
+	ctx context.Context,
+	userID uuid.UUID,
+	quota models.Quota,
+	file io.Reader,
+) (io.Reader, error) {
+	if quota.MaxBytes <= 0 {
+		return file, nil
+	}
+
+	usage, err := c.metaRepo.GetUsage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quotaReader{r: file, remaining: quota.MaxBytes - usage.Bytes}, nil
+}
+
+// quotaReader is a wrapper for io.Reader that fails with ErrQuotaExceeded
+// if more than remaining bytes are read.
+type quotaReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// Read implements io.Reader interface.
+func (q *quotaReader) Read(p []byte) (int, error) {
+	n, err := q.r.Read(p)
+	q.remaining -= int64(n)
+	if q.remaining < 0 {
+		return n, apperrors.ErrQuotaExceeded
+	}
+	return n, err
+}
@@ -0,0 +1,159 @@
+package docctrl
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentsController_getQuota(t *testing.T) {
+	maxBytes := int64(100)
+	zeroBytes := int64(0)
+	maxDocuments := 5
+
+	type test struct {
+		name      string
+		userQuota models.QuotaRequest
+		want      models.Quota
+	}
+	tests := []test{
+		{
+			name: "default limits",
+			want: models.Quota{MaxBytes: 1000, MaxDocuments: 10},
+		},
+		{
+			name:      "user limits",
+			userQuota: models.QuotaRequest{MaxBytes: &maxBytes, MaxDocuments: &maxDocuments},
+			want:      models.Quota{MaxBytes: 100, MaxDocuments: 5},
+		},
+		{
+			name:      "nil limit is reset to default",
+			userQuota: models.QuotaRequest{MaxDocuments: &maxDocuments},
+			want:      models.Quota{MaxBytes: 1000, MaxDocuments: 5},
+		},
+		{
+			name:      "zero user limit overrides default",
+			userQuota: models.QuotaRequest{MaxBytes: &zeroBytes},
+			want:      models.Quota{MaxBytes: 0, MaxDocuments: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			metaRepo := newFakeMetaRepo()
+			metaRepo.quotas[userID] = tt.userQuota
+
+			ctrl, _ := newTestController(metaRepo)
+			ctrl.defaultQuota = models.Quota{MaxBytes: 1000, MaxDocuments: 10}
+
+			quota, err := ctrl.getQuota(context.Background(), userID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, quota)
+		})
+	}
+}
+
+func TestDocumentsController_limitToQuota(t *testing.T) {
+	type test struct {
+		name     string
+		maxBytes int64
+		used     int64
+		size     int
+		wantErr  error
+	}
+	tests := []test{
+		{
+			name:     "zero limit means unlimited",
+			maxBytes: 0,
+			used:     1000,
+			size:     100,
+		},
+		{
+			name:     "file fits",
+			maxBytes: 100,
+			used:     60,
+			size:     40,
+		},
+		{
+			name:     "file doesn't fit",
+			maxBytes: 100,
+			used:     60,
+			size:     41,
+			wantErr:  apperrors.ErrQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			metaRepo := newFakeMetaRepo()
+			metaRepo.usage[userID] = models.Usage{Bytes: tt.used}
+			ctrl, _ := newTestController(metaRepo)
+
+			file, err := ctrl.limitToQuota(
+				context.Background(),
+				userID,
+				models.Quota{MaxBytes: tt.maxBytes},
+				strings.NewReader(strings.Repeat("a", tt.size)),
+			)
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(file)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, data, tt.size)
+		})
+	}
+}
+
+func TestQuotaReader_Read(t *testing.T) {
+	type test struct {
+		name      string
+		data      string
+		remaining int64
+		wantErr   error
+	}
+	tests := []test{
+		{
+			name:      "fits exactly",
+			data:      "abcd",
+			remaining: 4,
+		},
+		{
+			name:      "exceeded",
+			data:      "abcde",
+			remaining: 4,
+			wantErr:   apperrors.ErrQuotaExceeded,
+		},
+		{
+			name:      "empty file without quota left",
+			data:      "",
+			remaining: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &quotaReader{r: strings.NewReader(tt.data), remaining: tt.remaining}
+
+			data, err := io.ReadAll(r)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.data, string(data))
+		})
+	}
+}
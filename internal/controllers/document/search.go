@@ -0,0 +1,106 @@
+package docctrl
+
+import (
+	"bytes"
+	"context"
+	"html"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultSearchLimit is the number of found documents returned if limit is not set.
+	defaultSearchLimit = 20
+	// maxSearchLimit is the maximum number of found documents returned at once.
+	maxSearchLimit = 100
+	// maxTextSize is the maximum size of file beginning used for full-text search.
+	maxTextSize = 1 << 20
+)
+
+var (
+	// htmlSkipped matches html comments, scripts and styles.
+	htmlSkipped = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)\s*>|<!--.*?-->`)
+	// htmlTag matches html tags.
+	htmlTag = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// SearchDocuments searches documents visible to user by names, tags, JSON data
+// and text of txt, md, csv and html files.
+// Query uses web search syntax: quoted phrases, "or" and "-" to exclude words.
+// If limit is zero, default limit is used.
+// Returns ErrWrongSearchOptions if query is empty, limit is too big or limit or offset is negative.
+// Returns page of found documents, most relevant first, with snippets of matching text if search was successful.
+func (c *DocumentsController) SearchDocuments(
+	ctx context.Context,
+	userID uuid.UUID,
+	query string,
+	limit, offset int,
+) ([]models.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || limit < 0 || limit > maxSearchLimit || offset < 0 {
+		return nil, apperrors.ErrWrongSearchOptions
+	}
+
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	return c.metaRepo.SearchMetadata(ctx, userID, query, limit, offset)
+}
+
+// textBuffer keeps first maxTextSize bytes written to it.
+type textBuffer struct {
+	bytes.Buffer
+}
+
+// Write writes p to buffer while it is not full. It never fails.
+func (b *textBuffer) Write(p []byte) (int, error) {
+	if rest := maxTextSize - b.Len(); rest > 0 {
+		b.Buffer.Write(p[:min(len(p), rest)])
+	}
+
+	return len(p), nil
+}
+
+// isTextLike reports whether text of document file can be searched.
+// Plain text, markdown, csv and html files are detected by MIME type or by name extension.
+func isTextLike(meta models.Metadata) bool {
+	mediaType, _, _ := mime.ParseMediaType(meta.Mime)
+
+	switch mediaType {
+	case "text/plain", "text/markdown", "text/x-markdown", "text/csv", "text/html":
+		return true
+	}
+
+	switch strings.ToLower(path.Ext(meta.Name)) {
+	case ".txt", ".md", ".markdown", ".csv", ".html", ".htm":
+		return true
+	}
+
+	return false
+}
+
+// extractText returns searchable text of document file beginning.
+// Tags, scripts and styles are removed from html files.
+func extractText(meta models.Metadata, data []byte) string {
+	// File may be cut in the middle of a character, PostgreSQL text can't contain NUL
+	text := strings.ToValidUTF8(string(data), "")
+	text = strings.ReplaceAll(text, "\x00", "")
+
+	mediaType, _, _ := mime.ParseMediaType(meta.Mime)
+	ext := strings.ToLower(path.Ext(meta.Name))
+
+	if mediaType == "text/html" || ext == ".html" || ext == ".htm" {
+		text = htmlSkipped.ReplaceAllString(text, " ")
+		text = htmlTag.ReplaceAllString(text, " ")
+		text = html.UnescapeString(text)
+	}
+
+	return text
+}
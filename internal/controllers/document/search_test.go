@@ -0,0 +1,142 @@
+package docctrl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTextLike(t *testing.T) {
+	type test struct {
+		name string
+		meta models.Metadata
+		want bool
+	}
+	tests := []test{
+		{
+			name: "plain text mime",
+			meta: models.Metadata{Name: "notes", Mime: "text/plain; charset=utf-8"},
+			want: true,
+		},
+		{
+			name: "html mime",
+			meta: models.Metadata{Name: "page", Mime: "text/html"},
+			want: true,
+		},
+		{
+			name: "markdown extension",
+			meta: models.Metadata{Name: "README.MD", Mime: "application/octet-stream"},
+			want: true,
+		},
+		{
+			name: "csv extension without mime",
+			meta: models.Metadata{Name: "table.csv"},
+			want: true,
+		},
+		{
+			name: "binary file",
+			meta: models.Metadata{Name: "image.png", Mime: "image/png"},
+			want: false,
+		},
+		{
+			name: "wrong mime",
+			meta: models.Metadata{Name: "data", Mime: "text/"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isTextLike(tt.meta))
+		})
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	type test struct {
+		name string
+		meta models.Metadata
+		data string
+		want string
+	}
+	tests := []test{
+		{
+			name: "plain text is kept",
+			meta: models.Metadata{Name: "a.txt", Mime: "text/plain"},
+			data: "hello <b>world</b> &amp;",
+			want: "hello <b>world</b> &amp;",
+		},
+		{
+			name: "invalid utf-8 and nul are removed",
+			meta: models.Metadata{Name: "a.txt", Mime: "text/plain"},
+			data: "he\x00llo \xd0",
+			want: "hello ",
+		},
+		{
+			name: "html tags are removed",
+			meta: models.Metadata{Name: "a.html"},
+			data: "<p>hello <b>world</b></p>",
+			want: " hello  world  ",
+		},
+		{
+			name: "html scripts, styles and comments are removed",
+			meta: models.Metadata{Name: "a", Mime: "text/html"},
+			data: "<script>alert(1)</script>a<style>p{}</style>b<!-- c -->",
+			want: " a b ",
+		},
+		{
+			name: "html entities are unescaped",
+			meta: models.Metadata{Name: "a.htm"},
+			data: "a &amp; &lt;b&gt;",
+			want: "a & <b>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractText(tt.meta, []byte(tt.data)))
+		})
+	}
+}
+
+func TestTextBuffer_Write(t *testing.T) {
+	type test struct {
+		name    string
+		writes  []int
+		wantLen int
+	}
+	tests := []test{
+		{
+			name:    "small writes",
+			writes:  []int{10, 20},
+			wantLen: 30,
+		},
+		{
+			name:    "write is cut",
+			writes:  []int{maxTextSize - 10, 20},
+			wantLen: maxTextSize,
+		},
+		{
+			name:    "writes to full buffer are ignored",
+			writes:  []int{maxTextSize, 10},
+			wantLen: maxTextSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf textBuffer
+
+			for _, size := range tt.writes {
+				n, err := buf.Write(bytes.Repeat([]byte("a"), size))
+				require.NoError(t, err)
+				assert.Equal(t, size, n)
+			}
+
+			assert.Equal(t, tt.wantLen, buf.Len())
+		})
+	}
+}
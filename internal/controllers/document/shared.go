@@ -0,0 +1,66 @@
+package docctrl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/docfilter"
+	"github.com/FlutterDizaster/file-server/internal/docfilter/filters"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// ScopeOwn lists documents owned by user.
+	ScopeOwn = "own"
+	// ScopeShared lists documents shared with user by other users.
+	ScopeShared = "shared"
+	// ScopeAll lists both own and shared documents.
+	ScopeAll = "all"
+)
+
+// addScopeFilter adds filter selecting documents of given list scope.
+// Empty scope is the same as ScopeOwn.
+// Returns ErrWrongFilterOptions if scope is unknown.
+func addScopeFilter(filter *docfilter.DocumentsFilter, userID uuid.UUID, scope string) error {
+	owner := models.FilterClause{
+		Key:   string(filters.FilterKeyOwner),
+		Value: userID.String(),
+	}
+
+	switch strings.ToLower(scope) {
+	case "", ScopeOwn:
+	case ScopeShared:
+		owner.Not = true
+	case ScopeAll:
+		return nil
+	default:
+		return apperrors.ErrWrongFilterOptions
+	}
+
+	return filter.AddFilterClauses(docfilter.OperatorAnd, []models.FilterClause{owner})
+}
+
+// invalidateDocumentCache invalidates cache of document owner
+// and of all users the document is shared with.
+// Caches of additional users are invalidated too.
+func (c *DocumentsController) invalidateDocumentCache(
+	ctx context.Context,
+	id, ownerID uuid.UUID,
+	users ...uuid.UUID,
+) error {
+	grantees, err := c.metaRepo.GetGrantees(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	users = append(users, ownerID)
+	for _, userID := range append(users, grantees...) {
+		if err = c.cache.InvalidateUserCache(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
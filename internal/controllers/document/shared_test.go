@@ -0,0 +1,140 @@
+package docctrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/docfilter"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddScopeFilter(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+	docs := []models.Metadata{
+		newDoc("own.json", userID),
+		newDoc("shared.json", otherID),
+	}
+
+	type test struct {
+		name    string
+		scope   string
+		want    []string
+		wantErr error
+	}
+	tests := []test{
+		{
+			name:  "default scope",
+			scope: "",
+			want:  []string{"own.json"},
+		},
+		{
+			name:  "own",
+			scope: ScopeOwn,
+			want:  []string{"own.json"},
+		},
+		{
+			name:  "shared",
+			scope: ScopeShared,
+			want:  []string{"shared.json"},
+		},
+		{
+			name:  "all in upper case",
+			scope: "ALL",
+			want:  []string{"own.json", "shared.json"},
+		},
+		{
+			name:    "unknown scope",
+			scope:   "public",
+			wantErr: apperrors.ErrWrongFilterOptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := docfilter.New(10, 0)
+
+			err := addScopeFilter(filter, userID, tt.scope)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, meta := range filter.FilterData(docs) {
+				names = append(names, meta.Name)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
+
+func TestDocumentsController_GetFileInfo(t *testing.T) {
+	ownerID := uuid.New()
+	granteeID := uuid.New()
+	doc := newDoc("doc.json", ownerID)
+
+	metaRepo := newFakeMetaRepo(doc)
+	metaRepo.grant(*doc.ID, granteeID, models.AccessLevelRead)
+	ctrl, _ := newTestController(metaRepo)
+
+	type test struct {
+		name    string
+		userID  uuid.UUID
+		wantErr error
+	}
+	tests := []test{
+		{
+			name:   "owner",
+			userID: ownerID,
+		},
+		{
+			name:   "grantee",
+			userID: granteeID,
+		},
+		{
+			name:    "other user",
+			userID:  uuid.New(),
+			wantErr: apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := ctrl.GetFileInfo(context.Background(), *doc.ID, tt.userID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, *doc.ID, *meta.ID)
+		})
+	}
+}
+
+func TestDocumentsController_invalidateDocumentCache(t *testing.T) {
+	ownerID := uuid.New()
+	granteeID := uuid.New()
+	revokedID := uuid.New()
+	doc := newDoc("doc.json", ownerID)
+
+	metaRepo := newFakeMetaRepo(doc)
+	metaRepo.grant(*doc.ID, granteeID, models.AccessLevelWrite)
+	ctrl, cache := newTestController(metaRepo)
+
+	// Grantee cache is filled with the shared document
+	_, err := ctrl.GetFileInfo(context.Background(), *doc.ID, granteeID)
+	require.NoError(t, err)
+	require.Contains(t, cache.cache, granteeID)
+
+	// Users who lost access are not grantees anymore and are passed explicitly
+	require.NoError(t, ctrl.invalidateDocumentCache(context.Background(), *doc.ID, ownerID, revokedID))
+
+	assert.ElementsMatch(t, []uuid.UUID{ownerID, granteeID, revokedID}, cache.invalidated)
+	assert.NotContains(t, cache.cache, granteeID)
+}
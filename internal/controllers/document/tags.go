@@ -0,0 +1,48 @@
+package docctrl
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+const (
+	// maxTags is the maximum number of tags of a document.
+	maxTags = 64
+	// maxAttributes is the maximum number of attributes of a document.
+	maxAttributes = 64
+)
+
+// checkTags checks tags and attributes of document.
+// Tags are trimmed, sorted and deduplicated.
+// Returns ErrWrongMetadata if there are too many tags or attributes,
+// or if tag or attribute key is empty.
+func checkTags(meta *models.Metadata) error {
+	if meta.Tags != nil {
+		tags := make([]string, 0, len(meta.Tags))
+		for _, tag := range meta.Tags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				return apperrors.ErrWrongMetadata
+			}
+			tags = append(tags, tag)
+		}
+
+		slices.Sort(tags)
+		meta.Tags = slices.Compact(tags)
+	}
+
+	if len(meta.Tags) > maxTags || len(meta.Attributes) > maxAttributes {
+		return apperrors.ErrWrongMetadata
+	}
+
+	for key := range meta.Attributes {
+		if strings.TrimSpace(key) == "" {
+			return apperrors.ErrWrongMetadata
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package docctrl
+
+import (
+	"context"
+	"slices"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/docpurge"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// GetTrash returns documents of user moved to trash, most recently deleted first.
+// Returns error if get failed.
+func (c *DocumentsController) GetTrash(ctx context.Context, userID uuid.UUID) ([]models.Metadata, error) {
+	return c.metaRepo.GetTrash(ctx, userID)
+}
+
+// RestoreFile moves document back from trash.
+// Only owner can restore document.
+// Returns ErrNotFound if document is not in trash of user.
+func (c *DocumentsController) RestoreFile(ctx context.Context, id, userID uuid.UUID) error {
+	if err := c.metaRepo.RestoreMetadata(ctx, id, userID); err != nil {
+		return err
+	}
+
+	// Invalidate cache of owner and grantees
+	return c.invalidateDocumentCache(ctx, id, userID)
+}
+
+// PurgeFile permanently removes document from trash with files of all its versions,
+// so it is not counted in storage quota of user anymore.
+// Only owner can purge document.
+// Returns ErrNotFound if document is not in trash of user.
+func (c *DocumentsController) PurgeFile(ctx context.Context, id, userID uuid.UUID) error {
+	trash, err := c.metaRepo.GetTrash(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(trash, func(meta models.Metadata) bool {
+		return *meta.ID == id
+	})
+	if i < 0 {
+		return apperrors.ErrNotFound
+	}
+
+	return docpurge.Purge(ctx, c.fileRepo, c.metaRepo, trash[i])
+}
@@ -0,0 +1,173 @@
+package docctrl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/etag"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// maxJSONSize is the maximum size of JSON document content, the same as maximum size of upload form fields.
+const maxJSONSize = 10 << 20
+
+// UpdateFile changes metadata of the document in place, document id and version are kept.
+// Owner and users with write access can change name, MIME type, JSON data, tags and attributes.
+// Only owner can change public flag and expiration time and move document to another folder.
+// If ifMatch is not empty, document is changed only if its ETag matches ifMatch.
+// Returns ErrPreconditionFailed if ETag does not match.
+// Returns ErrWrongMetadata if name or MIME type is empty or tags or attributes are invalid.
+// Returns ErrWrongExpiration if expiration time is not in the future, zero expiration time clears it.
+// Returns updated metadata if update was successful.
+func (c *DocumentsController) UpdateFile(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	patch models.MetadataPatch,
+	ifMatch string,
+) (models.Metadata, error) {
+	if patch.Name != nil && *patch.Name == "" || patch.Mime != nil && *patch.Mime == "" {
+		return models.Metadata{}, apperrors.ErrWrongMetadata
+	}
+
+	expiration := models.Metadata{ExpiresAt: patch.ExpiresAt}
+	if err := checkExpiration(&expiration); err != nil {
+		return models.Metadata{}, err
+	}
+	patch.ExpiresAt = expiration.ExpiresAt
+
+	labels := models.Metadata{}
+	if patch.Tags != nil {
+		labels.Tags = *patch.Tags
+	}
+	if patch.Attributes != nil {
+		labels.Attributes = *patch.Attributes
+	}
+	if err := checkTags(&labels); err != nil {
+		return models.Metadata{}, err
+	}
+	if patch.Tags != nil {
+		patch.Tags = &labels.Tags
+	}
+
+	// Get current document metadata
+	current, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if err = c.checkAccess(ctx, current, userID, models.AccessLevelWrite); err != nil {
+		return models.Metadata{}, err
+	}
+
+	// Only owner can publish, delete and move document
+	if (patch.Public != nil || patch.ExpiresAt != nil || patch.FolderID != nil) && *current.OwnerID != userID {
+		return models.Metadata{}, apperrors.ErrAccessDenied
+	}
+
+	if patch.FolderID != nil {
+		if err = c.checkFolder(ctx, &models.Metadata{FolderID: patch.FolderID}, userID); err != nil {
+			return models.Metadata{}, err
+		}
+	}
+
+	updated, err := checkPrecondition(current, ifMatch)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if err = c.metaRepo.UpdateMetadata(ctx, id, patch, updated); err != nil {
+		return models.Metadata{}, err
+	}
+
+	// Invalidate cache of owner and grantees
+	if err = c.invalidateDocumentCache(ctx, id, *current.OwnerID); err != nil {
+		return models.Metadata{}, err
+	}
+
+	return c.GetFileInfo(ctx, id, userID)
+}
+
+// ReplaceFile replaces content of the document, document id is kept.
+// Content is uploaded as a new version, the same way as in UploadVersion,
+// name and JSON data of binary document are taken from the current version.
+// Content of JSON document replaces its JSON data and must be valid JSON.
+// If meta.Mime is set, MIME type of binary document is changed.
+// meta.FileSize is size of the content or -1 if it is unknown.
+// If ifMatch is not empty, content is replaced only if ETag of the document matches ifMatch.
+// Returns ErrPreconditionFailed if ETag does not match.
+// Returns ErrWrongMetadata if content of JSON document is not valid JSON or is longer than maxJSONSize.
+// Returns number of uploaded version if replace was successful.
+func (c *DocumentsController) ReplaceFile(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	meta models.Metadata,
+	file io.Reader,
+	ifMatch string,
+) (int, error) {
+	// Get current document metadata
+	current, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Access is checked before content is read
+	if err = c.checkAccess(ctx, current, userID, models.AccessLevelWrite); err != nil {
+		return 0, err
+	}
+
+	// Precondition is checked before upload and again when the version becomes current
+	updated, err := checkPrecondition(current, ifMatch)
+	if err != nil {
+		return 0, err
+	}
+
+	version := models.Metadata{
+		Name:     current.Name,
+		File:     current.File,
+		Mime:     current.Mime,
+		JSON:     current.JSON,
+		FileSize: meta.FileSize,
+	}
+
+	if current.File {
+		if meta.Mime != "" {
+			version.Mime = meta.Mime
+		}
+
+		return c.uploadVersion(ctx, current, version, file, updated)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxJSONSize+1))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) > maxJSONSize || !json.Valid(data) {
+		return 0, apperrors.ErrWrongMetadata
+	}
+
+	version.JSON = models.JSONString(data)
+	version.FileSize = 0
+
+	return c.uploadVersion(ctx, current, version, nil, updated)
+}
+
+// checkPrecondition checks that ETag of the document matches If-Match header value.
+// Returns ErrPreconditionFailed if ETag does not match.
+// Returns update time of the document to guard the change against concurrent updates,
+// or nil if ifMatch is empty.
+func checkPrecondition(meta models.Metadata, ifMatch string) (*time.Time, error) {
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	if !etag.Match(ifMatch, etag.Of(meta)) {
+		return nil, apperrors.ErrPreconditionFailed
+	}
+
+	return &meta.Updated, nil
+}
@@ -0,0 +1,177 @@
+package docctrl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/etag"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentsController_UpdateFile(t *testing.T) {
+	name := "renamed.json"
+	empty := ""
+	public := true
+	expiresAt := time.Now().Add(time.Hour).UTC()
+
+	type test struct {
+		name      string
+		user      string
+		patch     models.MetadataPatch
+		staleETag bool
+		wantErr   error
+		wantMeta  func(meta *models.Metadata)
+	}
+	tests := []test{
+		{
+			name:  "owner changes all fields",
+			user:  "owner",
+			patch: models.MetadataPatch{Name: &name, Public: &public, ExpiresAt: &expiresAt},
+			wantMeta: func(meta *models.Metadata) {
+				meta.Name = name
+				meta.Public = true
+				meta.ExpiresAt = &expiresAt
+			},
+		},
+		{
+			name:  "write grantee renames document",
+			user:  "writer",
+			patch: models.MetadataPatch{Name: &name},
+			wantMeta: func(meta *models.Metadata) {
+				meta.Name = name
+			},
+		},
+		{
+			name:    "write grantee can't publish document",
+			user:    "writer",
+			patch:   models.MetadataPatch{Name: &name, Public: &public},
+			wantErr: apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "write grantee can't set expiration",
+			user:    "writer",
+			patch:   models.MetadataPatch{ExpiresAt: &expiresAt},
+			wantErr: apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "write grantee can't clear expiration",
+			user:    "writer",
+			patch:   models.MetadataPatch{ExpiresAt: &time.Time{}},
+			wantErr: apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "read grantee can't rename document",
+			user:    "invited",
+			patch:   models.MetadataPatch{Name: &name},
+			wantErr: apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "empty name",
+			user:    "owner",
+			patch:   models.MetadataPatch{Name: &empty},
+			wantErr: apperrors.ErrWrongMetadata,
+		},
+		{
+			name:      "stale etag",
+			user:      "writer",
+			patch:     models.MetadataPatch{Name: &name},
+			staleETag: true,
+			wantErr:   apperrors.ErrPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			metaRepo, doc := newGrantsFixture()
+			ctrl, _ := newTestController(metaRepo)
+
+			ifMatch := etag.Of(doc)
+			if tt.staleETag {
+				stale := doc
+				stale.Version++
+				ifMatch = etag.Of(stale)
+			}
+
+			meta, err := ctrl.UpdateFile(ctx, *doc.ID, metaRepo.users[tt.user], tt.patch, ifMatch)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, doc, metaRepo.doc(*doc.ID))
+				return
+			}
+			require.NoError(t, err)
+
+			want := metaRepo.doc(*doc.ID)
+			assert.Equal(t, want, meta)
+
+			doc.Updated = want.Updated
+			tt.wantMeta(&doc)
+			assert.Equal(t, doc, want)
+		})
+	}
+}
+
+func TestDocumentsController_ReplaceFile(t *testing.T) {
+	type test struct {
+		name     string
+		user     string
+		content  string
+		wantErr  error
+		wantJSON models.JSONString
+	}
+	tests := []test{
+		{
+			name:     "write grantee replaces json",
+			user:     "writer",
+			content:  `{"a":1}`,
+			wantJSON: `{"a":1}`,
+		},
+		{
+			name:    "invalid json",
+			user:    "owner",
+			content: `{"a":`,
+			wantErr: apperrors.ErrWrongMetadata,
+		},
+		{
+			name:    "json too large",
+			user:    "owner",
+			content: `"` + strings.Repeat("a", maxJSONSize) + `"`,
+			wantErr: apperrors.ErrWrongMetadata,
+		},
+		{
+			name:    "read grantee can't replace json",
+			user:    "invited",
+			content: `{"a":1}`,
+			wantErr: apperrors.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			metaRepo, doc := newGrantsFixture()
+			ctrl, _ := newTestController(metaRepo)
+
+			meta := models.Metadata{FileSize: int64(len(tt.content))}
+			version, err := ctrl.ReplaceFile(ctx, *doc.ID, metaRepo.users[tt.user], meta, strings.NewReader(tt.content), "")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, metaRepo.versions)
+				assert.Equal(t, doc, metaRepo.doc(*doc.ID))
+				return
+			}
+			require.NoError(t, err)
+
+			current := metaRepo.doc(*doc.ID)
+			assert.Equal(t, version, current.Version)
+			assert.Equal(t, tt.wantJSON, current.JSON)
+			assert.Equal(t, doc.Name, current.Name)
+			assert.Zero(t, current.FileSize)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package docctrl
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// uploadLockTTL is the time after which lock of resumable upload held by crashed writer expires.
+const uploadLockTTL = time.Hour
+
+// CreateUpload creates resumable upload of file with given metadata and length.
+// Upload data is staged in file repository until all bytes are received.
+// Upload of empty file is finished immediately.
+// Returns ErrQuotaExceeded if file doesn't fit into storage quota of the user.
+// Returns error if create failed.
+// Returns models.Upload if create was successful.
+func (c *DocumentsController) CreateUpload(
+	ctx context.Context,
+	userID uuid.UUID,
+	meta models.Metadata,
+	length int64,
+) (models.Upload, error) {
+	if length < 0 {
+		return models.Upload{}, apperrors.ErrWrongUploadOptions
+	}
+
+	if err := checkExpiration(&meta); err != nil {
+		return models.Upload{}, err
+	}
+
+	if err := checkTags(&meta); err != nil {
+		return models.Upload{}, err
+	}
+
+	if err := c.checkFolder(ctx, &meta, userID); err != nil {
+		return models.Upload{}, err
+	}
+
+	meta.File = true
+	meta.OwnerID = &userID
+	meta.FileSize = length
+
+	quota, err := c.getQuota(ctx, userID)
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	// Upload length is reserved in quota until the upload is finished
+	upload, err := c.metaRepo.AddUpload(ctx, models.Upload{
+		OwnerID:  userID,
+		Length:   length,
+		Metadata: meta,
+	}, quota)
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	// Prepare staging in file repository
+	upload.StorageID, err = c.uploads.CreateUpload(ctx, upload)
+	if err == nil {
+		err = c.metaRepo.SetUploadStorage(ctx, upload.ID, upload.StorageID)
+	}
+
+	if err != nil {
+		c.removeUpload(ctx, upload)
+		return models.Upload{}, err
+	}
+
+	if length == 0 {
+		return upload, c.finishUpload(ctx, upload)
+	}
+
+	return upload, nil
+}
+
+// GetUpload get resumable upload of user.
+// Returns ErrNotFound if upload does not exist or is owned by another user.
+// Returns models.Upload if get was successful.
+func (c *DocumentsController) GetUpload(ctx context.Context, id, userID uuid.UUID) (models.Upload, error) {
+	upload, err := c.metaRepo.GetUpload(ctx, id)
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	if upload.OwnerID != userID {
+		return models.Upload{}, apperrors.ErrNotFound
+	}
+
+	return upload, nil
+}
+
+// WriteUpload appends data to resumable upload of user.
+// offset must be equal to the number of already received bytes.
+// Data after upload length is ignored.
+// When all bytes are received, document is uploaded with UploadDocument
+// and resumable upload is removed.
+// Returns ErrUploadOffsetMismatch if offset is wrong and ErrUploadLocked
+// if upload is being written by another request.
+// Returns upload with new offset, it is valid even if error is returned.
+func (c *DocumentsController) WriteUpload(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	offset int64,
+	data io.Reader,
+) (models.Upload, error) {
+	upload, err := c.metaRepo.LockUpload(ctx, id, uploadLockTTL)
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	// Offset is saved even if request is canceled
+	defer func() {
+		c.unlockUpload(ctx, upload)
+	}()
+
+	if upload.OwnerID != userID {
+		return models.Upload{}, apperrors.ErrNotFound
+	}
+
+	if offset != upload.Offset {
+		return upload, apperrors.ErrUploadOffsetMismatch
+	}
+
+	// Upload finishing may be retried without data
+	if upload.Offset < upload.Length {
+		upload.Offset, err = c.uploads.WriteUpload(
+			ctx,
+			upload,
+			io.LimitReader(data, upload.Length-upload.Offset),
+		)
+		if err != nil {
+			return upload, err
+		}
+	}
+
+	if upload.Offset == upload.Length {
+		err = c.finishUpload(ctx, upload)
+	}
+
+	return upload, err
+}
+
+// TerminateUpload removes resumable upload of user with all staged data.
+// Returns ErrUploadLocked if upload is being written by another request.
+// Returns error if terminate failed.
+func (c *DocumentsController) TerminateUpload(ctx context.Context, id, userID uuid.UUID) error {
+	upload, err := c.metaRepo.LockUpload(ctx, id, uploadLockTTL)
+	if err != nil {
+		return err
+	}
+
+	if upload.OwnerID != userID {
+		err = apperrors.ErrNotFound
+	} else {
+		err = c.uploads.RemoveUpload(ctx, upload)
+	}
+
+	if err != nil {
+		c.unlockUpload(ctx, upload)
+		return err
+	}
+
+	return c.metaRepo.RemoveUpload(ctx, id)
+}
+
+// finishUpload uploads document from fully received resumable upload and removes the upload.
+// If document upload fails, upload is kept so finishing can be retried.
+func (c *DocumentsController) finishUpload(ctx context.Context, upload models.Upload) error {
+	file, err := c.uploads.CompleteUpload(ctx, upload)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Document is saved before the upload is removed, so its size is reserved twice meanwhile
+	err = c.uploadDocument(ctx, upload.Metadata, file, upload.Length)
+	if err != nil {
+		return err
+	}
+
+	c.removeUpload(ctx, upload)
+
+	return nil
+}
+
+// unlockUpload saves offset of resumable upload and releases the lock.
+// Errors are only logged, the lock expires after uploadLockTTL.
+func (c *DocumentsController) unlockUpload(ctx context.Context, upload models.Upload) {
+	// Request context may be already canceled
+	ctx = context.WithoutCancel(ctx)
+
+	if err := c.metaRepo.UnlockUpload(ctx, upload.ID, upload.Offset); err != nil {
+		slog.Error(
+			"Error while unlocking upload",
+			slog.String("id", upload.ID.String()),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// removeUpload removes resumable upload and its staged data.
+// Errors are only logged, remaining data will be removed by reconciler.
+func (c *DocumentsController) removeUpload(ctx context.Context, upload models.Upload) {
+	// Request context may be already canceled
+	ctx = context.WithoutCancel(ctx)
+
+	if upload.StorageID != "" {
+		if err := c.uploads.RemoveUpload(ctx, upload); err != nil {
+			slog.Error(
+				"Error while removing staged upload",
+				slog.String("id", upload.ID.String()),
+				slog.Any("err", err),
+			)
+		}
+	}
+
+	if err := c.metaRepo.RemoveUpload(ctx, upload.ID); err != nil {
+		slog.Error(
+			"Error while removing upload",
+			slog.String("id", upload.ID.String()),
+			slog.Any("err", err),
+		)
+	}
+}
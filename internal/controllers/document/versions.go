@@ -0,0 +1,203 @@
+package docctrl
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// UploadVersion upload new version of the document with given id.
+// Only owner and users with write access can upload new versions.
+// New version becomes current after successful upload.
+// If meta.File is true, file cant be nil.
+// If meta.File is false, meta.JSON must be provided.
+// If meta.ExpiresAt is set, expiration time of the document is changed, zero time clears it.
+// Only owner can change expiration time.
+// Returns ErrQuotaExceeded if version doesn't fit into storage quota of the document owner.
+// Returns error if upload failed.
+// Returns number of uploaded version if upload was successful.
+func (c *DocumentsController) UploadVersion(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	meta models.Metadata,
+	file io.Reader,
+) (int, error) {
+	// Get current document metadata
+	current, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = c.checkAccess(ctx, current, userID, models.AccessLevelWrite); err != nil {
+		return 0, err
+	}
+
+	// Expired document is removed, so only owner can change expiration time
+	if meta.ExpiresAt != nil && *current.OwnerID != userID {
+		return 0, apperrors.ErrAccessDenied
+	}
+
+	return c.uploadVersion(ctx, current, meta, file, nil)
+}
+
+// uploadVersion uploads new version of the current document like UploadVersion does, access must be already checked.
+// If updated is not nil, version becomes current only if the document was not updated since then.
+// Returns ErrPreconditionFailed if document was updated.
+func (c *DocumentsController) uploadVersion(
+	ctx context.Context,
+	current, meta models.Metadata,
+	file io.Reader,
+	updated *time.Time,
+) (int, error) {
+	err := checkExpiration(&meta)
+	if err != nil {
+		return 0, err
+	}
+
+	meta.ID = current.ID
+	meta.OwnerID = current.OwnerID
+	meta.Status = models.MetadataStatusPending
+
+	// Versions uploaded by grantees are counted in the owner's quota
+	quota, err := c.getQuota(ctx, *current.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Save pending version to repository
+	meta.Version, err = c.metaRepo.AddVersion(ctx, meta, quota)
+	if err != nil {
+		return 0, err
+	}
+
+	// Upload file and make version current, expiration time is set for the whole document
+	err = c.storeVersion(ctx, meta, quota, file, updated)
+	if err != nil {
+		return 0, err
+	}
+
+	// Invalidate cache of owner and grantees
+	if err = c.invalidateDocumentCache(ctx, *current.ID, *current.OwnerID); err != nil {
+		return 0, err
+	}
+
+	return meta.Version, nil
+}
+
+// GetFileVersion get metadata of given version of the document.
+// Access is checked the same way as in GetFileInfo.
+// Version specific fields are taken from the version,
+// other fields are taken from the current document metadata.
+// Returns ErrNotFound if document or version does not exist.
+func (c *DocumentsController) GetFileVersion(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	version int,
+) (models.Metadata, error) {
+	current, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if version == current.Version {
+		return current, nil
+	}
+
+	meta, err := c.metaRepo.GetVersion(ctx, id, version)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	return mergeVersion(current, meta), nil
+}
+
+// GetVersions get all versions of the document, newest first.
+// Access is checked the same way as in GetFileInfo.
+// Returns error if get failed.
+func (c *DocumentsController) GetVersions(
+	ctx context.Context,
+	id, userID uuid.UUID,
+) ([]models.Metadata, error) {
+	current, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := c.metaRepo.GetVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range versions {
+		versions[i] = mergeVersion(current, versions[i])
+	}
+
+	return versions, nil
+}
+
+// RestoreVersion makes given version the current version of the document.
+// Only owner and users with write access can restore versions.
+// If ifMatch is not empty, version is restored only if ETag of the document matches ifMatch.
+// Returns ErrNotFound if document or version does not exist or version is not uploaded yet.
+// Returns ErrPreconditionFailed if ETag does not match.
+func (c *DocumentsController) RestoreVersion(
+	ctx context.Context,
+	id, userID uuid.UUID,
+	version int,
+	ifMatch string,
+) error {
+	current, err := c.GetFileInfo(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = c.checkAccess(ctx, current, userID, models.AccessLevelWrite); err != nil {
+		return err
+	}
+
+	updated, err := checkPrecondition(current, ifMatch)
+	if err != nil {
+		return err
+	}
+
+	// Version is checked first, so guarded restore fails only if the document was updated
+	if updated != nil {
+		if _, err = c.metaRepo.GetVersion(ctx, id, version); err != nil {
+			return err
+		}
+	}
+
+	// Invalidate cache of owner and grantees
+	if err = c.invalidateDocumentCache(ctx, id, *current.OwnerID); err != nil {
+		return err
+	}
+
+	return c.metaRepo.RestoreVersion(ctx, id, version, updated)
+}
+
+// mergeVersion returns document metadata with version specific fields taken from version.
+// Previous versions are not changed after upload, so their update time is upload time.
+func mergeVersion(current, version models.Metadata) models.Metadata {
+	if version.Version != current.Version {
+		current.Updated = version.Updated
+	}
+
+	current.Version = version.Version
+	current.Name = version.Name
+	current.File = version.File
+	current.Mime = version.Mime
+	current.JSON = version.JSON
+	current.FileSize = version.FileSize
+	current.SHA256 = version.SHA256
+	current.MD5 = version.MD5
+	current.Blob = version.Blob
+	current.StoredSize = version.StoredSize
+	current.Encoding = version.Encoding
+	current.Created = version.Created
+
+	return current
+}
@@ -0,0 +1,157 @@
+package docctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/etag"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentsController_UploadVersion(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC()
+
+	type test struct {
+		name          string
+		user          string
+		expiresAt     *time.Time
+		wantErr       error
+		wantExpiresAt *time.Time
+	}
+	tests := []test{
+		{
+			name:          "owner sets expiration",
+			user:          "owner",
+			expiresAt:     &expiresAt,
+			wantExpiresAt: &expiresAt,
+		},
+		{
+			name: "write grantee uploads version",
+			user: "writer",
+		},
+		{
+			name:      "write grantee can't set expiration",
+			user:      "writer",
+			expiresAt: &expiresAt,
+			wantErr:   apperrors.ErrAccessDenied,
+		},
+		{
+			name:      "write grantee can't clear expiration",
+			user:      "writer",
+			expiresAt: &time.Time{},
+			wantErr:   apperrors.ErrAccessDenied,
+		},
+		{
+			name:    "read grantee can't upload version",
+			user:    "invited",
+			wantErr: apperrors.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			metaRepo, doc := newGrantsFixture()
+			ctrl, _ := newTestController(metaRepo)
+
+			meta := models.Metadata{Name: "doc.json", JSON: `{"a":1}`, ExpiresAt: tt.expiresAt}
+			version, err := ctrl.UploadVersion(ctx, *doc.ID, metaRepo.users[tt.user], meta, nil)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, metaRepo.versions)
+				assert.Equal(t, doc, metaRepo.doc(*doc.ID))
+				return
+			}
+			require.NoError(t, err)
+
+			current := metaRepo.doc(*doc.ID)
+			assert.Equal(t, version, current.Version)
+			assert.Equal(t, tt.wantExpiresAt, current.ExpiresAt)
+		})
+	}
+}
+
+func TestDocumentsController_RestoreVersion(t *testing.T) {
+	type test struct {
+		name      string
+		user      string
+		version   int
+		ifMatch   bool
+		staleETag bool
+		wantErr   error
+	}
+	tests := []test{
+		{
+			name:    "owner restores version",
+			user:    "owner",
+			version: 1,
+		},
+		{
+			name:    "write grantee restores version with etag",
+			user:    "writer",
+			version: 1,
+			ifMatch: true,
+		},
+		{
+			name:      "stale etag",
+			user:      "writer",
+			version:   1,
+			ifMatch:   true,
+			staleETag: true,
+			wantErr:   apperrors.ErrPreconditionFailed,
+		},
+		{
+			name:    "missing version with etag",
+			user:    "writer",
+			version: 5,
+			ifMatch: true,
+			wantErr: apperrors.ErrNotFound,
+		},
+		{
+			name:    "read grantee can't restore version",
+			user:    "invited",
+			version: 1,
+			wantErr: apperrors.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			metaRepo, doc := newGrantsFixture()
+			ctrl, _ := newTestController(metaRepo)
+
+			first := doc
+			first.Name = "first.json"
+			metaRepo.versions = append(metaRepo.versions, first)
+
+			current := doc
+			current.Version = 2
+			metaRepo.docs[0] = current
+
+			var ifMatch string
+			if tt.ifMatch {
+				ifMatch = etag.Of(current)
+			}
+			if tt.staleETag {
+				ifMatch = etag.Of(doc)
+			}
+
+			err := ctrl.RestoreVersion(ctx, *doc.ID, metaRepo.users[tt.user], tt.version, ifMatch)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, current, metaRepo.doc(*doc.ID))
+				return
+			}
+			require.NoError(t, err)
+
+			restored := metaRepo.doc(*doc.ID)
+			assert.Equal(t, tt.version, restored.Version)
+			assert.Equal(t, first.Name, restored.Name)
+		})
+	}
+}
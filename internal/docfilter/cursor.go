@@ -0,0 +1,68 @@
+package docfilter
+
+import (
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/signedtoken"
+	"github.com/google/uuid"
+)
+
+// Cursor points to a document in sorted documents list.
+// DocumentsFilter with cursor set returns only documents after it.
+//
+// Must be created with DocumentsFilter.Cursor method.
+type Cursor struct {
+	// Sort is the sort keys of the list cursor belongs to.
+	Sort string `json:"s"`
+
+	// Values of sort key fields of the document.
+	Name     string    `json:"n"`
+	Created  string    `json:"c"`
+	FileSize int64     `json:"f"`
+	Mime     string    `json:"m"`
+	ID       uuid.UUID `json:"i"`
+}
+
+// metadata returns metadata with fields of cursor document.
+func (c Cursor) metadata() models.Metadata {
+	return models.Metadata{
+		ID:       &c.ID,
+		Name:     c.Name,
+		Created:  c.Created,
+		FileSize: c.FileSize,
+		Mime:     c.Mime,
+	}
+}
+
+// CursorSigner encodes cursors to opaque strings signed with HMAC-SHA256
+// and decodes them back.
+// Must be initialized with NewCursorSigner function.
+type CursorSigner struct {
+	signer *signedtoken.Signer
+}
+
+// NewCursorSigner creates new CursorSigner with given secret.
+func NewCursorSigner(secret string) *CursorSigner {
+	return &CursorSigner{
+		signer: signedtoken.New(secret, ""),
+	}
+}
+
+// Encode encodes and signs cursor.
+// Returned string is URL safe.
+func (s *CursorSigner) Encode(cursor Cursor) string {
+	// Cursor contains only strings and numbers, marshaling can't fail
+	token, _ := s.signer.Encode(cursor)
+	return token
+}
+
+// Decode verifies signature and decodes cursor.
+// Returns ErrInvalidCursor if cursor is malformed or signature is wrong.
+func (s *CursorSigner) Decode(token string) (Cursor, error) {
+	var cursor Cursor
+	if !s.signer.Decode(token, &cursor) {
+		return Cursor{}, apperrors.ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
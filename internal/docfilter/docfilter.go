@@ -1,10 +1,47 @@
 package docfilter
 
 import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
 	"github.com/FlutterDizaster/file-server/internal/docfilter/filters"
 	"github.com/FlutterDizaster/file-server/internal/models"
 )
 
+const (
+	// OperatorAnd combines filter clauses with AND.
+	OperatorAnd = "and"
+	// OperatorOr combines filter clauses with OR.
+	OperatorOr = "or"
+
+	// maxClauseDepth is the maximum nesting depth of filter clauses.
+	maxClauseDepth = 8
+)
+
+// SQLQuery is a DocumentsFilter compiled to SQL.
+// Must be created with DocumentsFilter.SQL method.
+type SQLQuery struct {
+	// Where contains SQL predicates that must be joined with AND.
+	// Predicates reference metadata table with alias m.
+	Where []string
+
+	// Args contains arguments for placeholders used in Where.
+	// Additional arguments must be added with Args.Add.
+	Args *filters.Args
+
+	// OrderBy contains SQL ORDER BY expressions in priority order.
+	// Expressions reference metadata table with alias m.
+	// Order is total, it always ends with document id.
+	OrderBy []string
+
+	// Paginated is true if Limit and Offset must be applied to the query.
+	Paginated bool
+	Limit     int
+	Offset    int
+}
+
 // DocumentsFilter used to filter metadata by given filters.
 //
 // If no filters are provided, all metadata will be returned.
@@ -18,6 +55,8 @@ type DocumentsFilter struct {
 	offset int
 
 	filters []filters.Filter
+	sort    []sortKey
+	cursor  *Cursor
 }
 
 // New creates new DocumentsFilter instance.
@@ -53,6 +92,136 @@ func (f *DocumentsFilter) AddFilter(key, value string) error {
 	return nil
 }
 
+// AddFilterClauses adds filter clauses combined with given operator to the DocumentsFilter.
+//
+// operator must be "and", "or" or empty (same as "and").
+// Every clause must be either a single filter with key and value,
+// or a group of nested clauses. Single filters are created with filters.QuerryFilter.
+//
+// All filters must be added before calling FilterData.
+//
+// Returns ErrWrongFilterOptions if clauses are malformed,
+// or an error returned by filters.QuerryFilter.
+func (f *DocumentsFilter) AddFilterClauses(operator string, clauses []models.FilterClause) error {
+	if len(clauses) == 0 {
+		return nil
+	}
+
+	filter, err := buildGroup(operator, clauses, 1)
+	if err != nil {
+		return err
+	}
+
+	f.filters = append(f.filters, filter)
+
+	return nil
+}
+
+// buildGroup creates filter from clauses combined with operator.
+func buildGroup(operator string, clauses []models.FilterClause, depth int) (filters.Filter, error) {
+	if depth > maxClauseDepth || len(clauses) == 0 {
+		return nil, apperrors.ErrWrongFilterOptions
+	}
+
+	children := make([]filters.Filter, 0, len(clauses))
+	for _, clause := range clauses {
+		child, err := buildClause(clause, depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	switch strings.ToLower(operator) {
+	case "", OperatorAnd:
+		return filters.NewAndFilter(children...), nil
+	case OperatorOr:
+		return filters.NewOrFilter(children...), nil
+	default:
+		return nil, apperrors.ErrWrongFilterOptions
+	}
+}
+
+// buildClause creates filter from a single clause.
+func buildClause(clause models.FilterClause, depth int) (filters.Filter, error) {
+	var (
+		filter filters.Filter
+		err    error
+	)
+
+	switch {
+	case clause.Key != "" && len(clause.Clauses) > 0:
+		return nil, apperrors.ErrWrongFilterOptions
+	case clause.Key != "":
+		filter, err = filters.QuerryFilter(clause.Key, clause.Value)
+	default:
+		filter, err = buildGroup(clause.Operator, clause.Clauses, depth+1)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if clause.Not {
+		filter = filters.NewNotFilter(filter)
+	}
+
+	return filter, nil
+}
+
+// AddSort adds a sort key to the DocumentsFilter.
+//
+// key must be one of "name", "created", "file-size" or "mime".
+// order must be "asc", "desc" or empty (same as "asc").
+//
+// Sort keys are applied in the order they were added.
+// Documents with equal sort keys are ordered by name, creation time (newest first) and id.
+//
+// Returns ErrWrongSortOptions if key or order is unknown.
+func (f *DocumentsFilter) AddSort(key, order string) error {
+	s, err := newSortKey(key, order)
+	if err != nil {
+		return err
+	}
+
+	f.sort = append(f.sort, s)
+
+	return nil
+}
+
+// SetCursor sets position in sorted documents list.
+// Only documents after cursor document match the filter.
+// Offset is applied after cursor.
+//
+// Cursor must be created by DocumentsFilter with the same sort keys.
+// Returns ErrInvalidCursor otherwise.
+func (f *DocumentsFilter) SetCursor(cursor Cursor) error {
+	if cursor.Sort != f.sortString() {
+		return apperrors.ErrInvalidCursor
+	}
+
+	if _, err := time.Parse(time.DateTime, cursor.Created); err != nil {
+		return apperrors.ErrInvalidCursor
+	}
+
+	f.cursor = &cursor
+
+	return nil
+}
+
+// Cursor returns cursor pointing to given document.
+// It can be used with SetCursor to get documents after meta.
+func (f DocumentsFilter) Cursor(meta models.Metadata) Cursor {
+	return Cursor{
+		Sort:     f.sortString(),
+		Name:     meta.Name,
+		Created:  meta.Created,
+		FileSize: meta.FileSize,
+		Mime:     meta.Mime,
+		ID:       metadataID(meta),
+	}
+}
+
 // FilterData filters the given slice of metadata according to the filters
 // set in the DocumentsFilter and returns a new slice of filtered metadata.
 //
@@ -63,24 +232,188 @@ func (f *DocumentsFilter) AddFilter(key, value string) error {
 // The method will return an empty slice if no metadata match the filters
 // or if the `limit` is set to 0.
 //
+// Matching metadata is sorted by sort keys before cursor, limit and offset are applied.
+//
 // The function will not modify the original slice.
 func (f DocumentsFilter) FilterData(data []models.Metadata) []models.Metadata {
-	result := make([]models.Metadata, 0, f.limit)
+	order := f.order()
+
+	var after models.Metadata
+	if f.cursor != nil {
+		after = f.cursor.metadata()
+	}
+
+	matched := make([]models.Metadata, 0, len(data))
+	for _, meta := range data {
+		if !f.match(meta) {
+			continue
+		}
+
+		if f.cursor != nil && compare(order, meta, after) <= 0 {
+			continue
+		}
+
+		matched = append(matched, meta)
+	}
+
+	slices.SortFunc(matched, func(a, b models.Metadata) int {
+		return compare(order, a, b)
+	})
+
+	start := min(f.offset, len(matched))
+	end := min(start+f.limit, len(matched))
+
+	return matched[start:end]
+}
+
+// Count returns number of metadata in data matching the filters.
+// Cursor, limit and offset are ignored.
+func (f DocumentsFilter) Count(data []models.Metadata) int {
+	count := 0
+	for _, meta := range data {
+		if f.match(meta) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// order returns sort keys followed by default order.
+// Default keys already used in sort keys are skipped.
+func (f DocumentsFilter) order() []sortKey {
+	order := slices.Clone(f.sort)
+	for _, s := range defaultOrder() {
+		used := slices.ContainsFunc(f.sort, func(k sortKey) bool {
+			return k.key == s.key
+		})
+		if !used {
+			order = append(order, s)
+		}
+	}
+
+	return order
+}
+
+// sortString returns sort keys as a string.
+func (f DocumentsFilter) sortString() string {
+	keys := make([]string, 0, len(f.sort))
+	for _, s := range f.sort {
+		keys = append(keys, s.String())
+	}
+
+	return strings.Join(keys, ",")
+}
+
+// compare compares metadata by all keys of order.
+func compare(order []sortKey, a, b models.Metadata) int {
+	for _, s := range order {
+		if result := s.compare(a, b); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
 
-	for i := 0; len(result) < f.limit && i < len(data); i++ {
-		pass := true
+// SQL compiles DocumentsFilter to SQL query.
+//
+// Filters that implement filters.SQLFilter are compiled to SQL predicates.
+// Sort keys are compiled to ORDER BY expressions.
+// If all filters are compiled, cursor, limit and offset are compiled too
+// and returned DocumentsFilter is nil.
+// Otherwise, returned DocumentsFilter contains remaining filters, sort keys, cursor, limit and offset,
+// and must be applied to the query result with FilterData.
+func (f DocumentsFilter) SQL() (SQLQuery, *DocumentsFilter) {
+	query := f.whereSQL()
+
+	order := f.order()
+	for _, s := range order {
+		query.OrderBy = append(query.OrderBy, s.sql())
+	}
+
+	rest := New(f.limit, f.offset)
+	rest.sort = f.sort
+	rest.cursor = f.cursor
+
+	for _, filter := range f.filters {
+		if _, ok := filter.(filters.SQLFilter); !ok {
+			rest.filters = append(rest.filters, filter)
+		}
+	}
+
+	if len(rest.filters) > 0 {
+		return query, rest
+	}
+
+	if f.cursor != nil {
+		query.Where = append(query.Where, seekSQL(order, f.cursor.metadata(), query.Args))
+	}
+
+	query.Limit = f.limit
+	query.Offset = f.offset
+	query.Paginated = true
+
+	return query, nil
+}
+
+// CountSQL compiles DocumentsFilter to SQL query counting all matching documents.
+// Cursor, sort keys, limit and offset are ignored.
+//
+// Result is correct only if SQL returns nil DocumentsFilter,
+// otherwise Count must be applied to the query result.
+func (f DocumentsFilter) CountSQL() SQLQuery {
+	return f.whereSQL()
+}
+
+// whereSQL compiles filters that implement filters.SQLFilter to SQL predicates.
+func (f DocumentsFilter) whereSQL() SQLQuery {
+	query := SQLQuery{
+		Args: filters.NewArgs(),
+	}
+
+	for _, filter := range f.filters {
+		if sqlFilter, ok := filter.(filters.SQLFilter); ok {
+			query.Where = append(query.Where, sqlFilter.SQL(query.Args))
+		}
+	}
+
+	return query
+}
 
-		for _, filter := range f.filters {
-			if !filter.Apply(data[i]) {
-				pass = false
-				break
-			}
+// seekSQL returns SQL predicate matching documents placed after given document in order.
+func seekSQL(order []sortKey, after models.Metadata, args *filters.Args) string {
+	placeholders := make([]string, 0, len(order))
+	for _, s := range order {
+		placeholders = append(placeholders, args.Add(s.value(after)))
+	}
+
+	alternatives := make([]string, 0, len(order))
+	for i, s := range order {
+		conditions := make([]string, 0, i+1)
+		for j, prev := range order[:i] {
+			conditions = append(conditions, prev.expr()+" = "+placeholders[j])
+		}
+
+		operator := " > "
+		if s.desc {
+			operator = " < "
 		}
+		conditions = append(conditions, s.expr()+operator+placeholders[i])
+
+		alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	return strings.Join(alternatives, " OR ")
+}
 
-		if pass && i >= f.offset {
-			result = append(result, data[i])
+// match checks if metadata matches all filters.
+func (f DocumentsFilter) match(data models.Metadata) bool {
+	for _, filter := range f.filters {
+		if !filter.Apply(data) {
+			return false
 		}
 	}
 
-	return result
+	return true
 }
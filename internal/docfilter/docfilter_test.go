@@ -0,0 +1,406 @@
+package docfilter
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testData() []models.Metadata {
+	return []models.Metadata{
+		{Name: "a.txt", Mime: "text/plain", File: true, Path: "/", Tags: []string{"draft", "report"}},
+		{Name: "b.json", Mime: "application/json", Path: "/docs", Attributes: map[string]string{"project": "alpha"}},
+		{
+			Name: "c.txt", Mime: "text/plain", File: true, Path: "/docs/old",
+			Tags: []string{"report"}, Attributes: map[string]string{"project": "beta"},
+		},
+		{Name: "d.png", Mime: "image/png", File: true, Path: "/images"},
+		{Name: "e.txt", Mime: "text/plain", File: true, Path: "/docs-2"},
+	}
+}
+
+func TestDocumentsFilter_FilterData(t *testing.T) {
+	type filter struct {
+		key   string
+		value string
+	}
+	type test struct {
+		name    string
+		limit   int
+		offset  int
+		filters []filter
+		want    []string
+	}
+	tests := []test{
+		{
+			name:  "no filters",
+			limit: 10,
+			want:  []string{"a.txt", "b.json", "c.txt", "d.png", "e.txt"},
+		},
+		{
+			name:    "limit",
+			limit:   2,
+			filters: []filter{{"mime", "text"}},
+			want:    []string{"a.txt", "c.txt"},
+		},
+		{
+			name:    "offset counts matched documents",
+			limit:   10,
+			offset:  1,
+			filters: []filter{{"mime", "text"}},
+			want:    []string{"c.txt", "e.txt"},
+		},
+		{
+			name:    "multiple filters",
+			limit:   10,
+			filters: []filter{{"file", "true"}, {"name", "*.png"}},
+			want:    []string{"d.png"},
+		},
+		{
+			name:    "folder",
+			limit:   10,
+			filters: []filter{{"path", "/docs/"}},
+			want:    []string{"b.json"},
+		},
+		{
+			name:    "folder with subfolders",
+			limit:   10,
+			filters: []filter{{"path", "docs/*"}},
+			want:    []string{"b.json", "c.txt"},
+		},
+		{
+			name:    "root folder",
+			limit:   10,
+			filters: []filter{{"path", "/"}},
+			want:    []string{"a.txt"},
+		},
+		{
+			name:    "tag",
+			limit:   10,
+			filters: []filter{{"tag", "report"}},
+			want:    []string{"a.txt", "c.txt"},
+		},
+		{
+			name:    "attribute",
+			limit:   10,
+			filters: []filter{{"attr", "project"}},
+			want:    []string{"b.json", "c.txt"},
+		},
+		{
+			name:    "attribute value",
+			limit:   10,
+			filters: []filter{{"attr", "project=beta"}},
+			want:    []string{"c.txt"},
+		},
+		{
+			name:  "zero limit",
+			limit: 0,
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(tt.limit, tt.offset)
+			for _, fl := range tt.filters {
+				require.NoError(t, f.AddFilter(fl.key, fl.value))
+			}
+
+			names := []string{}
+			for _, meta := range f.FilterData(testData()) {
+				names = append(names, meta.Name)
+			}
+
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestDocumentsFilter_SQL(t *testing.T) {
+	f := New(10, 5)
+	require.NoError(t, f.AddFilter("name", "report*"))
+	require.NoError(t, f.AddFilter("public", "true"))
+	require.NoError(t, f.AddFilter("created", "2024-01-01 00:00:00~2024-02-01 00:00:00"))
+	require.NoError(t, f.AddFilter("tag", "draft"))
+	require.NoError(t, f.AddFilter("attr", "project=alpha"))
+
+	query, rest := f.SQL()
+
+	assert.Nil(t, rest)
+	assert.True(t, query.Paginated)
+	assert.Equal(t, 10, query.Limit)
+	assert.Equal(t, 5, query.Offset)
+	assert.Equal(t, []string{
+		"m.name LIKE $1",
+		"m.public = $2",
+		"date_trunc('second', m.created) > $3 AND date_trunc('second', m.created) < $4",
+		"m.tags @> ARRAY[$5::text]",
+		"m.attributes @> jsonb_build_object($6::text, $7::text)",
+	}, query.Where)
+	assert.Equal(t, []any{
+		"report%",
+		true,
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		"draft",
+		"project",
+		"alpha",
+	}, query.Args.Values())
+}
+
+func TestDocumentsFilter_AddFilterClauses(t *testing.T) {
+	type test struct {
+		name     string
+		operator string
+		clauses  []models.FilterClause
+		want     []string
+		wantSQL  []string
+		wantErr  bool
+	}
+	tests := []test{
+		{
+			name:     "or",
+			operator: "or",
+			clauses: []models.FilterClause{
+				{Key: "mime", Value: "json"},
+				{Key: "mime", Value: "png"},
+			},
+			want:    []string{"b.json", "d.png"},
+			wantSQL: []string{"(strpos(m.mime, $1) > 0) OR (strpos(m.mime, $2) > 0)"},
+		},
+		{
+			name: "and with not",
+			clauses: []models.FilterClause{
+				{Key: "file", Value: "true"},
+				{Key: "name", Value: "a*", Not: true},
+			},
+			want:    []string{"c.txt", "d.png", "e.txt"},
+			wantSQL: []string{"(m.is_file = $1) AND (NOT (m.name LIKE $2))"},
+		},
+		{
+			name:     "nested group",
+			operator: "or",
+			clauses: []models.FilterClause{
+				{Key: "name", Value: "a*"},
+				{
+					Operator: "and",
+					Clauses: []models.FilterClause{
+						{Key: "mime", Value: "text"},
+						{Key: "name", Value: "e*"},
+					},
+				},
+			},
+			want: []string{"a.txt", "e.txt"},
+			wantSQL: []string{
+				"(m.name LIKE $1) OR ((strpos(m.mime, $2) > 0) AND (m.name LIKE $3))",
+			},
+		},
+		{
+			name:     "unknown operator",
+			operator: "xor",
+			clauses:  []models.FilterClause{{Key: "file", Value: "true"}},
+			wantErr:  true,
+		},
+		{
+			name:    "key and clauses",
+			clauses: []models.FilterClause{{Key: "file", Value: "true", Clauses: []models.FilterClause{{}}}},
+			wantErr: true,
+		},
+		{
+			name:    "empty group",
+			clauses: []models.FilterClause{{Not: true}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown filter",
+			clauses: []models.FilterClause{{Key: "size", Value: "1"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(10, 0)
+
+			err := f.AddFilterClauses(tt.operator, tt.clauses)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, meta := range f.FilterData(testData()) {
+				names = append(names, meta.Name)
+			}
+			assert.Equal(t, tt.want, names)
+
+			query, rest := f.SQL()
+			assert.Nil(t, rest)
+			assert.Equal(t, tt.wantSQL, query.Where)
+		})
+	}
+}
+
+func TestDocumentsFilter_Sort(t *testing.T) {
+	type sort struct {
+		key   string
+		order string
+	}
+	type test struct {
+		name    string
+		limit   int
+		offset  int
+		sort    []sort
+		want    []string
+		wantSQL []string
+		wantErr bool
+	}
+	tests := []test{
+		{
+			name:    "single key desc",
+			limit:   10,
+			sort:    []sort{{"name", "desc"}},
+			want:    []string{"e.txt", "d.png", "c.txt", "b.json", "a.txt"},
+			wantSQL: []string{`m.name COLLATE "C" DESC`, "date_trunc('second', m.created) DESC", "m.id ASC"},
+		},
+		{
+			name:  "multiple keys",
+			limit: 10,
+			sort:  []sort{{"mime", "asc"}, {"name", "desc"}},
+			want:  []string{"b.json", "d.png", "e.txt", "c.txt", "a.txt"},
+			wantSQL: []string{
+				`m.mime COLLATE "C" ASC`,
+				`m.name COLLATE "C" DESC`,
+				"date_trunc('second', m.created) DESC",
+				"m.id ASC",
+			},
+		},
+		{
+			name:    "limit and offset after sort",
+			limit:   2,
+			offset:  1,
+			sort:    []sort{{"name", "desc"}},
+			want:    []string{"d.png", "c.txt"},
+			wantSQL: []string{`m.name COLLATE "C" DESC`, "date_trunc('second', m.created) DESC", "m.id ASC"},
+		},
+		{
+			name:    "unknown key",
+			sort:    []sort{{"owner", "asc"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown order",
+			sort:    []sort{{"name", "up"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(tt.limit, tt.offset)
+			var err error
+			for _, s := range tt.sort {
+				err = f.AddSort(s.key, s.order)
+				if err != nil {
+					break
+				}
+			}
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, meta := range f.FilterData(testData()) {
+				names = append(names, meta.Name)
+			}
+			assert.Equal(t, tt.want, names)
+
+			query, rest := f.SQL()
+			assert.Nil(t, rest)
+			assert.Equal(t, tt.wantSQL, query.OrderBy)
+		})
+	}
+}
+
+func TestDocumentsFilter_Cursor(t *testing.T) {
+	data := testData()
+	for i := range data {
+		id := uuid.New()
+		data[i].ID = &id
+		data[i].Created = "2024-01-01 00:00:00"
+	}
+
+	// Collect all pages, removing already returned documents between pages
+	newFilter := func() *DocumentsFilter {
+		f := New(2, 0)
+		require.NoError(t, f.AddSort("mime", "asc"))
+		return f
+	}
+
+	names := []string{}
+	var cursor *Cursor
+	for range len(data) {
+		f := newFilter()
+		if cursor != nil {
+			require.NoError(t, f.SetCursor(*cursor))
+		}
+
+		page := f.FilterData(data)
+		if len(page) == 0 {
+			break
+		}
+		for _, meta := range page {
+			names = append(names, meta.Name)
+		}
+
+		next := f.Cursor(page[len(page)-1])
+		cursor = &next
+		data = slices.DeleteFunc(data, func(meta models.Metadata) bool {
+			return meta.Name == page[0].Name
+		})
+	}
+
+	assert.Equal(t, []string{"b.json", "d.png", "a.txt", "c.txt", "e.txt"}, names)
+
+	// Cursor is bound to sort keys
+	f := New(2, 0)
+	require.ErrorIs(t, f.SetCursor(*cursor), apperrors.ErrInvalidCursor)
+
+	// Cursor is compiled to SQL predicate
+	f = newFilter()
+	require.NoError(t, f.SetCursor(*cursor))
+	query, rest := f.SQL()
+	require.Nil(t, rest)
+	assert.Equal(t, []string{
+		`(m.mime COLLATE "C" > $1) OR ` +
+			`(m.mime COLLATE "C" = $1 AND m.name COLLATE "C" > $2) OR ` +
+			`(m.mime COLLATE "C" = $1 AND m.name COLLATE "C" = $2 AND date_trunc('second', m.created) < $3) OR ` +
+			`(m.mime COLLATE "C" = $1 AND m.name COLLATE "C" = $2 AND date_trunc('second', m.created) = $3 AND m.id > $4)`,
+	}, query.Where)
+	assert.Len(t, query.Args.Values(), 4)
+
+	// Count query ignores cursor
+	assert.Empty(t, f.CountSQL().Where)
+}
+
+func TestCursorSigner(t *testing.T) {
+	signer := NewCursorSigner("secret")
+	cursor := Cursor{Sort: "name:asc", Name: "a.txt", Created: "2024-01-01 00:00:00", ID: uuid.New()}
+
+	token := signer.Encode(cursor)
+	decoded, err := signer.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = NewCursorSigner("other").Decode(token)
+	require.ErrorIs(t, err, apperrors.ErrInvalidCursor)
+
+	_, err = signer.Decode("x" + token)
+	require.ErrorIs(t, err, apperrors.ErrInvalidCursor)
+}
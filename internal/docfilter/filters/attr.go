@@ -0,0 +1,57 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// AttrFilter used to filter metadata by user-defined attribute.
+type AttrFilter struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+// NewAttrFilter creates new AttrFilter instance.
+//
+// Value formats:
+//
+//   - "<key>" : document has attribute.
+//   - "<key>=<value>" : document attribute has exact value.
+//
+// Returns ErrInvalidFilterValue if key is empty.
+func NewAttrFilter(value string) (*AttrFilter, error) {
+	key, attrValue, hasValue := strings.Cut(value, "=")
+	if key == "" {
+		return nil, apperrors.ErrInvalidFilterValue
+	}
+
+	return &AttrFilter{
+		key:      key,
+		value:    attrValue,
+		hasValue: hasValue,
+	}, nil
+}
+
+// Apply implements Filter interface.
+//
+// Returns true if document has attribute f.key with value f.value, if it is set.
+func (f *AttrFilter) Apply(data models.Metadata) bool {
+	value, ok := data.Attributes[f.key]
+	if !ok {
+		return false
+	}
+
+	return !f.hasValue || value == f.value
+}
+
+// SQL implements SQLFilter interface.
+func (f *AttrFilter) SQL(args *Args) string {
+	if !f.hasValue {
+		return "m.attributes ? " + args.Add(f.key)
+	}
+
+	return "m.attributes @> jsonb_build_object(" + args.Add(f.key) + "::text, " + args.Add(f.value) + "::text)"
+}
@@ -119,3 +119,22 @@ func (f *DateFilter) parseFilterValue(value string) error {
 
 	return nil
 }
+
+// SQL implements SQLFilter interface.
+//
+// Creation time is truncated to seconds, the same way it is formatted in metadata.
+func (f *DateFilter) SQL(args *Args) string {
+	const created = "date_trunc('second', m.created)"
+
+	switch f.mode {
+	case dateFilterModeAfter:
+		return created + " > " + args.Add(f.date)
+	case dateFilterModeBefore:
+		return created + " < " + args.Add(f.date)
+	case dateFilterModeEqual:
+		return created + " = " + args.Add(f.date)
+	case dateFilterModeBetween:
+		return created + " > " + args.Add(f.date) + " AND " + created + " < " + args.Add(f.endDate)
+	}
+	return "false"
+}
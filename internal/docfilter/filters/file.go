@@ -34,3 +34,8 @@ func NewFileFilter(value string) (*FileFilter, error) {
 func (f *FileFilter) Apply(data models.Metadata) bool {
 	return f.isFile == data.File
 }
+
+// SQL implements SQLFilter interface.
+func (f *FileFilter) SQL(args *Args) string {
+	return "m.is_file = " + args.Add(f.isFile)
+}
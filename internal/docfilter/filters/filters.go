@@ -16,6 +16,9 @@ const (
 	FilterKeyDate   FilterKey = "created"
 	FilterKeyGrant  FilterKey = "grant"
 	FilterKeyID     FilterKey = "id"
+	FilterKeyPath   FilterKey = "path"
+	FilterKeyTag    FilterKey = "tag"
+	FilterKeyAttr   FilterKey = "attr"
 )
 
 // Filter used to filter metadata.
@@ -34,6 +37,9 @@ type Filter interface {
 //   - "created" : filter by creation date.
 //   - "grant" : filter by user login.
 //   - "id" : filter by document id.
+//   - "path" : filter by folder path.
+//   - "tag" : filter by tag.
+//   - "attr" : filter by attribute.
 //
 // value format depends on filter type.
 //
@@ -56,6 +62,12 @@ func QuerryFilter(key, value string) (Filter, error) {
 		return NewGrantFilter(value)
 	case FilterKeyID:
 		return NewIDFilter(value)
+	case FilterKeyPath:
+		return NewPathFilter(value)
+	case FilterKeyTag:
+		return NewTagFilter(value)
+	case FilterKeyAttr:
+		return NewAttrFilter(value)
 	default:
 		return nil, apperrors.ErrUnknownFilter
 	}
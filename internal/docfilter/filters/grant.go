@@ -28,3 +28,12 @@ func NewGrantFilter(login string) (*GrantFilter, error) {
 func (f *GrantFilter) Apply(data models.Metadata) bool {
 	return slices.Contains(data.Grant, f.login)
 }
+
+// SQL implements SQLFilter interface.
+func (f *GrantFilter) SQL(args *Args) string {
+	return `EXISTS (
+    SELECT 1 FROM meta_access fma
+    JOIN users fu ON fu.id = fma.user_id
+    WHERE fma.meta_id = m.id AND fu.username = ` + args.Add(f.login) + `
+)`
+}
@@ -33,3 +33,8 @@ func NewIDFilter(id string) (IDFilter, error) {
 func (f IDFilter) Apply(meta models.Metadata) bool {
 	return f.id == *meta.ID
 }
+
+// SQL implements SQLFilter interface.
+func (f IDFilter) SQL(args *Args) string {
+	return "m.id = " + args.Add(f.id)
+}
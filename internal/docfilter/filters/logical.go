@@ -0,0 +1,115 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+type logicalOperator int
+
+const (
+	logicalOperatorAnd logicalOperator = iota
+	logicalOperatorOr
+	logicalOperatorNot
+)
+
+// LogicalFilter used to combine filters with AND, OR or NOT.
+// Must be initialized with NewAndFilter, NewOrFilter or NewNotFilter function.
+type LogicalFilter struct {
+	operator logicalOperator
+	filters  []Filter
+}
+
+// sqlLogicalFilter is a LogicalFilter that can be compiled to SQL.
+// Used when all combined filters implement SQLFilter.
+type sqlLogicalFilter struct {
+	LogicalFilter
+}
+
+// NewAndFilter creates filter that matches metadata matching all given filters.
+//
+// Returned filter implements SQLFilter if all given filters implement it.
+func NewAndFilter(filters ...Filter) Filter {
+	return newLogicalFilter(logicalOperatorAnd, filters)
+}
+
+// NewOrFilter creates filter that matches metadata matching any of given filters.
+//
+// Returned filter implements SQLFilter if all given filters implement it.
+func NewOrFilter(filters ...Filter) Filter {
+	return newLogicalFilter(logicalOperatorOr, filters)
+}
+
+// NewNotFilter creates filter that matches metadata not matching given filter.
+//
+// Returned filter implements SQLFilter if given filter implements it.
+func NewNotFilter(filter Filter) Filter {
+	return newLogicalFilter(logicalOperatorNot, []Filter{filter})
+}
+
+func newLogicalFilter(operator logicalOperator, filters []Filter) Filter {
+	f := LogicalFilter{
+		operator: operator,
+		filters:  filters,
+	}
+
+	for _, filter := range filters {
+		if _, ok := filter.(SQLFilter); !ok {
+			return &f
+		}
+	}
+
+	return &sqlLogicalFilter{f}
+}
+
+// Apply implements Filter interface.
+//
+// Empty AND filter matches any metadata, empty OR filter matches nothing.
+func (f *LogicalFilter) Apply(data models.Metadata) bool {
+	switch f.operator {
+	case logicalOperatorAnd:
+		for _, filter := range f.filters {
+			if !filter.Apply(data) {
+				return false
+			}
+		}
+		return true
+	case logicalOperatorOr:
+		for _, filter := range f.filters {
+			if filter.Apply(data) {
+				return true
+			}
+		}
+		return false
+	case logicalOperatorNot:
+		return !f.filters[0].Apply(data)
+	}
+	return false
+}
+
+// SQL implements SQLFilter interface.
+func (f *sqlLogicalFilter) SQL(args *Args) string {
+	predicates := make([]string, 0, len(f.filters))
+	for _, filter := range f.filters {
+		//nolint:errcheck // all filters are checked in newLogicalFilter
+		sqlFilter := filter.(SQLFilter)
+		predicates = append(predicates, "("+sqlFilter.SQL(args)+")")
+	}
+
+	switch f.operator {
+	case logicalOperatorAnd:
+		if len(predicates) == 0 {
+			return "true"
+		}
+		return strings.Join(predicates, " AND ")
+	case logicalOperatorOr:
+		if len(predicates) == 0 {
+			return "false"
+		}
+		return strings.Join(predicates, " OR ")
+	case logicalOperatorNot:
+		return "NOT " + predicates[0]
+	}
+	return "false"
+}
@@ -28,3 +28,8 @@ func NewMimeFilter(value string) (*MimeFilter, error) {
 func (f *MimeFilter) Apply(meta models.Metadata) bool {
 	return strings.Contains(meta.Mime, f.mime)
 }
+
+// SQL implements SQLFilter interface.
+func (f *MimeFilter) SQL(args *Args) string {
+	return "strpos(m.mime, " + args.Add(f.mime) + ") > 0"
+}
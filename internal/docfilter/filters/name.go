@@ -34,3 +34,19 @@ func (f *NameFilter) Apply(data models.Metadata) bool {
 
 	return strings.Contains(data.Name, f.name)
 }
+
+// SQL implements SQLFilter interface.
+func (f *NameFilter) SQL(args *Args) string {
+	var pattern string
+
+	switch {
+	case strings.HasPrefix(f.name, "*"):
+		pattern = "%" + escapeLike(f.name[1:])
+	case strings.HasSuffix(f.name, "*"):
+		pattern = escapeLike(f.name[:len(f.name)-1]) + "%"
+	default:
+		pattern = "%" + escapeLike(f.name) + "%"
+	}
+
+	return "m.name LIKE " + args.Add(pattern)
+}
@@ -30,3 +30,8 @@ func NewOwnerFilter(value string) (*OwnerFilter, error) {
 func (f *OwnerFilter) Apply(meta models.Metadata) bool {
 	return f.id == *meta.OwnerID
 }
+
+// SQL implements SQLFilter interface.
+func (f *OwnerFilter) SQL(args *Args) string {
+	return "m.owner_id = " + args.Add(f.id)
+}
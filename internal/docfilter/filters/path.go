@@ -0,0 +1,62 @@
+package filters
+
+import (
+	"path"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// PathFilter used to filter metadata by folder path.
+type PathFilter struct {
+	path      string
+	recursive bool
+}
+
+// NewPathFilter creates new PathFilter instance.
+//
+// value is a folder path, for example "/projects/alpha", root folder path is "/".
+// Documents placed directly in the folder are matched.
+// If value ends with "/*", documents of all subfolders are matched too.
+func NewPathFilter(value string) (*PathFilter, error) {
+	value, recursive := strings.CutSuffix(value, "/*")
+
+	return &PathFilter{
+		path:      path.Clean("/" + value),
+		recursive: recursive,
+	}, nil
+}
+
+// Apply implements Filter interface.
+//
+// Returns true if document is placed in the folder f.path or, if filter is recursive, in its subfolder.
+func (f *PathFilter) Apply(data models.Metadata) bool {
+	if !f.recursive {
+		return data.Path == f.path
+	}
+
+	return f.path == "/" || data.Path == f.path || strings.HasPrefix(data.Path, f.path+"/")
+}
+
+// SQL implements SQLFilter interface.
+//
+// Documents of the root folder have no folder.
+func (f *PathFilter) SQL(args *Args) string {
+	switch {
+	case f.path == "/" && f.recursive:
+		return "true"
+	case f.path == "/":
+		return "m.folder_id IS NULL"
+	case f.recursive:
+		return `EXISTS (
+    SELECT 1 FROM folders pf
+    WHERE pf.id = m.folder_id
+    AND (pf.path = ` + args.Add(f.path) + ` OR starts_with(pf.path, ` + args.Add(f.path+"/") + `))
+)`
+	default:
+		return `EXISTS (
+    SELECT 1 FROM folders pf
+    WHERE pf.id = m.folder_id AND pf.path = ` + args.Add(f.path) + `
+)`
+	}
+}
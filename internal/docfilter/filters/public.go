@@ -34,3 +34,8 @@ func NewPublicFilter(isPublic string) (*PublicFilter, error) {
 func (f *PublicFilter) Apply(data models.Metadata) bool {
 	return f.isPublic == data.Public
 }
+
+// SQL implements SQLFilter interface.
+func (f *PublicFilter) SQL(args *Args) string {
+	return "m.public = " + args.Add(f.isPublic)
+}
@@ -0,0 +1,48 @@
+package filters
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SQLFilter is a Filter that can be compiled to SQL predicate.
+//
+// Filters that don't implement SQLFilter are applied in memory.
+type SQLFilter interface {
+	Filter
+
+	// SQL returns SQL predicate equivalent to Apply.
+	// Predicate references metadata table with alias m.
+	// Query arguments must be added with args.Add.
+	SQL(args *Args) string
+}
+
+// Args used to collect arguments of SQL query.
+// Zero value is ready to use.
+type Args struct {
+	values []any
+}
+
+// NewArgs creates new Args instance with given initial values.
+func NewArgs(values ...any) *Args {
+	return &Args{
+		values: values,
+	}
+}
+
+// Add adds value to arguments and returns its placeholder.
+func (a *Args) Add(value any) string {
+	a.values = append(a.values, value)
+	return "$" + strconv.Itoa(len(a.values))
+}
+
+// Values returns all added arguments in placeholders order.
+func (a *Args) Values() []any {
+	return a.values
+}
+
+// escapeLike escapes LIKE pattern special characters in s.
+func escapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
+}
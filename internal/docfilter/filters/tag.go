@@ -0,0 +1,38 @@
+package filters
+
+import (
+	"slices"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// TagFilter used to filter metadata by tag.
+type TagFilter struct {
+	tag string
+}
+
+// NewTagFilter creates new TagFilter instance.
+//
+// Returns ErrInvalidFilterValue if tag is empty.
+func NewTagFilter(tag string) (*TagFilter, error) {
+	if tag == "" {
+		return nil, apperrors.ErrInvalidFilterValue
+	}
+
+	return &TagFilter{
+		tag: tag,
+	}, nil
+}
+
+// Apply implements Filter interface.
+//
+// Returns true if document has tag f.tag.
+func (f *TagFilter) Apply(data models.Metadata) bool {
+	return slices.Contains(data.Tags, f.tag)
+}
+
+// SQL implements SQLFilter interface.
+func (f *TagFilter) SQL(args *Args) string {
+	return "m.tags @> ARRAY[" + args.Add(f.tag) + "::text]"
+}
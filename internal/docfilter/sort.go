@@ -0,0 +1,160 @@
+package docfilter
+
+import (
+	"bytes"
+	"cmp"
+	"strings"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// SortKey is a metadata field documents can be sorted by.
+type SortKey string
+
+const (
+	SortKeyName     SortKey = "name"
+	SortKeyCreated  SortKey = "created"
+	SortKeyFileSize SortKey = "file-size"
+	SortKeyMime     SortKey = "mime"
+
+	// sortKeyID is used only as the last tiebreaker and can't be requested.
+	sortKeyID SortKey = "id"
+)
+
+const (
+	// OrderAsc sorts in ascending order.
+	OrderAsc = "asc"
+	// OrderDesc sorts in descending order.
+	OrderDesc = "desc"
+)
+
+// defaultOrder returns sort keys applied after requested sort keys.
+// It ends with document id, so the order of documents is always total.
+func defaultOrder() []sortKey {
+	return []sortKey{
+		{key: SortKeyName},
+		{key: SortKeyCreated, desc: true},
+		{key: sortKeyID},
+	}
+}
+
+// sortKey is a parsed sort clause.
+type sortKey struct {
+	key  SortKey
+	desc bool
+}
+
+// newSortKey parses sort key and order.
+// Returns ErrWrongSortOptions if key or order is unknown.
+func newSortKey(key, order string) (sortKey, error) {
+	s := sortKey{
+		key: SortKey(key),
+	}
+
+	switch s.key {
+	case SortKeyName, SortKeyCreated, SortKeyFileSize, SortKeyMime:
+	default:
+		return sortKey{}, apperrors.ErrWrongSortOptions
+	}
+
+	switch strings.ToLower(order) {
+	case "", OrderAsc:
+	case OrderDesc:
+		s.desc = true
+	default:
+		return sortKey{}, apperrors.ErrWrongSortOptions
+	}
+
+	return s, nil
+}
+
+// String returns sort key in "key:order" format.
+func (s sortKey) String() string {
+	if s.desc {
+		return string(s.key) + ":" + OrderDesc
+	}
+	return string(s.key) + ":" + OrderAsc
+}
+
+// compare compares metadata by sort key.
+// Creation time is compared as formatted string, which has chronological order.
+func (s sortKey) compare(a, b models.Metadata) int {
+	var result int
+
+	switch s.key {
+	case SortKeyName:
+		result = strings.Compare(a.Name, b.Name)
+	case SortKeyCreated:
+		result = strings.Compare(a.Created, b.Created)
+	case SortKeyFileSize:
+		result = cmp.Compare(a.FileSize, b.FileSize)
+	case SortKeyMime:
+		result = strings.Compare(a.Mime, b.Mime)
+	case sortKeyID:
+		aID, bID := metadataID(a), metadataID(b)
+		result = bytes.Compare(aID[:], bID[:])
+	}
+
+	if s.desc {
+		return -result
+	}
+	return result
+}
+
+// expr returns SQL expression of sort key.
+// Expressions are consistent with compare: strings and ids are compared bytewise
+// and creation time is truncated to seconds.
+func (s sortKey) expr() string {
+	switch s.key {
+	case SortKeyName:
+		return `m.name COLLATE "C"`
+	case SortKeyCreated:
+		return "date_trunc('second', m.created)"
+	case SortKeyFileSize:
+		return "COALESCE(m.file_size, 0)"
+	case SortKeyMime:
+		return `m.mime COLLATE "C"`
+	case sortKeyID:
+		return "m.id"
+	}
+	return ""
+}
+
+// value returns value of sort key field of metadata as SQL query argument.
+// Creation time must be in time.DateTime format.
+func (s sortKey) value(meta models.Metadata) any {
+	switch s.key {
+	case SortKeyName:
+		return meta.Name
+	case SortKeyCreated:
+		// Format is checked before
+		created, _ := time.Parse(time.DateTime, meta.Created)
+		return created
+	case SortKeyFileSize:
+		return meta.FileSize
+	case SortKeyMime:
+		return meta.Mime
+	case sortKeyID:
+		return metadataID(meta)
+	}
+	return nil
+}
+
+// sql returns SQL ORDER BY expression for sort key.
+func (s sortKey) sql() string {
+	if s.desc {
+		return s.expr() + " DESC"
+	}
+	return s.expr() + " ASC"
+}
+
+// metadataID returns metadata id or uuid.Nil if id is not set.
+func metadataID(meta models.Metadata) uuid.UUID {
+	if meta.ID == nil {
+		return uuid.Nil
+	}
+	return *meta.ID
+}
@@ -0,0 +1,74 @@
+// Package docpurge permanently removes documents together with files of all their versions.
+package docpurge
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// FileRepository used to delete files of purged documents.
+type FileRepository interface {
+	// DeleteFile delete file from repository.
+	// Blob of deduplicated file is not deleted.
+	// Returns error if delete failed.
+	DeleteFile(ctx context.Context, meta models.Metadata) error
+}
+
+// MetadataRepository used to remove metadata of purged documents.
+type MetadataRepository interface {
+	// GetVersions get all uploaded versions of document.
+	// Returns error if get failed.
+	GetVersions(ctx context.Context, id uuid.UUID) ([]models.Metadata, error)
+
+	// PurgeMetadata permanently remove trashed or expired document with all its versions.
+	// References of document versions to deduplicated blobs are released.
+	// Returns ErrNotFound if document is not trashed or expired.
+	// Returns error if purge failed.
+	PurgeMetadata(ctx context.Context, id uuid.UUID) error
+}
+
+// Purge permanently removes metadata of trashed or expired document and files of its versions.
+// Only ID and OwnerID fields of meta are used.
+//
+// Files are deleted after metadata is removed. If it fails, files will be removed by reconciler
+// as orphaned, deduplicated files are removed by reconciler when their blobs are not referenced anymore.
+//
+// Returns ErrNotFound if document is not trashed or expired, files are not deleted then.
+func Purge(
+	ctx context.Context,
+	fileRepo FileRepository,
+	metaRepo MetadataRepository,
+	meta models.Metadata,
+) error {
+	versions, err := metaRepo.GetVersions(ctx, *meta.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = metaRepo.PurgeMetadata(ctx, *meta.ID); err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if !version.File {
+			continue
+		}
+
+		version.ID = meta.ID
+		version.OwnerID = meta.OwnerID
+
+		if err = fileRepo.DeleteFile(ctx, version); err != nil {
+			slog.Error(
+				"Error while deleting file of purged document",
+				slog.String("id", meta.ID.String()),
+				slog.Int("version", version.Version),
+				slog.Any("err", err),
+			)
+		}
+	}
+
+	return nil
+}
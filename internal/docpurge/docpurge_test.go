@@ -0,0 +1,63 @@
+package docpurge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/docpurge/docpurgetest"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurge(t *testing.T) {
+	type test struct {
+		name      string
+		kept      bool
+		wantErr   error
+		wantFiles map[docpurgetest.File]bool
+	}
+
+	id := uuid.New()
+	ownerID := uuid.New()
+	files := map[docpurgetest.File]bool{
+		{ID: id, Version: 1}: true,
+		{ID: id, Version: 3}: true,
+	}
+
+	tests := []test{
+		{
+			name:      "files of all versions are deleted",
+			wantFiles: map[docpurgetest.File]bool{},
+		},
+		{
+			name:      "files of kept document are not deleted",
+			kept:      true,
+			wantErr:   apperrors.ErrNotFound,
+			wantFiles: files,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileRepo := &docpurgetest.FileRepository{Files: make(map[docpurgetest.File]bool)}
+			for file := range files {
+				fileRepo.Files[file] = true
+			}
+			metaRepo := &docpurgetest.MetadataRepository{
+				Versions: map[uuid.UUID][]models.Metadata{
+					id: {{Version: 3, File: true}, {Version: 2}, {Version: 1, File: true}},
+				},
+				Kept: map[uuid.UUID]bool{id: tt.kept},
+			}
+
+			err := Purge(context.Background(), fileRepo, metaRepo, models.Metadata{ID: &id, OwnerID: &ownerID})
+			require.ErrorIs(t, err, tt.wantErr)
+
+			assert.Equal(t, tt.wantFiles, fileRepo.Files)
+			assert.Equal(t, tt.kept, metaRepo.Versions[id] != nil)
+		})
+	}
+}
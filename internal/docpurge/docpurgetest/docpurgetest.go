@@ -0,0 +1,50 @@
+// Package docpurgetest provides in-memory repositories for tests of document purging.
+package docpurgetest
+
+import (
+	"context"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// File identifies stored file of document version.
+type File struct {
+	ID      uuid.UUID
+	Version int
+}
+
+// FileRepository keeps stored files in memory.
+type FileRepository struct {
+	Files map[File]bool
+}
+
+// DeleteFile deletes file of version meta.Version of the document with id meta.ID.
+func (f *FileRepository) DeleteFile(_ context.Context, meta models.Metadata) error {
+	delete(f.Files, File{ID: *meta.ID, Version: meta.Version})
+	return nil
+}
+
+// MetadataRepository keeps versions of documents in memory.
+// Documents in Kept are not trashed or expired anymore and can't be purged.
+type MetadataRepository struct {
+	Versions map[uuid.UUID][]models.Metadata
+	Kept     map[uuid.UUID]bool
+}
+
+// GetVersions returns versions of the document with given id.
+func (f *MetadataRepository) GetVersions(_ context.Context, id uuid.UUID) ([]models.Metadata, error) {
+	return f.Versions[id], nil
+}
+
+// PurgeMetadata removes versions of the document with given id.
+// Returns ErrNotFound if the document does not exist or is kept.
+func (f *MetadataRepository) PurgeMetadata(_ context.Context, id uuid.UUID) error {
+	if _, ok := f.Versions[id]; !ok || f.Kept[id] {
+		return apperrors.ErrNotFound
+	}
+
+	delete(f.Versions, id)
+	return nil
+}
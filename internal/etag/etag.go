@@ -0,0 +1,68 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// tagSize is the number of hex characters of hash used as entity tag.
+const tagSize = 32
+
+// Of returns strong entity tag of the document, quoted as in ETag header.
+//
+// Tag is derived from document id, version, file checksums or JSON data and update time,
+// so it changes whenever content or metadata of the document is changed.
+func Of(meta models.Metadata) string {
+	h := sha256.New()
+
+	if meta.ID != nil {
+		h.Write(meta.ID[:])
+	}
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(meta.Version)))             //nolint:gosec // bits are hashed
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(meta.Updated.UnixMicro()))) //nolint:gosec // bits are hashed
+
+	for _, field := range []string{meta.SHA256, meta.MD5, string(meta.JSON)} {
+		h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(field))))
+		h.Write([]byte(field))
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil))[:tagSize] + `"`
+}
+
+// Match reports whether If-Match header value matches entity tag.
+// Strong comparison is used, weak tags never match.
+// Empty header matches any tag.
+func Match(header, tag string) bool {
+	if strings.TrimSpace(header) == "" {
+		return true
+	}
+
+	for _, item := range strings.Split(header, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || item == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NoneMatch reports whether If-None-Match header value doesn't match entity tag.
+// Weak comparison is used.
+// Empty header doesn't match any tag.
+func NoneMatch(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+
+	for _, item := range strings.Split(header, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || strings.TrimPrefix(item, "W/") == tag {
+			return false
+		}
+	}
+
+	return true
+}
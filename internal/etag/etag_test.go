@@ -0,0 +1,58 @@
+package etag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOf(t *testing.T) {
+	id := uuid.New()
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	meta := models.Metadata{
+		ID:      &id,
+		Version: 1,
+		SHA256:  "abc",
+		Updated: updated,
+	}
+
+	tag := Of(meta)
+	assert.Equal(t, tag, Of(meta))
+	assert.Len(t, tag, tagSize+2)
+
+	changed := []models.Metadata{meta, meta, meta, meta}
+	changed[0].Version = 2
+	changed[1].SHA256 = "abd"
+	changed[2].Updated = updated.Add(time.Microsecond)
+	changed[3].JSON = models.JSONString(`{}`)
+
+	for _, m := range changed {
+		assert.NotEqual(t, tag, Of(m))
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		match       bool
+		noneMatched bool
+	}{
+		{name: "empty", header: "", match: true, noneMatched: true},
+		{name: "any", header: "*", match: true, noneMatched: false},
+		{name: "same", header: `"a"`, match: true, noneMatched: false},
+		{name: "list", header: `"b", "a"`, match: true, noneMatched: false},
+		{name: "weak", header: `W/"a"`, match: false, noneMatched: false},
+		{name: "other", header: `"b"`, match: false, noneMatched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, Match(tt.header, `"a"`))
+			assert.Equal(t, tt.noneMatched, NoneMatch(tt.header, `"a"`))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package expirer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/docpurge"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/worker"
+	"github.com/google/uuid"
+)
+
+// FileRepository used to delete files of expired documents.
+type FileRepository interface {
+	docpurge.FileRepository
+}
+
+// MetadataRepository used to find and remove expired documents.
+type MetadataRepository interface {
+	docpurge.MetadataRepository
+
+	// GetExpiredMetadata get documents which expiration time has passed.
+	// Only ID and OwnerID fields are filled.
+	// Returns error if get failed.
+	GetExpiredMetadata(ctx context.Context) ([]models.Metadata, error)
+
+	// GetGrantees get ids of users the document is shared with.
+	// Returns error if get failed.
+	GetGrantees(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+}
+
+// MetadataCache used to invalidate cached documents of users.
+type MetadataCache interface {
+	// InvalidateUserCache invalidate user cache.
+	// Returns error if invalidate failed.
+	InvalidateUserCache(ctx context.Context, id uuid.UUID) error
+}
+
+// Settings used to create Expirer.
+// Settings must be provided to New function.
+// All fields are required.
+type Settings struct {
+	// FileRepo used to delete files.
+	FileRepo FileRepository
+
+	// MetaRepo used to find and remove expired documents.
+	MetaRepo MetadataRepository
+
+	// Cache used to invalidate cache of owners and grantees of expired documents.
+	Cache MetadataCache
+
+	// Interval between sweeps.
+	Interval time.Duration
+}
+
+// Expirer is a background worker that removes expired documents.
+//
+// On every sweep it permanently removes documents which expiration time has passed
+// together with files of all their versions and invalidates cache of their owners and grantees.
+// Expired documents are hidden from users before they are removed.
+//
+// Must be initialized with New function.
+type Expirer struct {
+	fileRepo FileRepository
+	metaRepo MetadataRepository
+	cache    MetadataCache
+	interval time.Duration
+}
+
+// New creates new Expirer.
+// Returns pointer to Expirer.
+// Accepts Settings as argument.
+func New(settings Settings) *Expirer {
+	return &Expirer{
+		fileRepo: settings.FileRepo,
+		metaRepo: settings.MetaRepo,
+		cache:    settings.Cache,
+		interval: settings.Interval,
+	}
+}
+
+// Start runs sweeps every interval and blocks until context is canceled.
+// Sweep errors are logged and don't stop the expirer.
+func (e *Expirer) Start(ctx context.Context) error {
+	worker.Run(ctx, e.interval, func(ctx context.Context) {
+		if err := e.Sweep(ctx); err != nil {
+			slog.Error("Error while removing expired documents", slog.Any("err", err))
+		}
+	})
+
+	return nil
+}
+
+// Sweep removes expired documents.
+// Returns error if sweep failed.
+func (e *Expirer) Sweep(ctx context.Context) error {
+	expired, err := e.metaRepo.GetExpiredMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, meta := range expired {
+		err = e.removeDocument(ctx, meta)
+		// Expiration time was changed meanwhile
+		if errors.Is(err, apperrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		slog.Info("Expired document removed", slog.String("id", meta.ID.String()))
+	}
+
+	return nil
+}
+
+// removeDocument removes metadata of expired document and files of its versions
+// and invalidates cache of its owner and grantees.
+func (e *Expirer) removeDocument(ctx context.Context, meta models.Metadata) error {
+	// Grants are removed with metadata
+	grantees, err := e.metaRepo.GetGrantees(ctx, *meta.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = docpurge.Purge(ctx, e.fileRepo, e.metaRepo, meta); err != nil {
+		return err
+	}
+
+	// Invalidate cache of owner and grantees.
+	// Cache is invalidated after removal, so it can't be filled with removed document again.
+	for _, userID := range append(grantees, *meta.OwnerID) {
+		if err = e.cache.InvalidateUserCache(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
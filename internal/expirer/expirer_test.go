@@ -0,0 +1,87 @@
+package expirer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/docpurge/docpurgetest"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMetaRepo struct {
+	docpurgetest.MetadataRepository
+
+	grantees map[uuid.UUID][]uuid.UUID
+	expired  []models.Metadata
+}
+
+func (f *fakeMetaRepo) GetExpiredMetadata(_ context.Context) ([]models.Metadata, error) {
+	return f.expired, nil
+}
+
+func (f *fakeMetaRepo) GetGrantees(_ context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	return f.grantees[id], nil
+}
+
+type fakeCache struct {
+	invalidated []uuid.UUID
+}
+
+func (f *fakeCache) InvalidateUserCache(_ context.Context, id uuid.UUID) error {
+	f.invalidated = append(f.invalidated, id)
+	return nil
+}
+
+func TestExpirer_Sweep(t *testing.T) {
+	expiredID := uuid.New()
+	prolongedID := uuid.New()
+	activeID := uuid.New()
+	ownerID := uuid.New()
+	granteeID := uuid.New()
+
+	fileRepo := &docpurgetest.FileRepository{
+		Files: map[docpurgetest.File]bool{
+			{ID: expiredID, Version: 1}:   true,
+			{ID: prolongedID, Version: 1}: true,
+			{ID: activeID, Version: 1}:    true,
+		},
+	}
+	metaRepo := &fakeMetaRepo{
+		MetadataRepository: docpurgetest.MetadataRepository{
+			Versions: map[uuid.UUID][]models.Metadata{
+				expiredID:   {{Version: 1, File: true}},
+				prolongedID: {{Version: 1, File: true}},
+				activeID:    {{Version: 1, File: true}},
+			},
+			// Expiration time of document was changed after it was found
+			Kept: map[uuid.UUID]bool{prolongedID: true},
+		},
+		grantees: map[uuid.UUID][]uuid.UUID{expiredID: {granteeID}},
+		expired: []models.Metadata{
+			{ID: &prolongedID, OwnerID: &ownerID},
+			{ID: &expiredID, OwnerID: &ownerID},
+		},
+	}
+	cache := &fakeCache{}
+
+	e := New(Settings{
+		FileRepo: fileRepo,
+		MetaRepo: metaRepo,
+		Cache:    cache,
+		Interval: time.Minute,
+	})
+
+	require.NoError(t, e.Sweep(context.Background()))
+
+	assert.Equal(t, map[docpurgetest.File]bool{
+		{ID: prolongedID, Version: 1}: true,
+		{ID: activeID, Version: 1}:    true,
+	}, fileRepo.Files)
+	assert.NotContains(t, metaRepo.Versions, expiredID)
+	assert.Contains(t, metaRepo.Versions, prolongedID)
+	assert.ElementsMatch(t, []uuid.UUID{ownerID, granteeID}, cache.invalidated)
+}
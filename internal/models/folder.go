@@ -0,0 +1,29 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+//easyjson:json
+type Folders []Folder
+
+//go:generate easyjson -all -omit_empty folder.go
+
+// Folder groups documents of its owner.
+// Path is a materialized path of the folder, for example "/projects/alpha".
+// Folder without parent is placed in the root folder "/".
+type Folder struct {
+	ID       *uuid.UUID `json:"id"`
+	ParentID *uuid.UUID `json:"parent-id"`
+	OwnerID  *uuid.UUID `json:"owner_id"`
+	Name     string     `json:"name"`
+	Path     string     `json:"path"`
+	Created  string     `json:"created"`
+}
+
+// FolderPatch renames folder or moves it to another parent folder.
+// Nil fields are not changed, nil UUID parent moves folder to the root folder.
+type FolderPatch struct {
+	Name     *string    `json:"name"`
+	ParentID *uuid.UUID `json:"parent-id"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// AccessLevel is a level of access to document granted to user.
+// Every level includes all previous levels.
+type AccessLevel string
+
+const (
+	// AccessLevelRead allows to get document.
+	AccessLevelRead AccessLevel = "read"
+	// AccessLevelWrite allows to upload and restore document versions.
+	AccessLevelWrite AccessLevel = "write"
+	// AccessLevelReshare allows to grant access to document to other users.
+	AccessLevelReshare AccessLevel = "reshare"
+)
+
+//go:generate easyjson -all -omit_empty grants.go
+type GrantsRequest struct {
+	// Logins of users to grant or revoke access.
+	Logins []string `json:"logins"`
+
+	// Level of granted access, default "read".
+	// Ignored when access is revoked.
+	Level AccessLevel `json:"level"`
+}
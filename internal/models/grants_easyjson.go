@@ -0,0 +1,129 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson39a340adDecodeGithubComFlutterDizasterFileServerInternalModels(in *jlexer.Lexer, out *GrantsRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "logins":
+			if in.IsNull() {
+				in.Skip()
+				out.Logins = nil
+			} else {
+				in.Delim('[')
+				if out.Logins == nil {
+					if !in.IsDelim(']') {
+						out.Logins = make([]string, 0, 4)
+					} else {
+						out.Logins = []string{}
+					}
+				} else {
+					out.Logins = (out.Logins)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.Logins = append(out.Logins, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "level":
+			out.Level = AccessLevel(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson39a340adEncodeGithubComFlutterDizasterFileServerInternalModels(out *jwriter.Writer, in GrantsRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Logins) != 0 {
+		const prefix string = ",\"logins\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.Logins {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Level != "" {
+		const prefix string = ",\"level\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Level))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GrantsRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson39a340adEncodeGithubComFlutterDizasterFileServerInternalModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GrantsRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson39a340adEncodeGithubComFlutterDizasterFileServerInternalModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GrantsRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson39a340adDecodeGithubComFlutterDizasterFileServerInternalModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GrantsRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson39a340adDecodeGithubComFlutterDizasterFileServerInternalModels(l, v)
+}
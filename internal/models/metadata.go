@@ -1,22 +1,79 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/mailru/easyjson"
+)
+
+// MetadataStatus is a state of document upload.
+type MetadataStatus string
+
+const (
+	// MetadataStatusPending means that metadata is saved but file upload is not finished yet.
+	MetadataStatusPending MetadataStatus = "pending"
+	// MetadataStatusReady means that document is fully uploaded and visible to users.
+	MetadataStatusReady MetadataStatus = "ready"
 )
 
+// FileEncodingIdentity is an encoding of files stored as is.
+// Empty encoding means that file was stored before encodings were saved.
+const FileEncodingIdentity = "identity"
+
 //easyjson:json
 type Metadatas []Metadata
 
 //go:generate easyjson -all -omit_empty metadata.go
 type Metadata struct {
-	ID       *uuid.UUID `json:"id"`
-	Name     string     `json:"name"`
-	File     bool       `json:"file"`
-	Public   bool       `json:"public"`
-	Mime     string     `json:"mime"`
-	Created  string     `json:"created"`
-	OwnerID  *uuid.UUID `json:"owner_id"`
-	Grant    []string   `json:"grant"`
-	JSON     JSONString `json:"json"`
-	FileSize int64      `json:"file-size"`
+	ID          *uuid.UUID        `json:"id"`
+	Name        string            `json:"name"`
+	File        bool              `json:"file"`
+	Public      bool              `json:"public"`
+	Mime        string            `json:"mime"`
+	Created     string            `json:"created"`
+	Updated     time.Time         `json:"updated"`
+	Deleted     string            `json:"deleted"`
+	ExpiresAt   *time.Time        `json:"expires-at"`
+	OwnerID     *uuid.UUID        `json:"owner_id"`
+	FolderID    *uuid.UUID        `json:"folder-id"`
+	Path        string            `json:"path"`
+	Tags        []string          `json:"tags"`
+	Attributes  map[string]string `json:"attributes"`
+	Grant       []string          `json:"grant"`
+	JSON        JSONString        `json:"json"`
+	FileSize    int64             `json:"file-size"`
+	StoredSize  int64             `json:"stored-size"`
+	Encoding    string            `json:"-"`
+	Version     int               `json:"version"`
+	SHA256      string            `json:"sha256"`
+	MD5         string            `json:"md5"`
+	Blob        string            `json:"-"`
+	Status      MetadataStatus    `json:"-"`
+	ContentText string            `json:"-"`
+}
+
+//easyjson:json
+type CachedMetadatas []CachedMetadata
+
+// CachedMetadata is document metadata saved to cache.
+// Blob key and file encoding are hidden from API responses but are needed to read the file.
+type CachedMetadata struct {
+	Metadata
+	Blob     string `json:"blob"`
+	Encoding string `json:"encoding"`
+}
+
+// MetadataPatch changes document metadata in place.
+// Nil fields are not changed, nil UUID folder moves document to the root folder.
+// Tags and attributes are replaced as a whole.
+type MetadataPatch struct {
+	Name       *string              `json:"name"`
+	Public     *bool                `json:"public"`
+	Mime       *string              `json:"mime"`
+	JSON       *easyjson.RawMessage `json:"json"`
+	ExpiresAt  *time.Time           `json:"expires-at"`
+	FolderID   *uuid.UUID           `json:"folder-id"`
+	Tags       *[]string            `json:"tags"`
+	Attributes *map[string]string   `json:"attributes"`
 }
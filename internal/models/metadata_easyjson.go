@@ -9,6 +9,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -85,7 +86,7 @@ func (v *Metadatas) UnmarshalJSON(data []byte) error {
 func (v *Metadatas) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels(l, v)
 }
-func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in *jlexer.Lexer, out *Metadata) {
+func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in *jlexer.Lexer, out *MetadataPatch) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -104,6 +105,802 @@ func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in
 			continue
 		}
 		switch key {
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+				out.Name = nil
+			} else {
+				if out.Name == nil {
+					out.Name = new(string)
+				}
+				*out.Name = string(in.String())
+			}
+		case "public":
+			if in.IsNull() {
+				in.Skip()
+				out.Public = nil
+			} else {
+				if out.Public == nil {
+					out.Public = new(bool)
+				}
+				*out.Public = bool(in.Bool())
+			}
+		case "mime":
+			if in.IsNull() {
+				in.Skip()
+				out.Mime = nil
+			} else {
+				if out.Mime == nil {
+					out.Mime = new(string)
+				}
+				*out.Mime = string(in.String())
+			}
+		case "json":
+			if in.IsNull() {
+				in.Skip()
+				out.JSON = nil
+			} else {
+				if out.JSON == nil {
+					out.JSON = new(easyjson.RawMessage)
+				}
+				(*out.JSON).UnmarshalEasyJSON(in)
+			}
+		case "expires-at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		case "folder-id":
+			if in.IsNull() {
+				in.Skip()
+				out.FolderID = nil
+			} else {
+				if out.FolderID == nil {
+					out.FolderID = new(uuid.UUID)
+				}
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((*out.FolderID).UnmarshalText(data))
+				}
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				if out.Tags == nil {
+					out.Tags = new([]string)
+				}
+				if in.IsNull() {
+					in.Skip()
+					*out.Tags = nil
+				} else {
+					in.Delim('[')
+					if *out.Tags == nil {
+						if !in.IsDelim(']') {
+							*out.Tags = make([]string, 0, 4)
+						} else {
+							*out.Tags = []string{}
+						}
+					} else {
+						*out.Tags = (*out.Tags)[:0]
+					}
+					for !in.IsDelim(']') {
+						var v4 string
+						v4 = string(in.String())
+						*out.Tags = append(*out.Tags, v4)
+						in.WantComma()
+					}
+					in.Delim(']')
+				}
+			}
+		case "attributes":
+			if in.IsNull() {
+				in.Skip()
+				out.Attributes = nil
+			} else {
+				if out.Attributes == nil {
+					out.Attributes = new(map[string]string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					in.Delim('{')
+					if !in.IsDelim('}') {
+						*out.Attributes = make(map[string]string)
+					} else {
+						*out.Attributes = nil
+					}
+					for !in.IsDelim('}') {
+						key := string(in.String())
+						in.WantColon()
+						var v5 string
+						v5 = string(in.String())
+						(*out.Attributes)[key] = v5
+						in.WantComma()
+					}
+					in.Delim('}')
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(out *jwriter.Writer, in MetadataPatch) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != nil {
+		const prefix string = ",\"name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(*in.Name))
+	}
+	if in.Public != nil {
+		const prefix string = ",\"public\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(*in.Public))
+	}
+	if in.Mime != nil {
+		const prefix string = ",\"mime\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(*in.Mime))
+	}
+	if in.JSON != nil {
+		const prefix string = ",\"json\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.JSON).MarshalEasyJSON(out)
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires-at\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.ExpiresAt).MarshalJSON())
+	}
+	if in.FolderID != nil {
+		const prefix string = ",\"folder-id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((*in.FolderID).MarshalText())
+	}
+	if in.Tags != nil {
+		const prefix string = ",\"tags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if *in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v6, v7 := range *in.Tags {
+				if v6 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v7))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Attributes != nil {
+		const prefix string = ",\"attributes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if *in.Attributes == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v8First := true
+			for v8Name, v8Value := range *in.Attributes {
+				if v8First {
+					v8First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v8Name))
+				out.RawByte(':')
+				out.String(string(v8Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MetadataPatch) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MetadataPatch) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MetadataPatch) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MetadataPatch) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(l, v)
+}
+func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels2(in *jlexer.Lexer, out *Metadata) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+				out.ID = nil
+			} else {
+				if out.ID == nil {
+					out.ID = new(uuid.UUID)
+				}
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((*out.ID).UnmarshalText(data))
+				}
+			}
+		case "name":
+			out.Name = string(in.String())
+		case "file":
+			out.File = bool(in.Bool())
+		case "public":
+			out.Public = bool(in.Bool())
+		case "mime":
+			out.Mime = string(in.String())
+		case "created":
+			out.Created = string(in.String())
+		case "updated":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Updated).UnmarshalJSON(data))
+			}
+		case "deleted":
+			out.Deleted = string(in.String())
+		case "expires-at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		case "owner_id":
+			if in.IsNull() {
+				in.Skip()
+				out.OwnerID = nil
+			} else {
+				if out.OwnerID == nil {
+					out.OwnerID = new(uuid.UUID)
+				}
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((*out.OwnerID).UnmarshalText(data))
+				}
+			}
+		case "folder-id":
+			if in.IsNull() {
+				in.Skip()
+				out.FolderID = nil
+			} else {
+				if out.FolderID == nil {
+					out.FolderID = new(uuid.UUID)
+				}
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((*out.FolderID).UnmarshalText(data))
+				}
+			}
+		case "path":
+			out.Path = string(in.String())
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v9 string
+					v9 = string(in.String())
+					out.Tags = append(out.Tags, v9)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "attributes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Attributes = make(map[string]string)
+				} else {
+					out.Attributes = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v10 string
+					v10 = string(in.String())
+					(out.Attributes)[key] = v10
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "grant":
+			if in.IsNull() {
+				in.Skip()
+				out.Grant = nil
+			} else {
+				in.Delim('[')
+				if out.Grant == nil {
+					if !in.IsDelim(']') {
+						out.Grant = make([]string, 0, 4)
+					} else {
+						out.Grant = []string{}
+					}
+				} else {
+					out.Grant = (out.Grant)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v11 string
+					v11 = string(in.String())
+					out.Grant = append(out.Grant, v11)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "json":
+			(out.JSON).UnmarshalEasyJSON(in)
+		case "file-size":
+			out.FileSize = int64(in.Int64())
+		case "stored-size":
+			out.StoredSize = int64(in.Int64())
+		case "version":
+			out.Version = int(in.Int())
+		case "sha256":
+			out.SHA256 = string(in.String())
+		case "md5":
+			out.MD5 = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels2(out *jwriter.Writer, in Metadata) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != nil {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.RawText((*in.ID).MarshalText())
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	if in.File {
+		const prefix string = ",\"file\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.File))
+	}
+	if in.Public {
+		const prefix string = ",\"public\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Public))
+	}
+	if in.Mime != "" {
+		const prefix string = ",\"mime\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Mime))
+	}
+	if in.Created != "" {
+		const prefix string = ",\"created\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Created))
+	}
+	if true {
+		const prefix string = ",\"updated\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Updated).MarshalJSON())
+	}
+	if in.Deleted != "" {
+		const prefix string = ",\"deleted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Deleted))
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires-at\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.ExpiresAt).MarshalJSON())
+	}
+	if in.OwnerID != nil {
+		const prefix string = ",\"owner_id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((*in.OwnerID).MarshalText())
+	}
+	if in.FolderID != nil {
+		const prefix string = ",\"folder-id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((*in.FolderID).MarshalText())
+	}
+	if in.Path != "" {
+		const prefix string = ",\"path\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Path))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v12, v13 := range in.Tags {
+				if v12 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v13))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Attributes) != 0 {
+		const prefix string = ",\"attributes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v14First := true
+			for v14Name, v14Value := range in.Attributes {
+				if v14First {
+					v14First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v14Name))
+				out.RawByte(':')
+				out.String(string(v14Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Grant) != 0 {
+		const prefix string = ",\"grant\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v15, v16 := range in.Grant {
+				if v15 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v16))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.JSON != "" {
+		const prefix string = ",\"json\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.JSON).MarshalEasyJSON(out)
+	}
+	if in.FileSize != 0 {
+		const prefix string = ",\"file-size\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.FileSize))
+	}
+	if in.StoredSize != 0 {
+		const prefix string = ",\"stored-size\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.StoredSize))
+	}
+	if in.Version != 0 {
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Version))
+	}
+	if in.SHA256 != "" {
+		const prefix string = ",\"sha256\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.SHA256))
+	}
+	if in.MD5 != "" {
+		const prefix string = ",\"md5\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MD5))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Metadata) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Metadata) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Metadata) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Metadata) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels2(l, v)
+}
+func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels3(in *jlexer.Lexer, out *CachedMetadatas) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(CachedMetadatas, 0, 0)
+			} else {
+				*out = CachedMetadatas{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v17 CachedMetadata
+			(v17).UnmarshalEasyJSON(in)
+			*out = append(*out, v17)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels3(out *jwriter.Writer, in CachedMetadatas) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v18, v19 := range in {
+			if v18 > 0 {
+				out.RawByte(',')
+			}
+			(v19).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CachedMetadatas) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CachedMetadatas) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CachedMetadatas) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CachedMetadatas) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels3(l, v)
+}
+func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels4(in *jlexer.Lexer, out *CachedMetadata) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "blob":
+			out.Blob = string(in.String())
+		case "encoding":
+			out.Encoding = string(in.String())
 		case "id":
 			if in.IsNull() {
 				in.Skip()
@@ -126,6 +923,24 @@ func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in
 			out.Mime = string(in.String())
 		case "created":
 			out.Created = string(in.String())
+		case "updated":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Updated).UnmarshalJSON(data))
+			}
+		case "deleted":
+			out.Deleted = string(in.String())
+		case "expires-at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
 		case "owner_id":
 			if in.IsNull() {
 				in.Skip()
@@ -138,6 +953,63 @@ func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in
 					in.AddError((*out.OwnerID).UnmarshalText(data))
 				}
 			}
+		case "folder-id":
+			if in.IsNull() {
+				in.Skip()
+				out.FolderID = nil
+			} else {
+				if out.FolderID == nil {
+					out.FolderID = new(uuid.UUID)
+				}
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((*out.FolderID).UnmarshalText(data))
+				}
+			}
+		case "path":
+			out.Path = string(in.String())
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v20 string
+					v20 = string(in.String())
+					out.Tags = append(out.Tags, v20)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "attributes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Attributes = make(map[string]string)
+				} else {
+					out.Attributes = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v21 string
+					v21 = string(in.String())
+					(out.Attributes)[key] = v21
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
 		case "grant":
 			if in.IsNull() {
 				in.Skip()
@@ -154,9 +1026,9 @@ func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in
 					out.Grant = (out.Grant)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v4 string
-					v4 = string(in.String())
-					out.Grant = append(out.Grant, v4)
+					var v22 string
+					v22 = string(in.String())
+					out.Grant = append(out.Grant, v22)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -165,6 +1037,14 @@ func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in
 			(out.JSON).UnmarshalEasyJSON(in)
 		case "file-size":
 			out.FileSize = int64(in.Int64())
+		case "stored-size":
+			out.StoredSize = int64(in.Int64())
+		case "version":
+			out.Version = int(in.Int())
+		case "sha256":
+			out.SHA256 = string(in.String())
+		case "md5":
+			out.MD5 = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -175,14 +1055,34 @@ func easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(in
 		in.Consumed()
 	}
 }
-func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(out *jwriter.Writer, in Metadata) {
+func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels4(out *jwriter.Writer, in CachedMetadata) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.ID != nil {
-		const prefix string = ",\"id\":"
+	if in.Blob != "" {
+		const prefix string = ",\"blob\":"
 		first = false
 		out.RawString(prefix[1:])
+		out.String(string(in.Blob))
+	}
+	if in.Encoding != "" {
+		const prefix string = ",\"encoding\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Encoding))
+	}
+	if in.ID != nil {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.RawText((*in.ID).MarshalText())
 	}
 	if in.Name != "" {
@@ -235,6 +1135,36 @@ func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(out
 		}
 		out.String(string(in.Created))
 	}
+	if true {
+		const prefix string = ",\"updated\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Updated).MarshalJSON())
+	}
+	if in.Deleted != "" {
+		const prefix string = ",\"deleted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Deleted))
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires-at\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.ExpiresAt).MarshalJSON())
+	}
 	if in.OwnerID != nil {
 		const prefix string = ",\"owner_id\":"
 		if first {
@@ -245,6 +1175,69 @@ func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(out
 		}
 		out.RawText((*in.OwnerID).MarshalText())
 	}
+	if in.FolderID != nil {
+		const prefix string = ",\"folder-id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.RawText((*in.FolderID).MarshalText())
+	}
+	if in.Path != "" {
+		const prefix string = ",\"path\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Path))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v23, v24 := range in.Tags {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v24))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Attributes) != 0 {
+		const prefix string = ",\"attributes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v25First := true
+			for v25Name, v25Value := range in.Attributes {
+				if v25First {
+					v25First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v25Name))
+				out.RawByte(':')
+				out.String(string(v25Value))
+			}
+			out.RawByte('}')
+		}
+	}
 	if len(in.Grant) != 0 {
 		const prefix string = ",\"grant\":"
 		if first {
@@ -255,11 +1248,11 @@ func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(out
 		}
 		{
 			out.RawByte('[')
-			for v5, v6 := range in.Grant {
-				if v5 > 0 {
+			for v26, v27 := range in.Grant {
+				if v26 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v6))
+				out.String(string(v27))
 			}
 			out.RawByte(']')
 		}
@@ -284,29 +1277,69 @@ func easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(out
 		}
 		out.Int64(int64(in.FileSize))
 	}
+	if in.StoredSize != 0 {
+		const prefix string = ",\"stored-size\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.StoredSize))
+	}
+	if in.Version != 0 {
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Version))
+	}
+	if in.SHA256 != "" {
+		const prefix string = ",\"sha256\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.SHA256))
+	}
+	if in.MD5 != "" {
+		const prefix string = ",\"md5\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MD5))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Metadata) MarshalJSON() ([]byte, error) {
+func (v CachedMetadata) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(&w, v)
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Metadata) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels1(w, v)
+func (v CachedMetadata) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBa0ee0e3EncodeGithubComFlutterDizasterFileServerInternalModels4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Metadata) UnmarshalJSON(data []byte) error {
+func (v *CachedMetadata) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(&r, v)
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Metadata) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels1(l, v)
+func (v *CachedMetadata) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBa0ee0e3DecodeGithubComFlutterDizasterFileServerInternalModels4(l, v)
 }
@@ -0,0 +1,25 @@
+package models
+
+//go:generate easyjson -all quota.go
+
+// Quota is a storage limit of user.
+// Zero limit means no limit.
+type Quota struct {
+	MaxBytes     int64 `json:"max-bytes"`
+	MaxDocuments int   `json:"max-documents"`
+}
+
+// QuotaRequest sets storage limits of user.
+// Nil limit resets it to the default limit.
+type QuotaRequest struct {
+	MaxBytes     *int64 `json:"max-bytes"`
+	MaxDocuments *int   `json:"max-documents"`
+}
+
+// Usage is a storage consumption of user.
+// Bytes is a total file size of all versions of documents, trashed documents and pending uploads included.
+type Usage struct {
+	Bytes     int64 `json:"bytes"`
+	Documents int   `json:"documents"`
+	Quota     Quota `json:"quota"`
+}
@@ -0,0 +1,270 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels(in *jlexer.Lexer, out *Usage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "bytes":
+			out.Bytes = int64(in.Int64())
+		case "documents":
+			out.Documents = int(in.Int())
+		case "quota":
+			(out.Quota).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels(out *jwriter.Writer, in Usage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"bytes\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Bytes))
+	}
+	{
+		const prefix string = ",\"documents\":"
+		out.RawString(prefix)
+		out.Int(int(in.Documents))
+	}
+	{
+		const prefix string = ",\"quota\":"
+		out.RawString(prefix)
+		(in.Quota).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Usage) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Usage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Usage) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Usage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels(l, v)
+}
+func easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels1(in *jlexer.Lexer, out *QuotaRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "max-bytes":
+			if in.IsNull() {
+				in.Skip()
+				out.MaxBytes = nil
+			} else {
+				if out.MaxBytes == nil {
+					out.MaxBytes = new(int64)
+				}
+				*out.MaxBytes = int64(in.Int64())
+			}
+		case "max-documents":
+			if in.IsNull() {
+				in.Skip()
+				out.MaxDocuments = nil
+			} else {
+				if out.MaxDocuments == nil {
+					out.MaxDocuments = new(int)
+				}
+				*out.MaxDocuments = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels1(out *jwriter.Writer, in QuotaRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"max-bytes\":"
+		out.RawString(prefix[1:])
+		if in.MaxBytes == nil {
+			out.RawString("null")
+		} else {
+			out.Int64(int64(*in.MaxBytes))
+		}
+	}
+	{
+		const prefix string = ",\"max-documents\":"
+		out.RawString(prefix)
+		if in.MaxDocuments == nil {
+			out.RawString("null")
+		} else {
+			out.Int(int(*in.MaxDocuments))
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v QuotaRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v QuotaRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *QuotaRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *QuotaRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels1(l, v)
+}
+func easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels2(in *jlexer.Lexer, out *Quota) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "max-bytes":
+			out.MaxBytes = int64(in.Int64())
+		case "max-documents":
+			out.MaxDocuments = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels2(out *jwriter.Writer, in Quota) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"max-bytes\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.MaxBytes))
+	}
+	{
+		const prefix string = ",\"max-documents\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxDocuments))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Quota) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Quota) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD24230d6EncodeGithubComFlutterDizasterFileServerInternalModels2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Quota) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Quota) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD24230d6DecodeGithubComFlutterDizasterFileServerInternalModels2(l, v)
+}
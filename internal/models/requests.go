@@ -7,4 +7,41 @@ type FilesListRequest struct {
 	Value  string `json:"value"`
 	Limit  int    `json:"limit"`
 	Offset int    `json:"offset"`
+
+	// Scope is "own" (default), "shared" or "all".
+	Scope string `json:"scope"`
+
+	// Filters are combined with Operator.
+	// Key and Value filter is combined with Filters using AND.
+	Filters  []FilterClause `json:"filters"`
+	Operator string         `json:"operator"`
+
+	// Sort keys are applied in the given order.
+	Sort []SortClause `json:"sort"`
+
+	// Cursor is the next_cursor of the previous page.
+	// Must be used with the same sort keys as the previous page.
+	Cursor string `json:"cursor"`
+}
+
+// FilterClause is a single filter or a group of filter clauses.
+//
+// Single filter has Key and Value set.
+// Group has Clauses combined with Operator ("and" or "or", default "and").
+// If Not is true, clause result is negated.
+type FilterClause struct {
+	Key      string         `json:"key"`
+	Value    string         `json:"value"`
+	Not      bool           `json:"not"`
+	Operator string         `json:"operator"`
+	Clauses  []FilterClause `json:"clauses"`
+}
+
+// SortClause is a single sort key.
+//
+// Key is one of "name", "created", "file-size" or "mime".
+// Order is "asc" or "desc", default "asc".
+type SortClause struct {
+	Key   string `json:"key"`
+	Order string `json:"order"`
 }
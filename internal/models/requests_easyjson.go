@@ -18,7 +18,231 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(in *jlexer.Lexer, out *FilesListRequest) {
+func easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(in *jlexer.Lexer, out *SortClause) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "key":
+			out.Key = string(in.String())
+		case "order":
+			out.Order = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels(out *jwriter.Writer, in SortClause) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Key))
+	}
+	if in.Order != "" {
+		const prefix string = ",\"order\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Order))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SortClause) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SortClause) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SortClause) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SortClause) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(l, v)
+}
+func easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels1(in *jlexer.Lexer, out *FilterClause) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "key":
+			out.Key = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		case "not":
+			out.Not = bool(in.Bool())
+		case "operator":
+			out.Operator = string(in.String())
+		case "clauses":
+			if in.IsNull() {
+				in.Skip()
+				out.Clauses = nil
+			} else {
+				in.Delim('[')
+				if out.Clauses == nil {
+					if !in.IsDelim(']') {
+						out.Clauses = make([]FilterClause, 0, 0)
+					} else {
+						out.Clauses = []FilterClause{}
+					}
+				} else {
+					out.Clauses = (out.Clauses)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 FilterClause
+					(v1).UnmarshalEasyJSON(in)
+					out.Clauses = append(out.Clauses, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels1(out *jwriter.Writer, in FilterClause) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Key))
+	}
+	if in.Value != "" {
+		const prefix string = ",\"value\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Value))
+	}
+	if in.Not {
+		const prefix string = ",\"not\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Not))
+	}
+	if in.Operator != "" {
+		const prefix string = ",\"operator\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Operator))
+	}
+	if len(in.Clauses) != 0 {
+		const prefix string = ",\"clauses\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.Clauses {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FilterClause) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FilterClause) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FilterClause) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FilterClause) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels1(l, v)
+}
+func easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels2(in *jlexer.Lexer, out *FilesListRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -47,6 +271,58 @@ func easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(in *
 			out.Limit = int(in.Int())
 		case "offset":
 			out.Offset = int(in.Int())
+		case "scope":
+			out.Scope = string(in.String())
+		case "filters":
+			if in.IsNull() {
+				in.Skip()
+				out.Filters = nil
+			} else {
+				in.Delim('[')
+				if out.Filters == nil {
+					if !in.IsDelim(']') {
+						out.Filters = make([]FilterClause, 0, 0)
+					} else {
+						out.Filters = []FilterClause{}
+					}
+				} else {
+					out.Filters = (out.Filters)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 FilterClause
+					(v4).UnmarshalEasyJSON(in)
+					out.Filters = append(out.Filters, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "operator":
+			out.Operator = string(in.String())
+		case "sort":
+			if in.IsNull() {
+				in.Skip()
+				out.Sort = nil
+			} else {
+				in.Delim('[')
+				if out.Sort == nil {
+					if !in.IsDelim(']') {
+						out.Sort = make([]SortClause, 0, 2)
+					} else {
+						out.Sort = []SortClause{}
+					}
+				} else {
+					out.Sort = (out.Sort)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 SortClause
+					(v5).UnmarshalEasyJSON(in)
+					out.Sort = append(out.Sort, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "cursor":
+			out.Cursor = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -57,7 +333,7 @@ func easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(in *
 		in.Consumed()
 	}
 }
-func easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels(out *jwriter.Writer, in FilesListRequest) {
+func easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels2(out *jwriter.Writer, in FilesListRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -107,29 +383,97 @@ func easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels(out
 		}
 		out.Int(int(in.Offset))
 	}
+	if in.Scope != "" {
+		const prefix string = ",\"scope\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Scope))
+	}
+	if len(in.Filters) != 0 {
+		const prefix string = ",\"filters\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v6, v7 := range in.Filters {
+				if v6 > 0 {
+					out.RawByte(',')
+				}
+				(v7).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Operator != "" {
+		const prefix string = ",\"operator\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Operator))
+	}
+	if len(in.Sort) != 0 {
+		const prefix string = ",\"sort\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v8, v9 := range in.Sort {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Cursor != "" {
+		const prefix string = ",\"cursor\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Cursor))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v FilesListRequest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels(&w, v)
+	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v FilesListRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels(w, v)
+	easyjson11d1a9baEncodeGithubComFlutterDizasterFileServerInternalModels2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *FilesListRequest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(&r, v)
+	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *FilesListRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels(l, v)
+	easyjson11d1a9baDecodeGithubComFlutterDizasterFileServerInternalModels2(l, v)
 }
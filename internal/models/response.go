@@ -12,12 +12,33 @@ type Response struct {
 }
 
 type ResponseUploading struct {
-	JSON JSONString `json:"json"`
-	File string     `json:"file"`
+	JSON    JSONString `json:"json"`
+	File    string     `json:"file"`
+	Version int        `json:"version"`
 }
 
 type ResponseFilesList struct {
 	Docs []Metadata `json:"docs"`
+
+	// NextCursor is used to get the next page of the list.
+	// Empty if there are no more documents.
+	NextCursor string `json:"next_cursor"`
+	// Total is the number of documents matching filters.
+	Total int `json:"total"`
+}
+
+type ResponseShareLink struct {
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type ResponsePresignedURL struct {
+	ID        string `json:"id"`
+	Version   int    `json:"version"`
+	Method    string `json:"method"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
 }
 
 type ResponseError struct {
@@ -41,6 +41,8 @@ func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels(in *
 			(out.JSON).UnmarshalEasyJSON(in)
 		case "file":
 			out.File = string(in.String())
+		case "version":
+			out.Version = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -71,6 +73,16 @@ func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels(out
 		}
 		out.String(string(in.File))
 	}
+	if in.Version != 0 {
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Version))
+	}
 	out.RawByte('}')
 }
 
@@ -97,7 +109,213 @@ func (v *ResponseUploading) UnmarshalJSON(data []byte) error {
 func (v *ResponseUploading) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels(l, v)
 }
-func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(in *jlexer.Lexer, out *ResponseFilesList) {
+func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(in *jlexer.Lexer, out *ResponseShareLink) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		case "expires_at":
+			out.ExpiresAt = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels1(out *jwriter.Writer, in ResponseShareLink) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Token != "" {
+		const prefix string = ",\"token\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	if in.URL != "" {
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.URL))
+	}
+	if in.ExpiresAt != "" {
+		const prefix string = ",\"expires_at\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ExpiresAt))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResponseShareLink) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResponseShareLink) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResponseShareLink) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResponseShareLink) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(l, v)
+}
+func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels2(in *jlexer.Lexer, out *ResponsePresignedURL) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "version":
+			out.Version = int(in.Int())
+		case "method":
+			out.Method = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		case "expires_at":
+			out.ExpiresAt = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels2(out *jwriter.Writer, in ResponsePresignedURL) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Version != 0 {
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Version))
+	}
+	if in.Method != "" {
+		const prefix string = ",\"method\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Method))
+	}
+	if in.URL != "" {
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.URL))
+	}
+	if in.ExpiresAt != "" {
+		const prefix string = ",\"expires_at\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ExpiresAt))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResponsePresignedURL) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResponsePresignedURL) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResponsePresignedURL) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResponsePresignedURL) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels2(l, v)
+}
+func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels3(in *jlexer.Lexer, out *ResponseFilesList) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -139,6 +357,10 @@ func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(in
 				}
 				in.Delim(']')
 			}
+		case "next_cursor":
+			out.NextCursor = string(in.String())
+		case "total":
+			out.Total = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -149,7 +371,7 @@ func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(in
 		in.Consumed()
 	}
 }
-func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels1(out *jwriter.Writer, in ResponseFilesList) {
+func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels3(out *jwriter.Writer, in ResponseFilesList) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -168,33 +390,53 @@ func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels1(out
 			out.RawByte(']')
 		}
 	}
+	if in.NextCursor != "" {
+		const prefix string = ",\"next_cursor\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.NextCursor))
+	}
+	if in.Total != 0 {
+		const prefix string = ",\"total\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Total))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v ResponseFilesList) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels1(&w, v)
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ResponseFilesList) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels1(w, v)
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ResponseFilesList) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(&r, v)
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ResponseFilesList) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels1(l, v)
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels3(l, v)
 }
-func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels2(in *jlexer.Lexer, out *ResponseError) {
+func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels4(in *jlexer.Lexer, out *ResponseError) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -227,7 +469,7 @@ func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels2(in
 		in.Consumed()
 	}
 }
-func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels2(out *jwriter.Writer, in ResponseError) {
+func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels4(out *jwriter.Writer, in ResponseError) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -253,27 +495,27 @@ func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels2(out
 // MarshalJSON supports json.Marshaler interface
 func (v ResponseError) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels2(&w, v)
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ResponseError) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels2(w, v)
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ResponseError) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels2(&r, v)
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ResponseError) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels2(l, v)
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels4(l, v)
 }
-func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels3(in *jlexer.Lexer, out *Response) {
+func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels5(in *jlexer.Lexer, out *Response) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -316,7 +558,7 @@ func easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels3(in
 		in.Consumed()
 	}
 }
-func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels3(out *jwriter.Writer, in Response) {
+func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels5(out *jwriter.Writer, in Response) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -352,23 +594,23 @@ func easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels3(out
 // MarshalJSON supports json.Marshaler interface
 func (v Response) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels3(&w, v)
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Response) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels3(w, v)
+	easyjson6ff3ac1dEncodeGithubComFlutterDizasterFileServerInternalModels5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Response) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels3(&r, v)
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Response) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels3(l, v)
+	easyjson6ff3ac1dDecodeGithubComFlutterDizasterFileServerInternalModels5(l, v)
 }
@@ -0,0 +1,15 @@
+package models
+
+//easyjson:json
+type SearchResults []SearchResult
+
+//go:generate easyjson -all -omit_empty search.go
+
+// SearchResult is a document found by full-text search.
+// Rank is relevance of the document to the search query, higher is better.
+// Snippet is an html escaped fragment of matching text with matched words wrapped in <b> and </b>.
+type SearchResult struct {
+	Metadata
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
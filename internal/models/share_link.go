@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink is a public link to the document.
+type ShareLink struct {
+	ID        uuid.UUID
+	MetaID    uuid.UUID
+	CreatedBy uuid.UUID
+
+	// PasswordHash is a bcrypt hash of link password.
+	// Empty if link is not password protected.
+	PasswordHash string
+
+	// MaxDownloads is a downloads limit, zero means unlimited.
+	MaxDownloads int
+	Downloads    int
+
+	ExpiresAt time.Time
+}
+
+//go:generate easyjson -omit_empty share_link.go
+//easyjson:json
+type ShareLinkRequest struct {
+	// TTL is a link lifetime in time.Duration format, default is set in config.
+	TTL string `json:"ttl"`
+
+	// Password required to open the link, optional.
+	Password string `json:"password"`
+
+	// MaxDownloads is a downloads limit, optional.
+	MaxDownloads int `json:"max_downloads"`
+}
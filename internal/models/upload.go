@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upload is a resumable upload in progress.
+type Upload struct {
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+
+	// Length is a total size of uploaded file in bytes.
+	Length int64
+	// Offset is a number of bytes already received.
+	Offset int64
+
+	// Metadata of the document created when upload is finished.
+	Metadata Metadata
+
+	// StorageID is an id of staged upload in file repository.
+	StorageID string
+
+	Created time.Time
+}
@@ -0,0 +1,106 @@
+package purger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/docpurge"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/worker"
+)
+
+// FileRepository used to delete files of purged documents.
+type FileRepository interface {
+	docpurge.FileRepository
+}
+
+// MetadataRepository used to find and remove expired trashed documents.
+type MetadataRepository interface {
+	docpurge.MetadataRepository
+
+	// GetExpiredTrash get documents trashed earlier than olderThan ago.
+	// Only ID and OwnerID fields are filled.
+	// Returns error if get failed.
+	GetExpiredTrash(ctx context.Context, olderThan time.Duration) ([]models.Metadata, error)
+}
+
+// Settings used to create Purger.
+// Settings must be provided to New function.
+// All fields are required.
+type Settings struct {
+	// FileRepo used to delete files.
+	FileRepo FileRepository
+
+	// MetaRepo used to find and remove trashed documents.
+	MetaRepo MetadataRepository
+
+	// Interval between purges.
+	Interval time.Duration
+
+	// Retention is the time trashed document can be restored before it is purged.
+	Retention time.Duration
+}
+
+// Purger is a background worker that permanently removes documents from trash.
+//
+// On every purge it removes documents trashed longer than Retention ago
+// together with files of all their versions.
+//
+// Must be initialized with New function.
+type Purger struct {
+	fileRepo  FileRepository
+	metaRepo  MetadataRepository
+	interval  time.Duration
+	retention time.Duration
+}
+
+// New creates new Purger.
+// Returns pointer to Purger.
+// Accepts Settings as argument.
+func New(settings Settings) *Purger {
+	return &Purger{
+		fileRepo:  settings.FileRepo,
+		metaRepo:  settings.MetaRepo,
+		interval:  settings.Interval,
+		retention: settings.Retention,
+	}
+}
+
+// Start runs purges every interval and blocks until context is canceled.
+// Purge errors are logged and don't stop the purger.
+func (p *Purger) Start(ctx context.Context) error {
+	worker.Run(ctx, p.interval, func(ctx context.Context) {
+		if err := p.Purge(ctx); err != nil {
+			slog.Error("Error while purging trash", slog.Any("err", err))
+		}
+	})
+
+	return nil
+}
+
+// Purge permanently removes documents trashed longer than retention ago.
+// Returns error if purge failed.
+func (p *Purger) Purge(ctx context.Context) error {
+	expired, err := p.metaRepo.GetExpiredTrash(ctx, p.retention)
+	if err != nil {
+		return err
+	}
+
+	for _, meta := range expired {
+		err = docpurge.Purge(ctx, p.fileRepo, p.metaRepo, meta)
+		// Document was restored or already purged by its owner
+		if errors.Is(err, apperrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		slog.Info("Trashed document purged", slog.String("id", meta.ID.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package purger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/docpurge/docpurgetest"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMetaRepo struct {
+	docpurgetest.MetadataRepository
+
+	expired []models.Metadata
+}
+
+func (f *fakeMetaRepo) GetExpiredTrash(_ context.Context, _ time.Duration) ([]models.Metadata, error) {
+	return f.expired, nil
+}
+
+func TestPurger_Purge(t *testing.T) {
+	expiredID := uuid.New()
+	restoredID := uuid.New()
+	trashedID := uuid.New()
+	ownerID := uuid.New()
+
+	fileRepo := &docpurgetest.FileRepository{
+		Files: map[docpurgetest.File]bool{
+			{ID: expiredID, Version: 1}:  true,
+			{ID: expiredID, Version: 2}:  true,
+			{ID: restoredID, Version: 1}: true,
+			{ID: trashedID, Version: 1}:  true,
+		},
+	}
+	metaRepo := &fakeMetaRepo{
+		MetadataRepository: docpurgetest.MetadataRepository{
+			Versions: map[uuid.UUID][]models.Metadata{
+				expiredID:  {{Version: 2, File: true}, {Version: 1, File: true}},
+				restoredID: {{Version: 1, File: true}},
+				trashedID:  {{Version: 1, File: true}},
+			},
+			// Document restored after it was found is skipped
+			Kept: map[uuid.UUID]bool{restoredID: true},
+		},
+		expired: []models.Metadata{
+			{ID: &restoredID, OwnerID: &ownerID},
+			{ID: &expiredID, OwnerID: &ownerID},
+		},
+	}
+
+	p := New(Settings{
+		FileRepo:  fileRepo,
+		MetaRepo:  metaRepo,
+		Interval:  time.Minute,
+		Retention: time.Hour,
+	})
+
+	require.NoError(t, p.Purge(context.Background()))
+
+	assert.Equal(t, map[docpurgetest.File]bool{
+		{ID: restoredID, Version: 1}: true,
+		{ID: trashedID, Version: 1}:  true,
+	}, fileRepo.Files)
+	assert.NotContains(t, metaRepo.Versions, expiredID)
+	assert.Contains(t, metaRepo.Versions, restoredID)
+	assert.Contains(t, metaRepo.Versions, trashedID)
+}
@@ -0,0 +1,230 @@
+package reconciler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/worker"
+	"github.com/google/uuid"
+)
+
+// FileRepository used to list and delete stored files.
+type FileRepository interface {
+	// DeleteFile delete file from repository.
+	// Returns error if delete failed.
+	DeleteFile(ctx context.Context, meta models.Metadata) error
+
+	// WalkFiles calls fn for every file stored in repository.
+	// Only ID, OwnerID and Version fields of metadata are filled.
+	// Returns error if listing failed or fn returned error.
+	WalkFiles(ctx context.Context, fn func(meta models.Metadata) error) error
+
+	// RemoveUpload remove staged data of resumable upload.
+	// Returns error if remove failed.
+	RemoveUpload(ctx context.Context, upload models.Upload) error
+
+	// DeleteBlob delete content addressed blob with given checksum.
+	// Returns error if delete failed.
+	DeleteBlob(ctx context.Context, sha256 string) error
+}
+
+// MetadataRepository used to find and remove unfinished uploads.
+type MetadataRepository interface {
+	// GetStaleMetadata get versions that stay pending longer than olderThan.
+	// Returns error if get failed.
+	GetStaleMetadata(ctx context.Context, olderThan time.Duration) ([]models.Metadata, error)
+
+	// RemoveVersion permanently remove version from repository.
+	// Metadata of never promoted document is removed too.
+	// Returns error if remove failed.
+	RemoveVersion(ctx context.Context, id uuid.UUID, version int) error
+
+	// VersionExists check if version of not purged document exists and its file is not deduplicated.
+	// Returns error if check failed.
+	VersionExists(ctx context.Context, id uuid.UUID, version int) (bool, error)
+
+	// GetStaleUploads get resumable uploads created earlier than olderThan ago.
+	// Returns error if get failed.
+	GetStaleUploads(ctx context.Context, olderThan time.Duration) ([]models.Upload, error)
+
+	// RemoveUpload remove resumable upload from repository.
+	// Returns error if remove failed.
+	RemoveUpload(ctx context.Context, id uuid.UUID) error
+
+	// CollectBlobs call fn for every blob not referenced for longer than olderThan
+	// and remove the blob if fn succeeded.
+	// Returns error if collect failed or fn returned error.
+	CollectBlobs(ctx context.Context, olderThan time.Duration, fn func(sha256 string) error) error
+}
+
+// Settings used to create Reconciler.
+// Settings must be provided to New function.
+// All fields are required.
+type Settings struct {
+	// FileRepo used to list and delete files.
+	FileRepo FileRepository
+
+	// MetaRepo used to find and remove unfinished uploads.
+	MetaRepo MetadataRepository
+
+	// Interval between sweeps.
+	Interval time.Duration
+
+	// PendingTTL is the time after which pending upload is considered failed.
+	PendingTTL time.Duration
+
+	// UploadTTL is the time after which unfinished resumable upload is removed.
+	UploadTTL time.Duration
+
+	// BlobTTL is the time after which not referenced blob is removed.
+	BlobTTL time.Duration
+}
+
+// Reconciler is a background worker that cleans up after failed uploads.
+//
+// On every sweep it removes versions that stay pending longer than PendingTTL
+// together with their files, resumable uploads not finished in UploadTTL,
+// blobs not referenced for BlobTTL and removes files that have no version metadata.
+//
+// Must be initialized with New function.
+type Reconciler struct {
+	fileRepo   FileRepository
+	metaRepo   MetadataRepository
+	interval   time.Duration
+	pendingTTL time.Duration
+	uploadTTL  time.Duration
+	blobTTL    time.Duration
+}
+
+// New creates new Reconciler.
+// Returns pointer to Reconciler.
+// Accepts Settings as argument.
+func New(settings Settings) *Reconciler {
+	return &Reconciler{
+		fileRepo:   settings.FileRepo,
+		metaRepo:   settings.MetaRepo,
+		interval:   settings.Interval,
+		pendingTTL: settings.PendingTTL,
+		uploadTTL:  settings.UploadTTL,
+		blobTTL:    settings.BlobTTL,
+	}
+}
+
+// Start runs sweeps every interval and blocks until context is canceled.
+// Sweep errors are logged and don't stop the reconciler.
+func (r *Reconciler) Start(ctx context.Context) error {
+	worker.Run(ctx, r.interval, r.Sweep)
+
+	return nil
+}
+
+// Sweep removes stale pending uploads, unfinished resumable uploads, not referenced blobs
+// and orphaned files.
+func (r *Reconciler) Sweep(ctx context.Context) {
+	if err := r.sweepPending(ctx); err != nil {
+		slog.Error("Error while sweeping pending uploads", slog.Any("err", err))
+	}
+
+	if err := r.sweepUploads(ctx); err != nil {
+		slog.Error("Error while sweeping resumable uploads", slog.Any("err", err))
+	}
+
+	if err := r.sweepBlobs(ctx); err != nil {
+		slog.Error("Error while sweeping blobs", slog.Any("err", err))
+	}
+
+	if err := r.sweepOrphans(ctx); err != nil {
+		slog.Error("Error while sweeping orphaned files", slog.Any("err", err))
+	}
+}
+
+// sweepPending removes versions and files of uploads that stay pending longer than pendingTTL.
+func (r *Reconciler) sweepPending(ctx context.Context) error {
+	stale, err := r.metaRepo.GetStaleMetadata(ctx, r.pendingTTL)
+	if err != nil {
+		return err
+	}
+
+	for _, meta := range stale {
+		// File must be removed first, otherwise it will become orphaned
+		if err = r.fileRepo.DeleteFile(ctx, meta); err != nil {
+			return err
+		}
+
+		if err = r.metaRepo.RemoveVersion(ctx, *meta.ID, meta.Version); err != nil {
+			return err
+		}
+
+		slog.Info(
+			"Stale upload removed",
+			slog.String("id", meta.ID.String()),
+			slog.Int("version", meta.Version),
+		)
+	}
+
+	return nil
+}
+
+// sweepUploads removes resumable uploads that are not finished in uploadTTL with their staged data.
+func (r *Reconciler) sweepUploads(ctx context.Context) error {
+	stale, err := r.metaRepo.GetStaleUploads(ctx, r.uploadTTL)
+	if err != nil {
+		return err
+	}
+
+	for _, upload := range stale {
+		// Staged data must be removed first, otherwise it will be left forever
+		if err = r.fileRepo.RemoveUpload(ctx, upload); err != nil {
+			return err
+		}
+
+		if err = r.metaRepo.RemoveUpload(ctx, upload.ID); err != nil {
+			return err
+		}
+
+		slog.Info("Stale resumable upload removed", slog.String("id", upload.ID.String()))
+	}
+
+	return nil
+}
+
+// sweepBlobs removes blobs that are not referenced by any version for longer than blobTTL.
+func (r *Reconciler) sweepBlobs(ctx context.Context) error {
+	return r.metaRepo.CollectBlobs(ctx, r.blobTTL, func(sha256 string) error {
+		if err := r.fileRepo.DeleteBlob(ctx, sha256); err != nil {
+			return err
+		}
+
+		slog.Info("Blob removed", slog.String("sha256", sha256))
+
+		return nil
+	})
+}
+
+// sweepOrphans removes files that have no version metadata, including files of purged documents.
+func (r *Reconciler) sweepOrphans(ctx context.Context) error {
+	return r.fileRepo.WalkFiles(ctx, func(meta models.Metadata) error {
+		exists, err := r.metaRepo.VersionExists(ctx, *meta.ID, meta.Version)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return nil
+		}
+
+		if err = r.fileRepo.DeleteFile(ctx, meta); err != nil {
+			return err
+		}
+
+		slog.Info(
+			"Orphaned file removed",
+			slog.String("id", meta.ID.String()),
+			slog.Int("version", meta.Version),
+		)
+
+		return nil
+	})
+}
@@ -0,0 +1,150 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFileRepo struct {
+	files   map[uuid.UUID]models.Metadata
+	uploads map[uuid.UUID]models.Upload
+	blobs   map[string]bool
+}
+
+func (f *fakeFileRepo) DeleteFile(_ context.Context, meta models.Metadata) error {
+	delete(f.files, *meta.ID)
+	return nil
+}
+
+func (f *fakeFileRepo) WalkFiles(_ context.Context, fn func(meta models.Metadata) error) error {
+	for _, meta := range f.files {
+		if err := fn(meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeFileRepo) RemoveUpload(_ context.Context, upload models.Upload) error {
+	delete(f.uploads, upload.ID)
+	return nil
+}
+
+func (f *fakeFileRepo) DeleteBlob(_ context.Context, sha256 string) error {
+	delete(f.blobs, sha256)
+	return nil
+}
+
+type fakeMetaRepo struct {
+	meta         map[uuid.UUID]models.Metadata
+	stale        []models.Metadata
+	uploads      map[uuid.UUID]models.Upload
+	staleUploads []models.Upload
+	blobs        map[string]int
+}
+
+func (f *fakeMetaRepo) GetStaleMetadata(_ context.Context, _ time.Duration) ([]models.Metadata, error) {
+	return f.stale, nil
+}
+
+func (f *fakeMetaRepo) RemoveVersion(_ context.Context, id uuid.UUID, _ int) error {
+	delete(f.meta, id)
+	return nil
+}
+
+func (f *fakeMetaRepo) VersionExists(_ context.Context, id uuid.UUID, _ int) (bool, error) {
+	_, ok := f.meta[id]
+	return ok, nil
+}
+
+func (f *fakeMetaRepo) GetStaleUploads(_ context.Context, _ time.Duration) ([]models.Upload, error) {
+	return f.staleUploads, nil
+}
+
+func (f *fakeMetaRepo) RemoveUpload(_ context.Context, id uuid.UUID) error {
+	delete(f.uploads, id)
+	return nil
+}
+
+func (f *fakeMetaRepo) CollectBlobs(
+	_ context.Context,
+	_ time.Duration,
+	fn func(sha256 string) error,
+) error {
+	for sha256, refs := range f.blobs {
+		if refs > 0 {
+			continue
+		}
+		if err := fn(sha256); err != nil {
+			return err
+		}
+		delete(f.blobs, sha256)
+	}
+	return nil
+}
+
+func newMeta() models.Metadata {
+	id := uuid.New()
+	ownerID := uuid.New()
+	return models.Metadata{
+		ID:      &id,
+		OwnerID: &ownerID,
+	}
+}
+
+func TestReconciler_Sweep(t *testing.T) {
+	ready := newMeta()
+	pending := newMeta()
+	orphan := newMeta()
+	active := models.Upload{ID: uuid.New()}
+	stale := models.Upload{ID: uuid.New()}
+
+	fileRepo := &fakeFileRepo{
+		files: map[uuid.UUID]models.Metadata{
+			*ready.ID:   ready,
+			*pending.ID: pending,
+			*orphan.ID:  orphan,
+		},
+		uploads: map[uuid.UUID]models.Upload{
+			active.ID: active,
+			stale.ID:  stale,
+		},
+		blobs: map[string]bool{"used": true, "unused": true},
+	}
+	metaRepo := &fakeMetaRepo{
+		meta: map[uuid.UUID]models.Metadata{
+			*ready.ID:   ready,
+			*pending.ID: pending,
+		},
+		stale: []models.Metadata{pending},
+		uploads: map[uuid.UUID]models.Upload{
+			active.ID: active,
+			stale.ID:  stale,
+		},
+		staleUploads: []models.Upload{stale},
+		blobs:        map[string]int{"used": 1, "unused": 0},
+	}
+
+	r := New(Settings{
+		FileRepo:   fileRepo,
+		MetaRepo:   metaRepo,
+		Interval:   time.Minute,
+		PendingTTL: time.Hour,
+		UploadTTL:  time.Hour,
+		BlobTTL:    time.Hour,
+	})
+
+	r.Sweep(context.Background())
+
+	assert.Equal(t, map[uuid.UUID]models.Metadata{*ready.ID: ready}, fileRepo.files)
+	assert.Equal(t, map[uuid.UUID]models.Metadata{*ready.ID: ready}, metaRepo.meta)
+	assert.Equal(t, map[uuid.UUID]models.Upload{active.ID: active}, fileRepo.uploads)
+	assert.Equal(t, map[uuid.UUID]models.Upload{active.ID: active}, metaRepo.uploads)
+	assert.Equal(t, map[string]bool{"used": true}, fileRepo.blobs)
+	assert.Equal(t, map[string]int{"used": 1}, metaRepo.blobs)
+}
@@ -0,0 +1,135 @@
+package comprepo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// errMalformedFrame is returned if decompressed frame size doesn't match index.
+var errMalformedFrame = errors.New("malformed compressed frame")
+
+// codec compresses and decompresses independent frames.
+type codec interface {
+	// encoding returns HTTP content coding of compressed stream.
+	encoding() string
+
+	// compress appends compressed src to dst.
+	compress(dst, src []byte) ([]byte, error)
+
+	// decompress appends decompressed src to dst.
+	// Returns errMalformedFrame if decompressed frame is not size bytes long.
+	decompress(dst, src []byte, size int) ([]byte, error)
+}
+
+// gzipCodec compresses frames as gzip members.
+type gzipCodec struct{}
+
+// encoding implements codec interface.
+func (gzipCodec) encoding() string {
+	return EncodingGzip
+}
+
+// compress implements codec interface.
+func (gzipCodec) compress(dst, src []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress implements codec interface.
+func (gzipCodec) decompress(dst, src []byte, size int) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	r.Multistream(false)
+
+	start := len(dst)
+	dst = slices.Grow(dst, size)[:start+size]
+	if _, err = io.ReadFull(r, dst[start:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errMalformedFrame
+		}
+		return nil, err
+	}
+
+	// Frame must end here, checksum is verified at the end of the member
+	n, err := r.Read(make([]byte, 1))
+	if n > 0 {
+		return nil, errMalformedFrame
+	}
+	if !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// zstdCodec compresses frames as zstd frames.
+// Encoder and decoder are safe for concurrent use.
+type zstdCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// newZstdCodec creates zstdCodec.
+func newZstdCodec() (zstdCodec, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return zstdCodec{}, err
+	}
+
+	decoder, err := zstd.NewReader(
+		nil,
+		zstd.WithDecoderConcurrency(0),
+		zstd.WithDecoderMaxMemory(frameSize),
+	)
+	if err != nil {
+		return zstdCodec{}, err
+	}
+
+	return zstdCodec{
+		encoder: encoder,
+		decoder: decoder,
+	}, nil
+}
+
+// encoding implements codec interface.
+func (zstdCodec) encoding() string {
+	return EncodingZstd
+}
+
+// compress implements codec interface.
+func (c zstdCodec) compress(dst, src []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(src, dst), nil
+}
+
+// decompress implements codec interface.
+func (c zstdCodec) decompress(dst, src []byte, size int) ([]byte, error) {
+	start := len(dst)
+
+	dst, err := c.decoder.DecodeAll(src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dst)-start != size {
+		return nil, errMalformedFrame
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,253 @@
+// Package comprepo compresses files stored in file repository.
+//
+// Files are compressed with codec chosen by MIME type of the document.
+// Compressed file is a sequence of independently compressed frames followed by index of frames,
+// so any range of the file can be decompressed without reading the whole file.
+// The whole compressed file is a valid gzip or zstd stream and can be sent to clients as is.
+//
+// Encoding of stored file is saved in document metadata, see FileEncoding.
+// Files of not compressed types are read as is. Encoding of files stored before encodings were saved
+// is detected by index at the end of the file.
+package comprepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// Supported encodings.
+const (
+	EncodingGzip = "gzip"
+	EncodingZstd = "zstd"
+)
+
+// FileRepository used to store compressed files.
+type FileRepository interface {
+	// UploadFile upload file to repository.
+	// Returns error if upload failed.
+	// Returns size of stored file if upload was successful.
+	UploadFile(ctx context.Context, file io.Reader, meta models.Metadata) (int64, error)
+
+	// GetFile get file from repository.
+	// Returns error if get failed.
+	// Returns io.ReadSeekCloser if get was successful.
+	GetFile(ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error)
+
+	// DeleteFile delete file from repository.
+	// Returns error if delete failed.
+	DeleteFile(ctx context.Context, meta models.Metadata) error
+
+	// StoreBlob move file to content addressed blob meta.SHA256.
+	// Returns error if move failed.
+	StoreBlob(ctx context.Context, meta models.Metadata) error
+}
+
+// Rule selects encoding of files with matching MIME type.
+type Rule struct {
+	// MimeType is a MIME type without parameters.
+	// Subtype can be "*" to match any subtype, "*/*" matches any type.
+	MimeType string
+
+	// Encoding is EncodingGzip or EncodingZstd.
+	Encoding string
+}
+
+// matches reports whether rule matches MIME type without parameters.
+func (r Rule) matches(mimeType string) bool {
+	if r.MimeType == "*/*" || r.MimeType == mimeType {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(r.MimeType, "/*")
+	return ok && strings.HasPrefix(mimeType, prefix+"/")
+}
+
+// ParseRules parses comma separated list of rules in format <mime type>:<encoding>.
+// Returns error if list is empty or any rule is malformed.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, item := range strings.Split(s, ",") {
+		mimeType, encoding, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || !strings.Contains(mimeType, "/") {
+			return nil, errors.New("compression rule must have format <mime type>:<encoding>")
+		}
+
+		if encoding != EncodingGzip && encoding != EncodingZstd {
+			return nil, fmt.Errorf("compression rule %s: unknown encoding %s", mimeType, encoding)
+		}
+
+		rules = append(rules, Rule{MimeType: strings.ToLower(mimeType), Encoding: encoding})
+	}
+
+	return rules, nil
+}
+
+// Settings used to create CompressionRepository.
+// Settings must be provided to New function.
+// All fields are required.
+type Settings struct {
+	// Repo used to store compressed files.
+	Repo FileRepository
+
+	// Rules select encoding of uploaded files by MIME type.
+	// The first matching rule is used, files not matching any rule are stored as is.
+	Rules []Rule
+}
+
+// CompressionRepository is a FileRepository wrapper that compresses files on upload
+// and decompresses them on download.
+// Must be initialized with New function.
+type CompressionRepository struct {
+	repo   FileRepository
+	rules  []Rule
+	codecs map[string]codec
+}
+
+// New creates new CompressionRepository.
+// Returns error if codecs can't be created.
+func New(settings Settings) (*CompressionRepository, error) {
+	zstdCodec, err := newZstdCodec()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompressionRepository{
+		repo:  settings.Repo,
+		rules: settings.Rules,
+		codecs: map[string]codec{
+			EncodingGzip: gzipCodec{},
+			EncodingZstd: zstdCodec,
+		},
+	}, nil
+}
+
+// FileEncoding returns encoding of the document file stored by UploadFile:
+// encoding of the first rule matching meta.Mime or models.FileEncodingIdentity if file is stored as is.
+func (r *CompressionRepository) FileEncoding(meta models.Metadata) string {
+	if c := r.codecFor(meta.Mime); c != nil {
+		return c.encoding()
+	}
+
+	return models.FileEncodingIdentity
+}
+
+// UploadFile compresses file with codec of meta.Encoding and uploads it to the wrapped repository.
+// If meta.Encoding is empty, codec is selected by meta.Mime.
+//
+// Size of compressed file is not known in advance, so compressed file is uploaded with unknown size.
+// File of not compressed type is uploaded as is.
+//
+// Returns an error if the upload fails.
+// Returns size of stored file if upload was successful.
+func (r *CompressionRepository) UploadFile(
+	ctx context.Context,
+	file io.Reader,
+	meta models.Metadata,
+) (int64, error) {
+	if meta.Encoding == "" {
+		meta.Encoding = r.FileEncoding(meta)
+	}
+
+	c, ok := r.codecs[meta.Encoding]
+	if !ok {
+		return r.repo.UploadFile(ctx, file, meta)
+	}
+
+	meta.FileSize = -1
+
+	return r.repo.UploadFile(ctx, newCompressReader(file, c), meta)
+}
+
+// GetFile gets file from the wrapped repository and decompresses it.
+//
+// Returned reader decompresses only frames that are read, so seeking
+// and range requests don't require reading the whole file.
+// Returned reader also provides the compressed file, see Encoded method.
+// File stored as is is returned as is.
+//
+// Returns error if get failed or stored file doesn't match meta.Encoding.
+func (r *CompressionRepository) GetFile(ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error) {
+	file, err := r.repo.GetFile(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if meta.Encoding == models.FileEncodingIdentity {
+		return file, nil
+	}
+
+	decompressed, err := r.openFile(file)
+	switch {
+	case err == nil && meta.Encoding != "" && decompressed.ContentEncoding() != meta.Encoding:
+		err = fmt.Errorf("file is compressed with %s, %s expected", decompressed.ContentEncoding(), meta.Encoding)
+	case errors.Is(err, errNotCompressed) && meta.Encoding == "":
+		// File stored before encodings were saved
+		_, err = file.Seek(0, io.SeekStart)
+		if err == nil {
+			return file, nil
+		}
+	}
+	if err != nil {
+		//nolint:errcheck // ignore
+		file.Close()
+		return nil, err
+	}
+
+	return decompressed, nil
+}
+
+// DeleteFile removes file from the wrapped repository.
+func (r *CompressionRepository) DeleteFile(ctx context.Context, meta models.Metadata) error {
+	return r.repo.DeleteFile(ctx, meta)
+}
+
+// StoreBlob moves file to content addressed blob in the wrapped repository.
+// Compressed file is moved as is.
+func (r *CompressionRepository) StoreBlob(ctx context.Context, meta models.Metadata) error {
+	return r.repo.StoreBlob(ctx, meta)
+}
+
+// codecFor returns codec of the first rule matching MIME type.
+// Returns nil if file of this type must not be compressed.
+func (r *CompressionRepository) codecFor(mimeType string) codec {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return nil
+	}
+
+	for _, rule := range r.rules {
+		if rule.matches(mediaType) {
+			return r.codecs[rule.Encoding]
+		}
+	}
+
+	return nil
+}
+
+// openFile reads index of stored file and returns decompressing reader.
+// Returns errNotCompressed if file has no index.
+func (r *CompressionRepository) openFile(file io.ReadSeekCloser) (*decompressReader, error) {
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := readIndex(file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := r.codecs[idx.encoding]
+	if !ok {
+		return nil, errNotCompressed
+	}
+
+	return newDecompressReader(file, c, idx), nil
+}
@@ -0,0 +1,277 @@
+package comprepo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/repository/fsrepo"
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFileRepo(t *testing.T) *fsrepo.FSRepository {
+	repo, err := fsrepo.New(context.Background(), fsrepo.Settings{Root: t.TempDir()})
+	require.NoError(t, err)
+	return repo
+}
+
+// readStored returns file as it is stored in the wrapped repository.
+func readStored(t *testing.T, fileRepo *fsrepo.FSRepository, meta models.Metadata) []byte {
+	t.Helper()
+
+	file, err := fileRepo.GetFile(context.Background(), meta)
+	require.NoError(t, err)
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return data
+}
+
+func newMeta(mimeType string, size int64) models.Metadata {
+	id := uuid.New()
+	ownerID := uuid.New()
+	return models.Metadata{
+		ID:       &id,
+		OwnerID:  &ownerID,
+		Mime:     mimeType,
+		FileSize: size,
+	}
+}
+
+// testData returns compressible data of given size.
+func testData(size int) []byte {
+	words := []string{"lorem ", "ipsum ", "dolor ", "sit ", "amet "}
+	rnd := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // test data
+
+	data := make([]byte, 0, size+len("lorem "))
+	for len(data) < size {
+		data = append(data, words[rnd.IntN(len(words))]...)
+	}
+
+	return data[:size]
+}
+
+func TestCompressionRepository_UploadGet(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "small", size: 10},
+		{name: "one frame", size: frameSize},
+		{name: "frame and byte", size: frameSize + 1},
+		{name: "many frames", size: 2*frameSize + 5},
+	}
+
+	ctx := context.Background()
+
+	for _, encoding := range []string{EncodingGzip, EncodingZstd} {
+		for _, tt := range tests {
+			t.Run(encoding+" "+tt.name, func(t *testing.T) {
+				fileRepo := newFileRepo(t)
+				repo, err := New(Settings{
+					Repo:  fileRepo,
+					Rules: []Rule{{MimeType: "text/*", Encoding: encoding}},
+				})
+				require.NoError(t, err)
+
+				data := testData(tt.size)
+				meta := newMeta("text/plain; charset=utf-8", int64(tt.size))
+				meta.Encoding = repo.FileEncoding(meta)
+				assert.Equal(t, encoding, meta.Encoding)
+
+				storedSize, err := repo.UploadFile(ctx, bytes.NewReader(data), meta)
+				require.NoError(t, err)
+
+				stored := readStored(t, fileRepo, meta)
+				assert.Equal(t, int64(len(stored)), storedSize)
+				if tt.size > frameSize {
+					assert.Less(t, len(stored), tt.size)
+				}
+
+				file, err := repo.GetFile(ctx, meta)
+				require.NoError(t, err)
+				defer file.Close()
+
+				// Range read
+				if tt.size > 2 {
+					offset := int64(tt.size / 2)
+					_, err = file.Seek(offset, io.SeekStart)
+					require.NoError(t, err)
+
+					var got []byte
+					got, err = io.ReadAll(io.LimitReader(file, 2))
+					require.NoError(t, err)
+					assert.Equal(t, data[offset:offset+2], got)
+				}
+
+				end, err := file.Seek(0, io.SeekEnd)
+				require.NoError(t, err)
+				assert.Equal(t, int64(tt.size), end)
+
+				_, err = file.Seek(0, io.SeekStart)
+				require.NoError(t, err)
+
+				got, err := io.ReadAll(file)
+				require.NoError(t, err)
+				assert.Equal(t, data, got)
+
+				// Stored file is a valid stream of its encoding
+				encoded, ok := file.(interface {
+					ContentEncoding() string
+					Encoded() (io.Reader, int64, error)
+				})
+				require.True(t, ok)
+				assert.Equal(t, encoding, encoded.ContentEncoding())
+
+				body, size, err := encoded.Encoded()
+				require.NoError(t, err)
+				assert.Equal(t, storedSize, size)
+
+				assert.Equal(t, data, decode(t, encoding, body))
+			})
+		}
+	}
+}
+
+func decode(t *testing.T, encoding string, r io.Reader) []byte {
+	var (
+		decoded io.Reader
+		err     error
+	)
+
+	switch encoding {
+	case EncodingGzip:
+		decoded, err = gzip.NewReader(r)
+	case EncodingZstd:
+		decoded, err = zstd.NewReader(r)
+	}
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(decoded)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestCompressionRepository_NotCompressed(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newFileRepo(t)
+	repo, err := New(Settings{
+		Repo:  fileRepo,
+		Rules: []Rule{{MimeType: "text/*", Encoding: EncodingZstd}},
+	})
+	require.NoError(t, err)
+
+	meta := newMeta("image/png", int64(len("test data")))
+	meta.Encoding = repo.FileEncoding(meta)
+	assert.Equal(t, models.FileEncodingIdentity, meta.Encoding)
+
+	storedSize, err := repo.UploadFile(ctx, bytes.NewReader([]byte("test data")), meta)
+	require.NoError(t, err)
+	assert.Equal(t, meta.FileSize, storedSize)
+	assert.Equal(t, []byte("test data"), readStored(t, fileRepo, meta))
+
+	file, err := repo.GetFile(ctx, meta)
+	require.NoError(t, err)
+
+	_, ok := file.(*decompressReader)
+	assert.False(t, ok)
+
+	got, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "test data", string(got))
+}
+
+func TestCompressionRepository_Encoding(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newFileRepo(t)
+	repo, err := New(Settings{
+		Repo:  fileRepo,
+		Rules: []Rule{{MimeType: "text/*", Encoding: EncodingZstd}},
+	})
+	require.NoError(t, err)
+
+	// Not compressed file that ends with valid index
+	compressed := newMeta("text/plain", 100)
+	_, err = repo.UploadFile(ctx, bytes.NewReader(testData(100)), compressed)
+	require.NoError(t, err)
+	lookalike := readStored(t, fileRepo, compressed)
+
+	tests := []struct {
+		name     string
+		encoding string
+		want     []byte
+		wantErr  bool
+	}{
+		{name: "identity", encoding: models.FileEncodingIdentity, want: lookalike},
+		{name: "zstd", encoding: EncodingZstd, want: testData(100)},
+		{name: "other encoding", encoding: EncodingGzip, wantErr: true},
+		{name: "unknown", encoding: "", want: testData(100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := newMeta("application/octet-stream", int64(len(lookalike)))
+			meta.Encoding = models.FileEncodingIdentity
+			_, err := repo.UploadFile(ctx, bytes.NewReader(lookalike), meta)
+			require.NoError(t, err)
+
+			meta.Encoding = tt.encoding
+			file, err := repo.GetFile(ctx, meta)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer file.Close()
+
+			got, err := io.ReadAll(file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []Rule
+		wantErr bool
+	}{
+		{
+			name: "many rules",
+			s:    "text/*:zstd, application/JSON:gzip",
+			want: []Rule{
+				{MimeType: "text/*", Encoding: EncodingZstd},
+				{MimeType: "application/json", Encoding: EncodingGzip},
+			},
+		},
+		{name: "empty", s: "", wantErr: true},
+		{name: "no encoding", s: "text/*", wantErr: true},
+		{name: "unknown encoding", s: "text/*:br", wantErr: true},
+		{name: "no subtype", s: "text:gzip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseRules(tt.s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rules)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package comprepo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+const (
+	// frameSize is the size of plaintext compressed as one independent frame.
+	frameSize = 4 << 20
+
+	// indexFooterSize is the size of plaintext size, frames count and magic at the end of index.
+	indexFooterSize = 16
+
+	// zstdTrailerHeaderSize is the size of zstd skippable frame header.
+	zstdTrailerHeaderSize = 8
+
+	// zstdSkippableMagic is the magic number of zstd skippable frame ignored by decoders.
+	zstdSkippableMagic = 0x184D2A5E
+
+	// gzipTrailerHeaderSize is the size of gzip member header with extra field length
+	// and extra subfield header.
+	gzipTrailerHeaderSize = 16
+
+	// maxGzipFrames limits number of frames in gzip index, extra field can't be longer than 64KiB.
+	maxGzipFrames = (math.MaxUint16 - 4 - indexFooterSize) / 4
+)
+
+const (
+	// zstdIndexMagic ends index of zstd compressed file.
+	zstdIndexMagic = "FSCZ"
+
+	// gzipIndexMagic ends index of gzip compressed file.
+	gzipIndexMagic = "FSCG"
+
+	// gzipTrailerTail ends empty gzip member: empty final deflate block, CRC-32 and size of empty data.
+	gzipTrailerTail = "\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00"
+)
+
+var (
+	// errNotCompressed is returned by readIndex if file has no valid index.
+	errNotCompressed = errors.New("file is not compressed")
+
+	// errTooManyFrames is returned if file is too large to be indexed.
+	errTooManyFrames = errors.New("file is too large to be compressed")
+)
+
+// Compressed file consists of frames followed by trailer.
+// Every frame is frameSize bytes of plaintext compressed independently, except the last one
+// that can be shorter. File with empty plaintext has one empty frame.
+//
+// Trailer contains index of frames: compressed size of every frame (4 bytes each),
+// plaintext size (8 bytes), frames count (4 bytes) and magic, all big endian.
+// Trailer is valid part of compressed stream that decodes to nothing: zstd skippable frame
+// or empty gzip member with index in extra field. Magic is at the end of zstd trailer,
+// and right before the fixed gzipTrailerTail in gzip trailer.
+
+// index is an index of compressed file frames.
+type index struct {
+	encoding   string
+	fileSize   int64
+	storedSize int64
+	frames     []int64
+}
+
+// framesCount returns number of frames of file with given plaintext size.
+func framesCount(size int64) int64 {
+	if size == 0 {
+		return 1
+	}
+	return (size + frameSize - 1) / frameSize
+}
+
+// encodeTrailer returns trailer of compressed file with given encoding,
+// compressed frame sizes and plaintext size.
+func encodeTrailer(encoding string, frames []uint32, fileSize int64) []byte {
+	payloadSize := len(frames)*4 + indexFooterSize
+
+	var buf []byte
+	switch encoding {
+	case EncodingZstd:
+		buf = make([]byte, 0, zstdTrailerHeaderSize+payloadSize)
+		buf = binary.LittleEndian.AppendUint32(buf, zstdSkippableMagic)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(payloadSize)) //nolint:gosec // limited by frames count
+	default:
+		buf = make([]byte, 0, gzipTrailerHeaderSize+payloadSize+len(gzipTrailerTail))
+		// Magic, deflate method, FEXTRA flag, no mtime, no extra flags, unknown OS
+		buf = append(buf, 0x1f, 0x8b, 8, 4, 0, 0, 0, 0, 0, 0xff)
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(payloadSize+4)) //nolint:gosec,mnd // subfield header
+		buf = append(buf, 'F', 'S')
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(payloadSize)) //nolint:gosec // limited by maxGzipFrames
+	}
+
+	for _, size := range frames {
+		buf = binary.BigEndian.AppendUint32(buf, size)
+	}
+	buf = binary.BigEndian.AppendUint64(buf, uint64(fileSize))    //nolint:gosec // size is not negative
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(frames))) //nolint:gosec // limited by file size
+
+	if encoding == EncodingZstd {
+		return append(buf, zstdIndexMagic...)
+	}
+
+	buf = append(buf, gzipIndexMagic...)
+	return append(buf, gzipTrailerTail...)
+}
+
+// readIndex reads and decodes index of compressed file with given size from r.
+// Returns errNotCompressed if r has no valid index.
+func readIndex(r io.ReadSeeker, size int64) (index, error) {
+	tail, err := readAt(r, size-min(size, indexFooterSize+int64(len(gzipTrailerTail))), size)
+	if err != nil {
+		return index{}, err
+	}
+
+	idx := index{storedSize: size}
+
+	var headerSize, footerEnd int
+	switch {
+	case size >= zstdTrailerHeaderSize+indexFooterSize && bytes.HasSuffix(tail, []byte(zstdIndexMagic)):
+		idx.encoding = EncodingZstd
+		headerSize = zstdTrailerHeaderSize
+		footerEnd = len(tail)
+	case size >= gzipTrailerHeaderSize+indexFooterSize+int64(len(gzipTrailerTail)) &&
+		bytes.HasSuffix(tail, []byte(gzipIndexMagic+gzipTrailerTail)):
+		idx.encoding = EncodingGzip
+		headerSize = gzipTrailerHeaderSize
+		footerEnd = len(tail) - len(gzipTrailerTail)
+	default:
+		return index{}, errNotCompressed
+	}
+
+	footer := tail[footerEnd-indexFooterSize : footerEnd]
+	fileSize := binary.BigEndian.Uint64(footer)
+	count := int64(binary.BigEndian.Uint32(footer[8:]))
+
+	if fileSize > math.MaxInt64 || framesCount(int64(fileSize)) != count {
+		return index{}, errNotCompressed
+	}
+	idx.fileSize = int64(fileSize)
+
+	payloadSize := count*4 + indexFooterSize
+	trailerSize := int64(headerSize) + payloadSize + int64(len(tail)-footerEnd)
+	if trailerSize > size {
+		return index{}, errNotCompressed
+	}
+
+	trailer, err := readAt(r, size-trailerSize, size-trailerSize+int64(headerSize)+count*4)
+	if err != nil {
+		return index{}, err
+	}
+
+	if !validTrailerHeader(idx.encoding, trailer[:headerSize], payloadSize) {
+		return index{}, errNotCompressed
+	}
+
+	// Frames must fill the file up to trailer
+	idx.frames = make([]int64, count)
+	var framesSize int64
+	for i := range idx.frames {
+		idx.frames[i] = int64(binary.BigEndian.Uint32(trailer[headerSize+i*4:]))
+		framesSize += idx.frames[i]
+	}
+
+	if framesSize+trailerSize != size {
+		return index{}, errNotCompressed
+	}
+
+	return idx, nil
+}
+
+// validTrailerHeader reports whether header of trailer matches encoding and index payload size.
+func validTrailerHeader(encoding string, header []byte, payloadSize int64) bool {
+	if encoding == EncodingZstd {
+		return binary.LittleEndian.Uint32(header) == zstdSkippableMagic &&
+			int64(binary.LittleEndian.Uint32(header[4:])) == payloadSize
+	}
+
+	return bytes.Equal(header[:4], []byte{0x1f, 0x8b, 8, 4}) &&
+		int64(binary.LittleEndian.Uint16(header[10:])) == payloadSize+4 &&
+		header[12] == 'F' && header[13] == 'S' &&
+		int64(binary.LittleEndian.Uint16(header[14:])) == payloadSize
+}
+
+// readAt reads bytes from start to end of r.
+func readAt(r io.ReadSeeker, start, end int64) ([]byte, error) {
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, end-start)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,211 @@
+package comprepo
+
+import (
+	"errors"
+	"io"
+)
+
+// compressReader compresses plaintext read from r.
+// Compressed frames are returned first, then trailer with index of frames.
+type compressReader struct {
+	r      io.Reader
+	codec  codec
+	plain  []byte
+	buf    []byte
+	out    []byte
+	frames []uint32
+	size   int64
+	done   bool
+}
+
+// newCompressReader creates compressReader with given codec.
+func newCompressReader(r io.Reader, c codec) *compressReader {
+	return &compressReader{
+		r:     r,
+		codec: c,
+		plain: make([]byte, frameSize),
+	}
+}
+
+// Read implements io.Reader interface.
+func (c *compressReader) Read(p []byte) (int, error) {
+	for len(c.out) == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+
+		if err := c.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, c.out)
+	c.out = c.out[n:]
+	return n, nil
+}
+
+// nextFrame reads and compresses the next frame of plaintext.
+// Trailer is returned after the last frame.
+func (c *compressReader) nextFrame() error {
+	n, err := io.ReadFull(c.r, c.plain)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	// File ends at frame boundary, empty file still has one frame
+	if n == 0 && len(c.frames) > 0 {
+		c.out = encodeTrailer(c.codec.encoding(), c.frames, c.size)
+		c.done = true
+		return nil
+	}
+
+	if c.codec.encoding() == EncodingGzip && len(c.frames) >= maxGzipFrames {
+		return errTooManyFrames
+	}
+
+	c.buf, err = c.codec.compress(c.buf[:0], c.plain[:n])
+	if err != nil {
+		return err
+	}
+
+	c.out = c.buf
+	c.frames = append(c.frames, uint32(len(c.buf))) //nolint:gosec // compressed frame is small
+	c.size += int64(n)
+
+	if n < frameSize {
+		c.out = append(c.out, encodeTrailer(c.codec.encoding(), c.frames, c.size)...)
+		c.done = true
+	}
+
+	return nil
+}
+
+// decompressReader decompresses compressed file read from r.
+// Seeking is supported, only frames containing read data are read and decompressed.
+// r is seeked only when frames are not read sequentially.
+type decompressReader struct {
+	r       io.ReadSeekCloser
+	codec   codec
+	idx     index
+	offsets []int64
+	rawPos  int64
+
+	pos   int64
+	frame int64
+	plain []byte
+	buf   []byte
+}
+
+// newDecompressReader creates decompressReader of file with given index.
+func newDecompressReader(r io.ReadSeekCloser, c codec, idx index) *decompressReader {
+	offsets := make([]int64, len(idx.frames)+1)
+	for i, size := range idx.frames {
+		offsets[i+1] = offsets[i] + size
+	}
+
+	return &decompressReader{
+		r:       r,
+		codec:   c,
+		idx:     idx,
+		offsets: offsets,
+		rawPos:  -1,
+		frame:   -1,
+	}
+}
+
+// ContentEncoding returns HTTP content coding of the compressed file.
+func (d *decompressReader) ContentEncoding() string {
+	return d.codec.encoding()
+}
+
+// Encoded returns reader of the compressed file as it is stored and its size.
+// The compressed file is a valid stream of ContentEncoding.
+// Reader shares position with d, so d must not be read after that.
+func (d *decompressReader) Encoded() (io.Reader, int64, error) {
+	if _, err := d.r.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	d.rawPos = -1
+
+	return d.r, d.idx.storedSize, nil
+}
+
+// Read implements io.Reader interface.
+func (d *decompressReader) Read(p []byte) (int, error) {
+	if d.pos >= d.idx.fileSize {
+		return 0, io.EOF
+	}
+
+	frame := d.pos / frameSize
+	if frame != d.frame {
+		if err := d.openFrame(frame); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain[d.pos-frame*frameSize:])
+	d.pos += int64(n)
+	return n, nil
+}
+
+// Seek implements io.Seeker interface.
+func (d *decompressReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = d.pos + offset
+	case io.SeekEnd:
+		pos = d.idx.fileSize + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	d.pos = pos
+	return pos, nil
+}
+
+// Close implements io.Closer interface.
+func (d *decompressReader) Close() error {
+	return d.r.Close()
+}
+
+// openFrame reads and decompresses frame with given index.
+func (d *decompressReader) openFrame(frame int64) error {
+	size := min(d.idx.fileSize-frame*frameSize, frameSize)
+
+	offset := d.offsets[frame]
+	if offset != d.rawPos {
+		if _, err := d.r.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		d.rawPos = offset
+	}
+
+	compressedSize := d.offsets[frame+1] - offset
+	if int64(cap(d.buf)) < compressedSize {
+		d.buf = make([]byte, compressedSize)
+	}
+
+	n, err := io.ReadFull(d.r, d.buf[:compressedSize])
+	d.rawPos += int64(n)
+	if err != nil {
+		return err
+	}
+
+	plain, err := d.codec.decompress(d.plain[:0], d.buf[:compressedSize], int(size))
+	if err != nil {
+		d.frame = -1
+		return err
+	}
+
+	d.plain = plain
+	d.frame = frame
+
+	return nil
+}
@@ -0,0 +1,264 @@
+// Package cryptorepo encrypts files stored in file repository.
+//
+// Envelope encryption is used: every stored file is encrypted with its own random data key,
+// and the data key is wrapped by master key and stored in the header of encrypted file.
+// Master keys can be rotated by re-wrapping data keys, file data is not re-encrypted then.
+//
+// Files stored before encryption was enabled are read as is.
+package cryptorepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// FileRepository used to store encrypted files.
+type FileRepository interface {
+	// UploadFile upload file to repository.
+	// Returns error if upload failed.
+	// Returns size of stored file if upload was successful.
+	UploadFile(ctx context.Context, file io.Reader, meta models.Metadata) (int64, error)
+
+	// GetFile get file from repository.
+	// Returns error if get failed.
+	// Returns io.ReadSeekCloser if get was successful.
+	GetFile(ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error)
+
+	// DeleteFile delete file from repository.
+	// Returns error if delete failed.
+	DeleteFile(ctx context.Context, meta models.Metadata) error
+
+	// StoreBlob move file to content addressed blob meta.SHA256.
+	// Returns error if move failed.
+	StoreBlob(ctx context.Context, meta models.Metadata) error
+
+	// ReplaceFile upload file over stored file, stored file stays readable until upload is finished.
+	// Returns error if upload failed.
+	// Returns size of stored file if upload was successful.
+	ReplaceFile(ctx context.Context, file io.Reader, meta models.Metadata) (int64, error)
+}
+
+// Settings used to create CryptoRepository.
+// Settings must be provided to New function.
+// All fields are required.
+type Settings struct {
+	// Repo used to store encrypted files.
+	Repo FileRepository
+
+	// MasterKeys used to wrap data keys.
+	// The first key wraps data keys of new files, others are used to read files
+	// wrapped before key rotation.
+	MasterKeys []MasterKey
+}
+
+// CryptoRepository is a FileRepository wrapper that encrypts files on upload
+// and decrypts them on download.
+// Must be initialized with New function.
+type CryptoRepository struct {
+	repo FileRepository
+	keys *keyring
+}
+
+// New creates new CryptoRepository.
+// Returns error if master keys are invalid.
+func New(settings Settings) (*CryptoRepository, error) {
+	keys, err := newKeyring(settings.MasterKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CryptoRepository{
+		repo: settings.Repo,
+		keys: keys,
+	}, nil
+}
+
+// UploadFile encrypts file with new data key and uploads it to the wrapped repository.
+//
+// If meta.FileSize is not negative, size of encrypted file is passed to the wrapped repository.
+//
+// Returns an error if the upload fails.
+// Returns size of stored encrypted file if upload was successful.
+func (r *CryptoRepository) UploadFile(ctx context.Context, file io.Reader, meta models.Metadata) (int64, error) {
+	encrypted, meta, err := r.encrypt(file, meta)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.repo.UploadFile(ctx, encrypted, meta)
+}
+
+// encrypt returns reader encrypting file with new data key
+// and metadata with size of encrypted file, if size is known.
+func (r *CryptoRepository) encrypt(file io.Reader, meta models.Metadata) (io.Reader, models.Metadata, error) {
+	h, dataKey, err := r.keys.newHeader()
+	if err != nil {
+		return nil, models.Metadata{}, err
+	}
+
+	encrypted, err := newEncryptReader(file, h, dataKey)
+	if err != nil {
+		return nil, models.Metadata{}, err
+	}
+
+	if meta.FileSize >= 0 {
+		meta.FileSize = encryptedSize(meta.FileSize, h.size())
+	}
+
+	return encrypted, meta, nil
+}
+
+// GetFile gets file from the wrapped repository and decrypts it.
+//
+// Returned reader decrypts only chunks that are read, so seeking
+// and range requests don't require reading the whole file.
+// Not encrypted file is returned as is.
+//
+// Returns error if get failed or file data key can't be unwrapped.
+func (r *CryptoRepository) GetFile(ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error) {
+	file, h, size, err := r.openFile(ctx, meta)
+	if errors.Is(err, errNotEncrypted) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := r.keys.unwrap(h)
+	if err != nil {
+		//nolint:errcheck // ignore
+		file.Close()
+		return nil, err
+	}
+
+	decrypted, err := newDecryptReader(file, h, dataKey, size)
+	if err != nil {
+		//nolint:errcheck // ignore
+		file.Close()
+		return nil, err
+	}
+
+	return decrypted, nil
+}
+
+// DeleteFile removes file from the wrapped repository.
+func (r *CryptoRepository) DeleteFile(ctx context.Context, meta models.Metadata) error {
+	return r.repo.DeleteFile(ctx, meta)
+}
+
+// StoreBlob moves file to content addressed blob in the wrapped repository.
+// Encrypted file is moved as is, blob keeps data key of the file in its header.
+func (r *CryptoRepository) StoreBlob(ctx context.Context, meta models.Metadata) error {
+	return r.repo.StoreBlob(ctx, meta)
+}
+
+// RewrapFile wraps data key of the stored file with the current master key.
+//
+// Only the header of the file is replaced, file data is copied as is.
+// Not encrypted file is encrypted.
+// Files of deduplicated documents are rewritten in their blobs.
+//
+// Returns false if data key of the file is already wrapped with the current master key.
+func (r *CryptoRepository) RewrapFile(ctx context.Context, meta models.Metadata) (bool, error) {
+	file, h, size, err := r.openFile(ctx, meta)
+	if err != nil && !errors.Is(err, errNotEncrypted) {
+		return false, err
+	}
+	defer file.Close()
+
+	// Not encrypted file is at the start
+	if errors.Is(err, errNotEncrypted) {
+		meta.FileSize = size
+
+		var encrypted io.Reader
+		encrypted, meta, err = r.encrypt(file, meta)
+		if err != nil {
+			return false, err
+		}
+
+		return true, r.replaceFile(ctx, encrypted, meta)
+	}
+
+	if h.keyID == r.keys.currentID {
+		return false, nil
+	}
+
+	dataKey, err := r.keys.unwrap(h)
+	if err != nil {
+		return false, err
+	}
+
+	rewrapped := h
+	if err = r.keys.wrap(&rewrapped, dataKey); err != nil {
+		return false, err
+	}
+
+	// File is positioned right after the old header
+	meta.FileSize = size - h.size() + rewrapped.size()
+	body := io.MultiReader(bytes.NewReader(rewrapped.encode()), file)
+
+	return true, r.replaceFile(ctx, body, meta)
+}
+
+// replaceFile rewrites stored file of the document version with body.
+// Stored file is read while it is rewritten, so body is never written to the same location directly.
+// File of deduplicated document is uploaded as a version file first and then moved to its blob.
+func (r *CryptoRepository) replaceFile(ctx context.Context, body io.Reader, meta models.Metadata) error {
+	if meta.Blob == "" {
+		_, err := r.repo.ReplaceFile(ctx, body, meta)
+		return err
+	}
+
+	meta.SHA256 = meta.Blob
+	meta.Blob = ""
+
+	if _, err := r.repo.UploadFile(ctx, body, meta); err != nil {
+		return err
+	}
+
+	return r.repo.StoreBlob(ctx, meta)
+}
+
+// openFile opens stored file and reads its header.
+// Returns opened file positioned right after the header, the header and stored file size.
+// Returns errNotEncrypted with opened file positioned at the start if file has no header.
+func (r *CryptoRepository) openFile(
+	ctx context.Context,
+	meta models.Metadata,
+) (io.ReadSeekCloser, header, int64, error) {
+	file, err := r.repo.GetFile(ctx, meta)
+	if err != nil {
+		return nil, header{}, 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		//nolint:errcheck // ignore
+		file.Close()
+		return nil, header{}, 0, err
+	}
+
+	h, err := readHeader(file)
+	if errors.Is(err, errNotEncrypted) {
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			//nolint:errcheck // ignore
+			file.Close()
+			return nil, header{}, 0, err
+		}
+		return file, header{}, size, errNotEncrypted
+	}
+	if err != nil {
+		//nolint:errcheck // ignore
+		file.Close()
+		return nil, header{}, 0, err
+	}
+
+	return file, h, size, nil
+}
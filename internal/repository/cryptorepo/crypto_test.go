@@ -0,0 +1,259 @@
+package cryptorepo
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/repository/fsrepo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFileRepo(t *testing.T) *fsrepo.FSRepository {
+	repo, err := fsrepo.New(context.Background(), fsrepo.Settings{Root: t.TempDir()})
+	require.NoError(t, err)
+	return repo
+}
+
+// readStored returns file as it is stored in the wrapped repository.
+func readStored(t *testing.T, fileRepo *fsrepo.FSRepository, meta models.Metadata) []byte {
+	t.Helper()
+
+	file, err := fileRepo.GetFile(context.Background(), meta)
+	require.NoError(t, err)
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return data
+}
+
+func newMasterKey(t *testing.T, id string) MasterKey {
+	key := make([]byte, masterKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return MasterKey{ID: id, Key: key}
+}
+
+func newMeta(size int64) models.Metadata {
+	id := uuid.New()
+	ownerID := uuid.New()
+	return models.Metadata{
+		ID:       &id,
+		OwnerID:  &ownerID,
+		FileSize: size,
+	}
+}
+
+func TestCryptoRepository_UploadGet(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "small", size: 10},
+		{name: "one chunk", size: chunkSize},
+		{name: "chunk and byte", size: chunkSize + 1},
+		{name: "many chunks", size: 3*chunkSize + 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fileRepo := newFileRepo(t)
+			repo, err := New(Settings{
+				Repo:       fileRepo,
+				MasterKeys: []MasterKey{newMasterKey(t, "k1")},
+			})
+			require.NoError(t, err)
+
+			data := make([]byte, tt.size)
+			_, err = rand.Read(data)
+			require.NoError(t, err)
+
+			// Known and unknown size
+			for _, size := range []int64{int64(tt.size), -1} {
+				meta := newMeta(size)
+				storedSize, err := repo.UploadFile(ctx, bytes.NewReader(data), meta)
+				require.NoError(t, err)
+
+				stored := readStored(t, fileRepo, meta)
+				assert.Equal(t, int64(len(stored)), storedSize)
+				h, err := readHeader(bytes.NewReader(stored))
+				require.NoError(t, err)
+				assert.Equal(t, encryptedSize(int64(tt.size), h.size()), int64(len(stored)))
+				if tt.size > 0 {
+					assert.NotContains(t, string(stored), string(data[:min(tt.size, 32)]))
+				}
+
+				file, err := repo.GetFile(ctx, meta)
+				require.NoError(t, err)
+
+				got, err := io.ReadAll(file)
+				require.NoError(t, err)
+				assert.Equal(t, data, got)
+
+				// Range read
+				if tt.size > 2 {
+					offset := int64(tt.size / 2)
+					_, err = file.Seek(offset, io.SeekStart)
+					require.NoError(t, err)
+
+					got, err = io.ReadAll(io.LimitReader(file, 2))
+					require.NoError(t, err)
+					assert.Equal(t, data[offset:offset+2], got)
+				}
+
+				end, err := file.Seek(0, io.SeekEnd)
+				require.NoError(t, err)
+				assert.Equal(t, int64(tt.size), end)
+
+				require.NoError(t, file.Close())
+			}
+		})
+	}
+}
+
+func TestCryptoRepository_Tampered(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newFileRepo(t)
+	repo, err := New(Settings{
+		Repo:       fileRepo,
+		MasterKeys: []MasterKey{newMasterKey(t, "k1")},
+	})
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("a"), 2*chunkSize+10)
+	meta := newMeta(int64(len(data)))
+	_, err = repo.UploadFile(ctx, bytes.NewReader(data), meta)
+	require.NoError(t, err)
+	stored := readStored(t, fileRepo, meta)
+
+	tests := []struct {
+		name   string
+		stored []byte
+	}{
+		{
+			name:   "modified",
+			stored: append(bytes.Clone(stored[:len(stored)-1]), stored[len(stored)-1]^1),
+		},
+		{
+			name:   "truncated",
+			stored: bytes.Clone(stored[:len(stored)-chunkOverhead-10]),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fileRepo.UploadFile(ctx, bytes.NewReader(tt.stored), meta)
+			require.NoError(t, err)
+
+			file, err := repo.GetFile(ctx, meta)
+			if err == nil {
+				_, err = io.ReadAll(file)
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCryptoRepository_RewrapFile(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newFileRepo(t)
+	oldKey := newMasterKey(t, "old")
+	newKey := newMasterKey(t, "new")
+
+	oldRepo, err := New(Settings{Repo: fileRepo, MasterKeys: []MasterKey{oldKey}})
+	require.NoError(t, err)
+
+	encrypted := newMeta(int64(len("test data")))
+	_, err = oldRepo.UploadFile(ctx, strings.NewReader("test data"), encrypted)
+	require.NoError(t, err)
+
+	// Deduplicated file
+	blob := newMeta(int64(len("blob data")))
+	_, err = oldRepo.UploadFile(ctx, strings.NewReader("blob data"), blob)
+	require.NoError(t, err)
+	blob.SHA256 = strings.Repeat("b", 64)
+	require.NoError(t, fileRepo.StoreBlob(ctx, blob))
+	blob.Blob = blob.SHA256
+
+	// File stored before encryption was enabled
+	plain := newMeta(int64(len("plain data")))
+	_, err = fileRepo.UploadFile(ctx, strings.NewReader("plain data"), plain)
+	require.NoError(t, err)
+
+	repo, err := New(Settings{Repo: fileRepo, MasterKeys: []MasterKey{newKey, oldKey}})
+	require.NoError(t, err)
+
+	for _, meta := range []models.Metadata{encrypted, blob, plain} {
+		ok, err := repo.RewrapFile(ctx, meta)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.RewrapFile(ctx, meta)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	// Old key is not needed anymore
+	newRepo, err := New(Settings{Repo: fileRepo, MasterKeys: []MasterKey{newKey}})
+	require.NoError(t, err)
+
+	for meta, want := range map[*models.Metadata]string{
+		&encrypted: "test data",
+		&blob:      "blob data",
+		&plain:     "plain data",
+	} {
+		file, err := newRepo.GetFile(ctx, *meta)
+		require.NoError(t, err)
+
+		got, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+	}
+
+	_, err = oldRepo.GetFile(ctx, encrypted)
+	require.ErrorIs(t, err, errUnknownMasterKey)
+}
+
+func TestParseMasterKeys(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, masterKeySize))
+
+	tests := []struct {
+		name    string
+		s       string
+		wantIDs []string
+		wantErr bool
+	}{
+		{name: "one key", s: "k1:" + key, wantIDs: []string{"k1"}},
+		{name: "many keys", s: "k2:" + key + ", k1:" + key, wantIDs: []string{"k2", "k1"}},
+		{name: "empty", s: "", wantErr: true},
+		{name: "no id", s: key, wantErr: true},
+		{name: "short key", s: "k1:AQID", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseMasterKeys(tt.s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			ids := make([]string, 0, len(keys))
+			for _, k := range keys {
+				ids = append(ids, k.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package cryptorepo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+const (
+	// masterKeySize is the size of AES-256 master key.
+	masterKeySize = 32
+
+	// dataKeySize is the size of AES-256 data key.
+	dataKeySize = 32
+)
+
+// headerMagic starts every encrypted file.
+const headerMagic = "FSE\x01"
+
+var (
+	// errNotEncrypted is returned by readHeader if file has no encryption header.
+	errNotEncrypted = errors.New("file is not encrypted")
+
+	// errUnknownMasterKey is returned if file data key is wrapped by master key that is not configured.
+	errUnknownMasterKey = errors.New("unknown master key")
+)
+
+// MasterKey is a key used to wrap data keys of files.
+type MasterKey struct {
+	// ID identifies the key in headers of encrypted files.
+	ID string
+
+	// Key is a 32 bytes AES-256 key.
+	Key []byte
+}
+
+// ParseMasterKeys parses comma separated list of master keys in format <id>:<base64 key>.
+// Returns error if list is empty or any key is malformed.
+func ParseMasterKeys(s string) ([]MasterKey, error) {
+	var keys []MasterKey
+
+	for _, item := range strings.Split(s, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || id == "" || len(id) > math.MaxUint8 {
+			return nil, errors.New("master key must have format <id>:<base64 key>")
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %s: %w", id, err)
+		}
+
+		if len(key) != masterKeySize {
+			return nil, fmt.Errorf("master key %s: must be %d bytes long", id, masterKeySize)
+		}
+
+		keys = append(keys, MasterKey{ID: id, Key: key})
+	}
+
+	return keys, nil
+}
+
+// keyring wraps and unwraps data keys with master keys.
+type keyring struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+// newKeyring creates keyring with given master keys.
+// The first key is used to wrap new data keys.
+func newKeyring(masterKeys []MasterKey) (*keyring, error) {
+	if len(masterKeys) == 0 {
+		return nil, errors.New("no master keys")
+	}
+
+	k := &keyring{
+		currentID: masterKeys[0].ID,
+		keys:      make(map[string]cipher.AEAD, len(masterKeys)),
+	}
+
+	for _, masterKey := range masterKeys {
+		aead, err := newAEAD(masterKey.Key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[masterKey.ID] = aead
+	}
+
+	return k, nil
+}
+
+// newHeader generates new data key and returns header with the key wrapped by current master key.
+func (k *keyring) newHeader() (header, []byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return header{}, nil, err
+	}
+
+	h := header{
+		nonce: make([]byte, chunkNonceSize),
+	}
+	if _, err := rand.Read(h.nonce); err != nil {
+		return header{}, nil, err
+	}
+
+	if err := k.wrap(&h, dataKey); err != nil {
+		return header{}, nil, err
+	}
+
+	return h, dataKey, nil
+}
+
+// wrap encrypts data key with current master key and saves it to header.
+func (k *keyring) wrap(h *header, dataKey []byte) error {
+	aead := k.keys[k.currentID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	h.keyID = k.currentID
+	h.wrappedKey = aead.Seal(nonce, nonce, dataKey, []byte(h.keyID))
+
+	return nil
+}
+
+// unwrap decrypts data key from header.
+// Returns errUnknownMasterKey if header master key is not in keyring.
+func (k *keyring) unwrap(h header) ([]byte, error) {
+	aead, ok := k.keys[h.keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownMasterKey, h.keyID)
+	}
+
+	if len(h.wrappedKey) < aead.NonceSize() {
+		return nil, errors.New("malformed wrapped key")
+	}
+
+	nonce, wrapped := h.wrappedKey[:aead.NonceSize()], h.wrappedKey[aead.NonceSize():]
+	return aead.Open(nil, nonce, wrapped, []byte(h.keyID))
+}
+
+// header is a header of encrypted file.
+//
+// Format: magic | key id length (1 byte) | key id | wrapped key length (1 byte) | wrapped key | nonce.
+type header struct {
+	keyID      string
+	wrappedKey []byte
+	nonce      []byte
+}
+
+// size returns size of encoded header.
+func (h header) size() int64 {
+	//nolint:mnd // two length bytes
+	return int64(len(headerMagic) + 2 + len(h.keyID) + len(h.wrappedKey) + len(h.nonce))
+}
+
+// encode returns encoded header.
+func (h header) encode() []byte {
+	buf := make([]byte, 0, h.size())
+	buf = append(buf, headerMagic...)
+	buf = append(buf, byte(len(h.keyID)))
+	buf = append(buf, h.keyID...)
+	buf = append(buf, byte(len(h.wrappedKey)))
+	buf = append(buf, h.wrappedKey...)
+	buf = append(buf, h.nonce...)
+	return buf
+}
+
+// readHeader reads and decodes header from r.
+// Returns errNotEncrypted if r doesn't start with header magic.
+func readHeader(r io.Reader) (header, error) {
+	magic := make([]byte, len(headerMagic))
+	_, err := io.ReadFull(r, magic)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return header{}, errNotEncrypted
+	}
+	if err != nil {
+		return header{}, err
+	}
+
+	if !bytes.Equal(magic, []byte(headerMagic)) {
+		return header{}, errNotEncrypted
+	}
+
+	var h header
+
+	keyID, err := readField(r)
+	if err != nil {
+		return header{}, err
+	}
+	h.keyID = string(keyID)
+
+	h.wrappedKey, err = readField(r)
+	if err != nil {
+		return header{}, err
+	}
+
+	h.nonce = make([]byte, chunkNonceSize)
+	if _, err = io.ReadFull(r, h.nonce); err != nil {
+		return header{}, err
+	}
+
+	return h, nil
+}
+
+// readField reads field prefixed with one byte length from r.
+func readField(r io.Reader) ([]byte, error) {
+	var length uint8
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+
+	field := make([]byte, length)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// newAEAD creates AES-GCM cipher with given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,296 @@
+package cryptorepo
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	// chunkSize is the size of plaintext encrypted as one AES-GCM message.
+	chunkSize = 64 << 10
+
+	// chunkNonceSize is the size of AES-GCM nonce.
+	chunkNonceSize = 12
+
+	// chunkOverhead is the size of AES-GCM tag added to every chunk.
+	chunkOverhead = 16
+
+	// encryptedChunkSize is the size of encrypted full chunk.
+	encryptedChunkSize = chunkSize + chunkOverhead
+)
+
+// errMalformedFile is returned if encrypted file size doesn't match chunked format.
+var errMalformedFile = errors.New("malformed encrypted file")
+
+// Encrypted file consists of header followed by chunks.
+// Every chunk is chunkSize bytes of plaintext sealed with AES-GCM, except the last one
+// that can be shorter. File with empty plaintext has one empty chunk.
+//
+// Nonce of chunk is header nonce XORed with chunk index, so chunks can't be reordered,
+// and the last chunk is sealed with different additional data, so file can't be truncated.
+// Chunks are independent, so any plaintext range can be decrypted without reading the whole file.
+
+// encryptedSize returns size of encrypted file with given plaintext size and header size.
+func encryptedSize(size, headerSize int64) int64 {
+	return headerSize + size + chunksCount(size)*chunkOverhead
+}
+
+// chunksCount returns number of chunks of file with given plaintext size.
+func chunksCount(size int64) int64 {
+	if size == 0 {
+		return 1
+	}
+	return (size + chunkSize - 1) / chunkSize
+}
+
+// plaintextSize returns plaintext size of encrypted file with given body size.
+// Body size is the size of encrypted file without header.
+func plaintextSize(bodySize int64) (int64, error) {
+	chunks := bodySize / encryptedChunkSize
+	if rem := bodySize % encryptedChunkSize; rem != 0 {
+		if rem < chunkOverhead {
+			return 0, errMalformedFile
+		}
+		chunks++
+	}
+
+	if chunks == 0 {
+		return 0, errMalformedFile
+	}
+
+	return bodySize - chunks*chunkOverhead, nil
+}
+
+// chunkNonce returns nonce of chunk with given index.
+func chunkNonce(base []byte, index int64) []byte {
+	nonce := make([]byte, chunkNonceSize)
+	copy(nonce, base)
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(index)) //nolint:gosec // index is not negative
+	for i, b := range counter {
+		nonce[chunkNonceSize-len(counter)+i] ^= b
+	}
+
+	return nonce
+}
+
+// chunkAdditionalData returns additional data of chunk.
+func chunkAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptReader encrypts plaintext read from r.
+// Encoded header is returned first, then encrypted chunks.
+type encryptReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	nonce  []byte
+	index  int64
+	plain  []byte
+	sealed []byte
+	out    []byte
+	header []byte
+	done   bool
+}
+
+// newEncryptReader creates encryptReader with given header and data key.
+func newEncryptReader(r io.Reader, h header, dataKey []byte) (*encryptReader, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptReader{
+		r:      r,
+		aead:   aead,
+		nonce:  h.nonce,
+		plain:  make([]byte, 0, chunkSize+1),
+		sealed: make([]byte, 0, encryptedChunkSize),
+		header: h.encode(),
+	}, nil
+}
+
+// Read implements io.Reader interface.
+func (e *encryptReader) Read(p []byte) (int, error) {
+	if len(e.header) > 0 {
+		n := copy(p, e.header)
+		e.header = e.header[n:]
+		return n, nil
+	}
+
+	for len(e.out) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+
+		if err := e.sealChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+// sealChunk reads the next chunk of plaintext and encrypts it.
+// One byte after the chunk is read ahead to know if the chunk is the last one.
+func (e *encryptReader) sealChunk() error {
+	n, err := io.ReadFull(e.r, e.plain[len(e.plain):chunkSize+1])
+	e.plain = e.plain[:len(e.plain)+n]
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	last := len(e.plain) <= chunkSize
+	chunk := e.plain[:min(len(e.plain), chunkSize)]
+
+	e.out = e.aead.Seal(e.sealed[:0], chunkNonce(e.nonce, e.index), chunk, chunkAdditionalData(last))
+	e.index++
+
+	if last {
+		e.done = true
+		return nil
+	}
+
+	// Keep read ahead byte for the next chunk
+	e.plain[0] = e.plain[chunkSize]
+	e.plain = e.plain[:1]
+
+	return nil
+}
+
+// decryptReader decrypts encrypted file read from r.
+// Seeking is supported, only chunks containing read data are read and decrypted.
+// r is seeked only when chunks are not read sequentially.
+type decryptReader struct {
+	r          io.ReadSeekCloser
+	aead       cipher.AEAD
+	nonce      []byte
+	headerSize int64
+	size       int64
+	chunks     int64
+	rawPos     int64
+
+	pos   int64
+	index int64
+	plain []byte
+	buf   []byte
+}
+
+// newDecryptReader creates decryptReader of file with given header, data key and total size.
+// r must be positioned right after the header.
+func newDecryptReader(
+	r io.ReadSeekCloser,
+	h header,
+	dataKey []byte,
+	fileSize int64,
+) (*decryptReader, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := plaintextSize(fileSize - h.size())
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:          r,
+		aead:       aead,
+		nonce:      h.nonce,
+		headerSize: h.size(),
+		size:       size,
+		chunks:     chunksCount(size),
+		rawPos:     h.size(),
+		index:      -1,
+		buf:        make([]byte, encryptedChunkSize),
+	}, nil
+}
+
+// Read implements io.Reader interface.
+func (d *decryptReader) Read(p []byte) (int, error) {
+	if d.pos >= d.size {
+		return 0, io.EOF
+	}
+
+	index := d.pos / chunkSize
+	if index != d.index {
+		if err := d.openChunk(index); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain[d.pos-index*chunkSize:])
+	d.pos += int64(n)
+	return n, nil
+}
+
+// Seek implements io.Seeker interface.
+func (d *decryptReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = d.pos + offset
+	case io.SeekEnd:
+		pos = d.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	d.pos = pos
+	return pos, nil
+}
+
+// Close implements io.Closer interface.
+func (d *decryptReader) Close() error {
+	return d.r.Close()
+}
+
+// openChunk reads and decrypts chunk with given index.
+func (d *decryptReader) openChunk(index int64) error {
+	last := index == d.chunks-1
+
+	size := int64(encryptedChunkSize)
+	if last {
+		size = d.size - index*chunkSize + chunkOverhead
+	}
+
+	offset := d.headerSize + index*encryptedChunkSize
+	if offset != d.rawPos {
+		if _, err := d.r.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		d.rawPos = offset
+	}
+
+	n, err := io.ReadFull(d.r, d.buf[:size])
+	d.rawPos += int64(n)
+	if err != nil {
+		return err
+	}
+
+	plain, err := d.aead.Open(d.plain[:0], chunkNonce(d.nonce, index), d.buf[:size], chunkAdditionalData(last))
+	if err != nil {
+		d.index = -1
+		return err
+	}
+
+	d.plain = plain
+	d.index = index
+
+	return nil
+}
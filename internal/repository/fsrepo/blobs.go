@@ -0,0 +1,68 @@
+package fsrepo
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// blobsDir is the name of the directory inside Root used for content addressed blobs.
+const blobsDir = "blobs"
+
+// StoreBlob moves file of the document version to content addressed blob meta.SHA256.
+//
+// Existing blob with the same checksum is replaced, it has the same content.
+//
+// Returns an error if the move fails.
+func (r FSRepository) StoreBlob(_ context.Context, meta models.Metadata) error {
+	if meta.ID == nil || meta.OwnerID == nil || len(meta.SHA256) <= shardLength {
+		return apperrors.ErrWrongMetadata
+	}
+
+	blobPath := r.blobPath(meta.SHA256)
+
+	err := os.MkdirAll(filepath.Dir(blobPath), dirPerm)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(r.filePath(meta), blobPath)
+}
+
+// DeleteBlob removes content addressed blob with given checksum.
+//
+// Deleting a blob that does not exist is not an error.
+//
+// Returns an error if the deletion fails.
+func (r FSRepository) DeleteBlob(_ context.Context, sha256 string) error {
+	if len(sha256) <= shardLength {
+		return apperrors.ErrWrongMetadata
+	}
+
+	err := os.Remove(r.blobPath(sha256))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// readPath returns path to the file with data of the document version.
+// Deduplicated files are stored in blobs, other files in paths returned by filePath.
+func (r FSRepository) readPath(meta models.Metadata) string {
+	if meta.Blob != "" && len(meta.Blob) > shardLength {
+		return r.blobPath(meta.Blob)
+	}
+	return r.filePath(meta)
+}
+
+// blobPath returns path to the blob with given checksum.
+// Blobs are sharded by the first characters of the checksum.
+func (r FSRepository) blobPath(sha256 string) string {
+	return filepath.Join(r.root, blobsDir, sha256[:shardLength], sha256)
+}
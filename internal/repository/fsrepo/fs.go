@@ -0,0 +1,314 @@
+package fsrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// shardLength is the number of document ID characters used as shard directory name.
+	shardLength = 2
+
+	// tmpDir is the name of the directory inside Root used for incomplete uploads.
+	tmpDir = ".tmp"
+
+	dirPerm  = 0o750
+	filePerm = 0o640
+)
+
+// Settings used to create FSRepository.
+// Root is required.
+type Settings struct {
+	// Root is the directory where files are stored.
+	// It will be created if not exists.
+	Root string
+}
+
+// FSRepository used to upload and download files from the local filesystem.
+//
+// Files are stored in Root using layout <owner id>/<shard>/<document id>[.<version>],
+// where shard is the first characters of the document ID.
+// The first version of the document has no version suffix.
+// Deduplicated files are stored in blobs/<shard>/<sha256>.
+//
+// Must be initialized with New function.
+type FSRepository struct {
+	root string
+}
+
+// New creates a new FSRepository instance.
+//
+// It takes a context.Context, and Settings to initialize the repository.
+//
+// It creates the root and temporary directories if they don't exist.
+//
+// It returns the pointer to created FSRepository and an error.
+func New(_ context.Context, settings Settings) (*FSRepository, error) {
+	if settings.Root == "" {
+		return nil, errors.New("empty storage root")
+	}
+
+	repo := &FSRepository{
+		root: filepath.Clean(settings.Root),
+	}
+
+	// Create root and temporary directories if not exists
+	err := os.MkdirAll(filepath.Join(repo.root, tmpDir), dirPerm)
+	if err != nil {
+		slog.Error("Error while creating storage directory", slog.Any("err", err))
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// UploadFile uploads a file to the local filesystem.
+//
+// It takes an io.Reader representing the file
+// to be uploaded, and metadata containing file information like owner ID, file ID and version.
+//
+// The file is first written to a temporary file and then atomically renamed
+// to its final location, so readers never see partially written files.
+//
+// Returns an error if the upload fails.
+// Returns size of stored file if upload was successful.
+func (r FSRepository) UploadFile(
+	ctx context.Context,
+	file io.Reader,
+	meta models.Metadata,
+) (int64, error) {
+	if meta.ID == nil || meta.OwnerID == nil {
+		return 0, apperrors.ErrWrongMetadata
+	}
+
+	filePath := r.filePath(meta)
+
+	err := os.MkdirAll(filepath.Dir(filePath), dirPerm)
+	if err != nil {
+		return 0, err
+	}
+
+	// Write data to temporary file
+	tmp, err := os.CreateTemp(filepath.Join(r.root, tmpDir), meta.ID.String()+"-*")
+	if err != nil {
+		return 0, err
+	}
+	tmpName := tmp.Name()
+
+	size, err := writeFile(ctx, tmp, file)
+	if err != nil {
+		//nolint:errcheck // ignore
+		os.Remove(tmpName)
+		return 0, err
+	}
+
+	// Move file to its final location
+	err = os.Rename(tmpName, filePath)
+	if err != nil {
+		//nolint:errcheck // ignore
+		os.Remove(tmpName)
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// ReplaceFile uploads file over stored file of the document version.
+//
+// Files are always written to temporary files and renamed, so stored file
+// stays readable until the new file is fully written.
+//
+// Returns an error if the upload fails.
+// Returns size of stored file if upload was successful.
+func (r FSRepository) ReplaceFile(
+	ctx context.Context,
+	file io.Reader,
+	meta models.Metadata,
+) (int64, error) {
+	return r.UploadFile(ctx, file, meta)
+}
+
+// GetFile get file from the local filesystem.
+//
+// It takes metadata containing file information like owner ID, file ID and version.
+// Deduplicated file is read from its blob.
+//
+// Returns ErrNotFound if file does not exist.
+// Returns io.ReadSeekCloser if get was successful.
+func (r FSRepository) GetFile(
+	_ context.Context,
+	meta models.Metadata,
+) (io.ReadSeekCloser, error) {
+	if meta.ID == nil || meta.OwnerID == nil {
+		return nil, apperrors.ErrWrongMetadata
+	}
+
+	file, err := os.Open(r.readPath(meta))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, apperrors.ErrNotFound
+	}
+
+	return file, err
+}
+
+// DeleteFile removes a file from the local filesystem.
+//
+// It takes metadata containing file information like owner ID, file ID and version.
+//
+// Deleting a file that does not exist is not an error.
+// Blob of deduplicated file is not removed, it is shared with other documents.
+//
+// Returns an error if the deletion fails.
+func (r FSRepository) DeleteFile(_ context.Context, meta models.Metadata) error {
+	if meta.ID == nil || meta.OwnerID == nil {
+		return apperrors.ErrWrongMetadata
+	}
+
+	err := os.Remove(r.filePath(meta))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// WalkFiles calls fn for every file stored in the local filesystem.
+//
+// Only ID, OwnerID and Version fields of metadata passed to fn are filled.
+// Temporary files, blobs and files that don't match storage layout are skipped.
+//
+// Walking stops on the first error returned by fn.
+func (r FSRepository) WalkFiles(
+	ctx context.Context,
+	fn func(meta models.Metadata) error,
+) error {
+	return filepath.WalkDir(r.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == tmpDir || d.Name() == blobsDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(r.root, path)
+		if err != nil {
+			return err
+		}
+
+		meta, ok := parseFilePath(rel)
+		if !ok {
+			return nil
+		}
+
+		return fn(meta)
+	})
+}
+
+// filePath returns path to the file with owner ID, document ID and version from metadata.
+func (r FSRepository) filePath(meta models.Metadata) string {
+	name := meta.ID.String()
+	shard := name[:shardLength]
+	if meta.Version > 1 {
+		name = fmt.Sprintf("%s.%d", name, meta.Version)
+	}
+	return filepath.Join(r.root, meta.OwnerID.String(), shard, name)
+}
+
+// parseFilePath parses file path relative to root created by filePath.
+// Returns false if path has unexpected format.
+func parseFilePath(rel string) (models.Metadata, bool) {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	//nolint:mnd // owner/shard/id
+	if len(parts) != 3 {
+		return models.Metadata{}, false
+	}
+
+	ownerID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return models.Metadata{}, false
+	}
+
+	idStr, versionStr, hasVersion := strings.Cut(parts[2], ".")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return models.Metadata{}, false
+	}
+
+	meta := models.Metadata{
+		ID:      &id,
+		OwnerID: &ownerID,
+		Version: 1,
+	}
+
+	if hasVersion {
+		meta.Version, err = strconv.Atoi(versionStr)
+		if err != nil || meta.Version <= 1 {
+			return models.Metadata{}, false
+		}
+	}
+
+	return meta, true
+}
+
+// writeFile copies data from src to dst, syncs and closes dst.
+// Copying is interrupted if ctx is canceled.
+// Returns number of bytes written.
+func writeFile(ctx context.Context, dst *os.File, src io.Reader) (int64, error) {
+	n, err := io.Copy(dst, &ctxReader{ctx: ctx, r: src})
+	if err != nil {
+		//nolint:errcheck // ignore
+		dst.Close()
+		return 0, err
+	}
+
+	err = dst.Sync()
+	if err != nil {
+		//nolint:errcheck // ignore
+		dst.Close()
+		return 0, err
+	}
+
+	err = dst.Chmod(filePerm)
+	if err != nil {
+		//nolint:errcheck // ignore
+		dst.Close()
+		return 0, err
+	}
+
+	return n, dst.Close()
+}
+
+// ctxReader is a wrapper for io.Reader that stops reading when context is canceled.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read implements io.Reader interface.
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
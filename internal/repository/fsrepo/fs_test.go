@@ -0,0 +1,220 @@
+package fsrepo
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSRepository(t *testing.T) {
+	type test struct {
+		name     string
+		data     string
+		version  int
+		fileName func(id uuid.UUID) string
+	}
+	tests := []test{
+		{
+			name:     "text file",
+			data:     "test data",
+			version:  1,
+			fileName: uuid.UUID.String,
+		},
+		{
+			name:     "empty file",
+			data:     "",
+			version:  1,
+			fileName: uuid.UUID.String,
+		},
+		{
+			name:    "second version",
+			data:    "test data v2",
+			version: 2,
+			fileName: func(id uuid.UUID) string {
+				return id.String() + ".2"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			root := t.TempDir()
+
+			repo, err := New(ctx, Settings{Root: root})
+			require.NoError(t, err)
+
+			id := uuid.New()
+			ownerID := uuid.New()
+			meta := models.Metadata{
+				ID:      &id,
+				OwnerID: &ownerID,
+				Version: tt.version,
+			}
+
+			// Upload
+			size, err := repo.UploadFile(ctx, strings.NewReader(tt.data), meta)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.data)), size)
+
+			assert.FileExists(t, filepath.Join(root, ownerID.String(), id.String()[:2], tt.fileName(id)))
+
+			tmpEntries, err := os.ReadDir(filepath.Join(root, tmpDir))
+			require.NoError(t, err)
+			assert.Empty(t, tmpEntries)
+
+			// Get
+			file, err := repo.GetFile(ctx, meta)
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(file)
+			require.NoError(t, err)
+			require.NoError(t, file.Close())
+
+			assert.Equal(t, tt.data, string(data))
+
+			// Walk
+			var walked []models.Metadata
+			err = repo.WalkFiles(ctx, func(m models.Metadata) error {
+				walked = append(walked, m)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []models.Metadata{meta}, walked)
+
+			// Delete
+			err = repo.DeleteFile(ctx, meta)
+			require.NoError(t, err)
+
+			_, err = repo.GetFile(ctx, meta)
+			require.ErrorIs(t, err, apperrors.ErrNotFound)
+		})
+	}
+}
+
+func TestFSRepository_UploadFileCanceled(t *testing.T) {
+	root := t.TempDir()
+
+	repo, err := New(context.Background(), Settings{Root: root})
+	require.NoError(t, err)
+
+	id := uuid.New()
+	ownerID := uuid.New()
+	meta := models.Metadata{
+		ID:      &id,
+		OwnerID: &ownerID,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = repo.UploadFile(ctx, strings.NewReader("test data"), meta)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.NoFileExists(t, filepath.Join(root, ownerID.String(), id.String()[:2], id.String()))
+
+	tmpEntries, err := os.ReadDir(filepath.Join(root, tmpDir))
+	require.NoError(t, err)
+	assert.Empty(t, tmpEntries)
+}
+
+func TestFSRepository_Upload(t *testing.T) {
+	ctx := context.Background()
+
+	repo, err := New(ctx, Settings{Root: t.TempDir()})
+	require.NoError(t, err)
+
+	upload := models.Upload{
+		ID:     uuid.New(),
+		Length: int64(len("test data")),
+	}
+
+	upload.StorageID, err = repo.CreateUpload(ctx, upload)
+	require.NoError(t, err)
+
+	// Write chunks
+	upload.Offset, err = repo.WriteUpload(ctx, upload, strings.NewReader("test"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), upload.Offset)
+
+	// Data written after offset by interrupted write is discarded
+	_, err = repo.WriteUpload(ctx, upload, strings.NewReader(" lost"))
+	require.NoError(t, err)
+
+	upload.Offset, err = repo.WriteUpload(ctx, upload, strings.NewReader(" data"))
+	require.NoError(t, err)
+	assert.Equal(t, upload.Length, upload.Offset)
+
+	// Complete
+	file, err := repo.CompleteUpload(ctx, upload)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	assert.Equal(t, "test data", string(data))
+
+	// Remove
+	require.NoError(t, repo.RemoveUpload(ctx, upload))
+
+	_, err = repo.CompleteUpload(ctx, upload)
+	require.Error(t, err)
+}
+
+func TestFSRepository_Blob(t *testing.T) {
+	ctx := context.Background()
+
+	repo, err := New(ctx, Settings{Root: t.TempDir()})
+	require.NoError(t, err)
+
+	id := uuid.New()
+	ownerID := uuid.New()
+	meta := models.Metadata{
+		ID:      &id,
+		OwnerID: &ownerID,
+		Version: 1,
+		SHA256:  "916f0027a575074ce72a331777c3478d6513f786a591bd892da1a577bf2335f9",
+	}
+
+	_, err = repo.UploadFile(ctx, strings.NewReader("test data"), meta)
+	require.NoError(t, err)
+	require.NoError(t, repo.StoreBlob(ctx, meta))
+
+	// Version file is moved to blob
+	_, err = repo.GetFile(ctx, meta)
+	require.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	meta.Blob = meta.SHA256
+	file, err := repo.GetFile(ctx, meta)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "test data", string(data))
+
+	// Blobs are not walked and not removed with version files
+	require.NoError(t, repo.WalkFiles(ctx, func(models.Metadata) error {
+		t.Fatal("unexpected file")
+		return nil
+	}))
+	require.NoError(t, repo.DeleteFile(ctx, meta))
+
+	file, err = repo.GetFile(ctx, meta)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	require.NoError(t, repo.DeleteBlob(ctx, meta.SHA256))
+
+	_, err = repo.GetFile(ctx, meta)
+	require.ErrorIs(t, err, apperrors.ErrNotFound)
+}
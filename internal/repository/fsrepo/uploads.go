@@ -0,0 +1,85 @@
+package fsrepo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// uploadsDir is the name of the directory inside tmpDir used for resumable uploads.
+const uploadsDir = "uploads"
+
+// CreateUpload creates empty file used to stage resumable upload.
+//
+// Returns path of the file relative to the uploads directory.
+func (r FSRepository) CreateUpload(_ context.Context, upload models.Upload) (string, error) {
+	err := os.MkdirAll(filepath.Join(r.root, tmpDir, uploadsDir), dirPerm)
+	if err != nil {
+		return "", err
+	}
+
+	name := upload.ID.String()
+
+	file, err := os.OpenFile(r.uploadPath(name), os.O_CREATE|os.O_WRONLY, filePerm)
+	if err != nil {
+		return "", err
+	}
+
+	return name, file.Close()
+}
+
+// WriteUpload appends data to staged upload starting at upload.Offset.
+//
+// Data written after upload.Offset by interrupted writes is discarded first.
+//
+// Returns new offset of staged upload, it is valid even if error is returned.
+func (r FSRepository) WriteUpload(
+	ctx context.Context,
+	upload models.Upload,
+	data io.Reader,
+) (int64, error) {
+	file, err := os.OpenFile(r.uploadPath(upload.StorageID), os.O_WRONLY, filePerm)
+	if err != nil {
+		return upload.Offset, err
+	}
+	defer file.Close()
+
+	if err = file.Truncate(upload.Offset); err != nil {
+		return upload.Offset, err
+	}
+
+	if _, err = file.Seek(upload.Offset, io.SeekStart); err != nil {
+		return upload.Offset, err
+	}
+
+	n, err := io.Copy(file, &ctxReader{ctx: ctx, r: data})
+	if err != nil {
+		return upload.Offset + n, err
+	}
+
+	return upload.Offset + n, file.Sync()
+}
+
+// CompleteUpload returns io.ReadCloser of the whole staged upload.
+func (r FSRepository) CompleteUpload(_ context.Context, upload models.Upload) (io.ReadCloser, error) {
+	return os.Open(r.uploadPath(upload.StorageID))
+}
+
+// RemoveUpload removes file of staged upload.
+func (r FSRepository) RemoveUpload(_ context.Context, upload models.Upload) error {
+	err := os.Remove(r.uploadPath(upload.StorageID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// uploadPath returns path to the file of staged upload.
+func (r FSRepository) uploadPath(name string) string {
+	return filepath.Join(r.root, tmpDir, uploadsDir, name)
+}
@@ -0,0 +1,56 @@
+package miniorepo
+
+import (
+	"context"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/minio/minio-go/v7"
+)
+
+// blobsPrefix is the prefix of content addressed blob objects.
+const blobsPrefix = "blobs/"
+
+// StoreBlob moves file of the document version to content addressed blob meta.SHA256.
+//
+// Existing blob with the same checksum is overwritten, it has the same content.
+// Server side copy is used, so file data is not transferred through the application.
+//
+// Returns an error if the move fails.
+func (r MinioRepository) StoreBlob(ctx context.Context, meta models.Metadata) error {
+	dst := minio.CopyDestOptions{
+		Bucket: r.bucket,
+		Object: blobObjectName(meta.SHA256),
+	}
+	src := minio.CopySrcOptions{
+		Bucket: r.bucket,
+		Object: objectName(meta),
+	}
+
+	// Unlike CopyObject, ComposeObject copies objects larger than 5GiB
+	if _, err := r.client.ComposeObject(ctx, dst, src); err != nil {
+		return err
+	}
+
+	return r.client.RemoveObject(ctx, r.bucket, objectName(meta), minio.RemoveObjectOptions{})
+}
+
+// DeleteBlob removes content addressed blob with given checksum.
+//
+// Returns an error if the deletion fails.
+func (r MinioRepository) DeleteBlob(ctx context.Context, sha256 string) error {
+	return r.client.RemoveObject(ctx, r.bucket, blobObjectName(sha256), minio.RemoveObjectOptions{})
+}
+
+// fileObjectName returns name of the object with file data of the document version.
+// Deduplicated files are stored in blobs, other files in objects named by objectName.
+func fileObjectName(meta models.Metadata) string {
+	if meta.Blob != "" {
+		return blobObjectName(meta.Blob)
+	}
+	return objectName(meta)
+}
+
+// blobObjectName returns name of the blob object with given checksum.
+func blobObjectName(sha256 string) string {
+	return blobsPrefix + sha256
+}
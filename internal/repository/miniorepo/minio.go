@@ -4,12 +4,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
+// replacePrefix is the name prefix of temporary objects replacing stored files.
+// Names of temporary objects are not parsed by parseObjectName, so they are skipped while walking files.
+const replacePrefix = "replace/"
+
 // Settings used to create MinioRepository.
 // Endpoint, AccessKey, SecretKey and Bucket are required.
 // UseSSL defaults to false.
@@ -71,35 +78,87 @@ func New(ctx context.Context, settings Settings) (*MinioRepository, error) {
 // UploadFile uploads a file to the Minio repository.
 //
 // It takes an io.Reader representing the file
-// to be uploaded, and metadata containing file information like owner ID and file ID.
+// to be uploaded, and metadata containing file information like owner ID, file ID and version.
 //
 // The file is uploaded to the bucket specified in the repository, using a
-// filename composed of the owner ID and file ID.
+// filename composed of the owner ID, file ID and version.
+//
+// If meta.FileSize is negative, file of unknown size is streamed in parts of uploadPartSize.
 //
 // Returns an error if the upload fails.
+// Returns size of stored object if upload was successful.
 func (r MinioRepository) UploadFile(
 	ctx context.Context,
 	file io.Reader,
 	meta models.Metadata,
-) error {
-	fileName := fmt.Sprintf("%s:%s", meta.OwnerID.String(), meta.ID.String())
-	_, err := r.client.PutObject(
-		ctx,
-		r.bucket,
-		fileName,
-		file,
-		meta.FileSize,
-		minio.PutObjectOptions{},
-	)
-	return err
+) (int64, error) {
+	return r.putObject(ctx, objectName(meta), file, meta.FileSize)
+}
+
+// ReplaceFile uploads file over stored file of the document version.
+//
+// The file is uploaded to temporary object first and then copied over the stored file
+// with server side copy, so the stored file stays readable until the new file is fully uploaded.
+//
+// Returns an error if the upload fails.
+// Returns size of stored object if upload was successful.
+func (r MinioRepository) ReplaceFile(
+	ctx context.Context,
+	file io.Reader,
+	meta models.Metadata,
+) (int64, error) {
+	tmpName := replacePrefix + objectName(meta)
+
+	size, err := r.putObject(ctx, tmpName, file, meta.FileSize)
+	if err != nil {
+		return 0, err
+	}
+
+	dst := minio.CopyDestOptions{
+		Bucket: r.bucket,
+		Object: objectName(meta),
+	}
+	src := minio.CopySrcOptions{
+		Bucket: r.bucket,
+		Object: tmpName,
+	}
+
+	// Unlike CopyObject, ComposeObject copies objects larger than 5GiB
+	_, err = r.client.ComposeObject(ctx, dst, src)
+	if removeErr := r.client.RemoveObject(ctx, r.bucket, tmpName, minio.RemoveObjectOptions{}); err == nil {
+		err = removeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// putObject uploads object with given name.
+// If size is negative, object of unknown size is streamed in parts of uploadPartSize.
+// Returns size of uploaded object.
+func (r MinioRepository) putObject(ctx context.Context, name string, file io.Reader, size int64) (int64, error) {
+	opts := minio.PutObjectOptions{}
+	if size < 0 {
+		opts.PartSize = uploadPartSize
+	}
+
+	info, err := r.client.PutObject(ctx, r.bucket, name, file, size, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size, nil
 }
 
 // GetFile get file from repository.
 //
-// It takes metadata containing file information like owner ID and file ID.
+// It takes metadata containing file information like owner ID, file ID and version.
 //
 // The file is downloaded from the bucket specified in the repository, using a
-// filename composed of the owner ID and file ID.
+// filename composed of the owner ID, file ID and version.
+// Deduplicated file is downloaded from its blob.
 //
 // Returns error if get failed.
 // Returns io.ReadSeekCloser if get was successful.
@@ -107,17 +166,101 @@ func (r MinioRepository) GetFile(
 	ctx context.Context,
 	meta models.Metadata,
 ) (io.ReadSeekCloser, error) {
-	fileName := fmt.Sprintf("%s:%s", meta.OwnerID.String(), meta.ID.String())
-	return r.client.GetObject(ctx, r.bucket, fileName, minio.GetObjectOptions{})
+	return r.client.GetObject(ctx, r.bucket, fileObjectName(meta), minio.GetObjectOptions{})
 }
 
 // DeleteFile removes a file from the Minio repository.
 //
-// It takes a context and a string representing the file ID.
+// It takes metadata containing file information like owner ID, file ID and version.
 //
-// The file is removed from the bucket specified in the repository.
+// The file is removed from the bucket specified in the repository,
+// together with the file staged with presigned URL, if any.
+// Blob of deduplicated file is not removed, it is shared with other documents.
 //
 // Returns an error if the deletion fails.
-func (r MinioRepository) DeleteFile(ctx context.Context, id string) error {
-	return r.client.RemoveObject(ctx, r.bucket, id, minio.RemoveObjectOptions{})
+func (r MinioRepository) DeleteFile(ctx context.Context, meta models.Metadata) error {
+	if err := r.DeleteStagedFile(ctx, meta); err != nil {
+		return err
+	}
+
+	return r.client.RemoveObject(ctx, r.bucket, objectName(meta), minio.RemoveObjectOptions{})
+}
+
+// WalkFiles calls fn for every file stored in the Minio repository.
+//
+// Only ID, OwnerID and Version fields of metadata passed to fn are filled.
+// Objects with names that are not created by objectName are skipped.
+//
+// Walking stops on the first error returned by fn or by the listing.
+func (r MinioRepository) WalkFiles(
+	ctx context.Context,
+	fn func(meta models.Metadata) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objects := r.client.ListObjects(ctx, r.bucket, minio.ListObjectsOptions{Recursive: true})
+	for object := range objects {
+		if object.Err != nil {
+			return object.Err
+		}
+
+		meta, ok := parseObjectName(object.Key)
+		if !ok {
+			continue
+		}
+
+		if err := fn(meta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// objectName returns name of the object composed of the owner ID, file ID and version.
+// The first version has no version suffix, so objects uploaded before
+// versioning was introduced stay addressable.
+func objectName(meta models.Metadata) string {
+	name := fmt.Sprintf("%s:%s", meta.OwnerID.String(), meta.ID.String())
+	if meta.Version > 1 {
+		name = fmt.Sprintf("%s:%d", name, meta.Version)
+	}
+	return name
+}
+
+// parseObjectName parses object name created by objectName.
+// Returns false if name has unexpected format.
+func parseObjectName(name string) (models.Metadata, bool) {
+	parts := strings.Split(name, ":")
+	//nolint:mnd // owner:id[:version]
+	if len(parts) < 2 || len(parts) > 3 {
+		return models.Metadata{}, false
+	}
+
+	ownerID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return models.Metadata{}, false
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return models.Metadata{}, false
+	}
+
+	meta := models.Metadata{
+		ID:      &id,
+		OwnerID: &ownerID,
+		Version: 1,
+	}
+
+	//nolint:mnd // owner:id:version
+	if len(parts) == 3 {
+		meta.Version, err = strconv.Atoi(parts[2])
+		if err != nil || meta.Version <= 1 {
+			return models.Metadata{}, false
+		}
+	}
+
+	return meta, true
 }
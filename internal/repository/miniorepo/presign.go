@@ -0,0 +1,93 @@
+package miniorepo
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/url"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/minio/minio-go/v7"
+)
+
+// presignedPrefix is the name prefix of objects uploaded with presigned URLs.
+const presignedPrefix = "presigned/"
+
+// PresignUpload creates presigned URL to upload file of the document with PUT request.
+//
+// The URL is valid for ttl. File is uploaded to staging object, not to the document file,
+// so it can't be changed with the same URL after the upload is completed.
+func (r MinioRepository) PresignUpload(
+	ctx context.Context,
+	meta models.Metadata,
+	ttl time.Duration,
+) (string, error) {
+	u, err := r.client.PresignedPutObject(ctx, r.bucket, stagedObjectName(meta), ttl)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// PresignDownload creates presigned URL to download file of the document with GET request.
+//
+// The URL is valid for ttl. Response has document mime type and is served
+// as attachment with document name.
+func (r MinioRepository) PresignDownload(
+	ctx context.Context,
+	meta models.Metadata,
+	ttl time.Duration,
+) (string, error) {
+	params := url.Values{}
+	if meta.Mime != "" {
+		params.Set("response-content-type", meta.Mime)
+	}
+	if meta.Name != "" {
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": meta.Name})
+		params.Set("response-content-disposition", disposition)
+	}
+
+	u, err := r.client.PresignedGetObject(ctx, r.bucket, fileObjectName(meta), ttl, params)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// OpenStagedFile opens file of the document uploaded with presigned URL.
+//
+// Returns ErrNotFound if file is not uploaded.
+func (r MinioRepository) OpenStagedFile(ctx context.Context, meta models.Metadata) (io.ReadCloser, error) {
+	object, err := r.client.GetObject(ctx, r.bucket, stagedObjectName(meta), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Object is requested lazily, missing object is reported by Stat
+	_, err = object.Stat()
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		object.Close()
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		object.Close()
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// DeleteStagedFile removes file of the document uploaded with presigned URL.
+func (r MinioRepository) DeleteStagedFile(ctx context.Context, meta models.Metadata) error {
+	return r.client.RemoveObject(ctx, r.bucket, stagedObjectName(meta), minio.RemoveObjectOptions{})
+}
+
+// stagedObjectName returns name of the object file of the document is uploaded to with presigned URL.
+// Names of staged files are not parsed by parseObjectName, so they are skipped while walking files.
+func stagedObjectName(meta models.Metadata) string {
+	return presignedPrefix + objectName(meta)
+}
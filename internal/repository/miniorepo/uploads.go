@@ -0,0 +1,245 @@
+package miniorepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// uploadPartSize is the size of staged upload parts.
+	// All parts except the last one must be at least 5 MiB.
+	uploadPartSize = 16 << 20
+
+	// uploadsPrefix is the name prefix of staged upload objects.
+	uploadsPrefix = "uploads/"
+
+	// uploadTailSuffix is the name suffix of the object with data
+	// that doesn't fill the whole part yet.
+	uploadTailSuffix = ".part"
+
+	// listPartsMax is the maximum number of parts listed at once.
+	listPartsMax = 1000
+)
+
+// CreateUpload starts multipart upload used to stage resumable upload.
+//
+// Returns id of the multipart upload.
+func (r MinioRepository) CreateUpload(ctx context.Context, upload models.Upload) (string, error) {
+	core := minio.Core{Client: r.client}
+	return core.NewMultipartUpload(ctx, r.bucket, uploadObjectName(upload), minio.PutObjectOptions{})
+}
+
+// WriteUpload appends data to staged upload starting at upload.Offset.
+//
+// Data is uploaded as multipart upload parts of uploadPartSize.
+// Data that doesn't fill the whole part is stored in a separate object and
+// is prepended to the data of the next write. When upload.Length bytes are received,
+// the rest of data is uploaded as the last part.
+//
+// Returns new offset of staged upload, it is valid even if error is returned.
+func (r MinioRepository) WriteUpload(
+	ctx context.Context,
+	upload models.Upload,
+	data io.Reader,
+) (int64, error) {
+	core := minio.Core{Client: r.client}
+	name := uploadObjectName(upload)
+
+	parts, err := r.listUploadParts(ctx, upload)
+	if err != nil {
+		return upload.Offset, err
+	}
+
+	var stored int64
+	for _, part := range parts {
+		stored += part.Size
+	}
+
+	buffer := make([]byte, uploadPartSize)
+	filled := 0
+
+	// Prepend data stored by the previous write
+	if tail := upload.Offset - stored; tail > 0 {
+		if filled, err = r.readUploadTail(ctx, name, buffer[:tail]); err != nil {
+			return upload.Offset, err
+		}
+	}
+
+	partNumber := len(parts) + 1
+	for {
+		var n int
+		n, err = io.ReadFull(data, buffer[filled:])
+		filled += n
+		last := stored+int64(filled) == upload.Length
+
+		if filled < len(buffer) && !last {
+			break
+		}
+
+		_, err = core.PutObjectPart(
+			ctx,
+			r.bucket,
+			name,
+			upload.StorageID,
+			partNumber,
+			bytes.NewReader(buffer[:filled]),
+			int64(filled),
+			minio.PutObjectPartOptions{},
+		)
+		if err != nil {
+			// Data of the previous write is still stored if no parts were uploaded
+			return max(stored, upload.Offset), err
+		}
+
+		stored += int64(filled)
+		filled = 0
+		partNumber++
+
+		if last {
+			return stored, nil
+		}
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+
+	// Nothing new was received
+	if stored+int64(filled) == upload.Offset {
+		return upload.Offset, err
+	}
+
+	// Store data that doesn't fill the whole part
+	_, putErr := r.client.PutObject(
+		ctx,
+		r.bucket,
+		name+uploadTailSuffix,
+		bytes.NewReader(buffer[:filled]),
+		int64(filled),
+		minio.PutObjectOptions{},
+	)
+	if putErr != nil {
+		return max(stored, upload.Offset), putErr
+	}
+
+	return stored + int64(filled), err
+}
+
+// CompleteUpload completes multipart upload of staged resumable upload.
+//
+// Upload completed before is not completed again.
+//
+// Returns io.ReadCloser of the whole uploaded file.
+func (r MinioRepository) CompleteUpload(ctx context.Context, upload models.Upload) (io.ReadCloser, error) {
+	core := minio.Core{Client: r.client}
+	name := uploadObjectName(upload)
+
+	_, err := r.client.StatObject(ctx, r.bucket, name, minio.StatObjectOptions{})
+	switch {
+	case err == nil:
+		return r.client.GetObject(ctx, r.bucket, name, minio.GetObjectOptions{})
+	case minio.ToErrorResponse(err).Code != "NoSuchKey":
+		return nil, err
+	}
+
+	parts, err := r.listUploadParts(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	// Multipart upload can't be completed without parts
+	if len(parts) == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	completeParts := make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		completeParts = append(completeParts, minio.CompletePart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		})
+	}
+
+	_, err = core.CompleteMultipartUpload(
+		ctx,
+		r.bucket,
+		name,
+		upload.StorageID,
+		completeParts,
+		minio.PutObjectOptions{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.client.GetObject(ctx, r.bucket, name, minio.GetObjectOptions{})
+}
+
+// RemoveUpload aborts multipart upload and removes all objects of staged resumable upload.
+func (r MinioRepository) RemoveUpload(ctx context.Context, upload models.Upload) error {
+	core := minio.Core{Client: r.client}
+	name := uploadObjectName(upload)
+
+	err := core.AbortMultipartUpload(ctx, r.bucket, name, upload.StorageID)
+	if err != nil && minio.ToErrorResponse(err).Code != "NoSuchUpload" {
+		return err
+	}
+
+	for _, object := range []string{name, name + uploadTailSuffix} {
+		err = r.client.RemoveObject(ctx, r.bucket, object, minio.RemoveObjectOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listUploadParts returns all uploaded parts of staged resumable upload.
+func (r MinioRepository) listUploadParts(
+	ctx context.Context,
+	upload models.Upload,
+) ([]minio.ObjectPart, error) {
+	core := minio.Core{Client: r.client}
+	name := uploadObjectName(upload)
+
+	var (
+		parts  []minio.ObjectPart
+		marker int
+	)
+	for {
+		result, err := core.ListObjectParts(ctx, r.bucket, name, upload.StorageID, marker, listPartsMax)
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, result.ObjectParts...)
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// readUploadTail reads data stored by the previous write into buffer.
+func (r MinioRepository) readUploadTail(ctx context.Context, name string, buffer []byte) (int, error) {
+	tail, err := r.client.GetObject(ctx, r.bucket, name+uploadTailSuffix, minio.GetObjectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tail.Close()
+
+	return io.ReadFull(tail, buffer)
+}
+
+// uploadObjectName returns name of the object staged resumable upload is assembled to.
+// Names of staged uploads are not parsed by parseObjectName, so they are skipped
+// while walking files.
+func uploadObjectName(upload models.Upload) string {
+	return uploadsPrefix + upload.ID.String()
+}
@@ -0,0 +1,144 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// LinkBlob references existing blob with checksum meta.SHA256 from version meta.Version
+// of the document with id meta.ID and increments blob reference count.
+// Encoding of the version is set to encoding of the blob.
+// Returns false if blob does not exist.
+func (p PostgresRepository) LinkBlob(ctx context.Context, meta models.Metadata) (bool, error) {
+	tag, err := p.pool.Exec(ctx, queryLinkBlob, meta.ID, meta.Version, meta.SHA256)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// AddBlob saves blob with checksum meta.SHA256 and references it from version meta.Version
+// of the document with id meta.ID.
+//
+// store is called to store the blob file in the same transaction, while the new blob is locked,
+// so concurrent uploads of the same content wait for it and never replace the blob file.
+// If store fails, the blob is not saved.
+//
+// Returns false if blob already exists, store is not called then.
+func (p PostgresRepository) AddBlob(ctx context.Context, meta models.Metadata, store func() error) (bool, error) {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return false, err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	var sha256 string
+	err = tx.QueryRow(ctx, queryAddBlob, meta.SHA256, meta.FileSize, meta.Encoding).Scan(&sha256)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Blob already exists, transaction is rolled back as err is set
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err = store(); err != nil {
+		return false, err
+	}
+
+	if _, err = tx.Exec(ctx, querySetVersionBlob, meta.ID, meta.Version, sha256); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CollectBlobs calls fn for every blob that has no references for longer than olderThan
+// and removes the blob if fn succeeds.
+//
+// Every blob is locked while fn is running, so it can't be referenced again
+// until it is removed. Blobs referenced again before they are locked are skipped.
+//
+// Collecting stops on the first error returned by fn.
+func (p PostgresRepository) CollectBlobs(
+	ctx context.Context,
+	olderThan time.Duration,
+	fn func(sha256 string) error,
+) error {
+	rows, err := p.pool.Query(ctx, queryGetUnreferencedBlobs, olderThan.Seconds())
+	if err != nil {
+		return err
+	}
+
+	blobs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+
+	for _, sha256 := range blobs {
+		if err = p.collectBlob(ctx, sha256, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectBlob locks unreferenced blob, calls fn and removes the blob in one transaction.
+func (p PostgresRepository) collectBlob(
+	ctx context.Context,
+	sha256 string,
+	fn func(sha256 string) error,
+) error {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	// Blob may be referenced again or locked by another collector
+	var locked string
+	err = tx.QueryRow(ctx, queryLockUnreferencedBlob, sha256).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = fn(sha256); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, queryRemoveBlob, sha256); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,37 @@
+package postgresrepo
+
+import (
+	"context"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// GetExpiredMetadata returns documents which expiration time has passed, trashed included.
+// Only ID and OwnerID fields are filled.
+// Returns error if get failed.
+func (p PostgresRepository) GetExpiredMetadata(ctx context.Context) ([]models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetExpiredMetadata)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metaList []models.Metadata
+
+	for rows.Next() {
+		var meta models.Metadata
+
+		err = rows.Scan(&meta.ID, &meta.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+
+		metaList = append(metaList, meta)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return metaList, nil
+}
@@ -0,0 +1,280 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is a PostgreSQL error code of unique constraint violation.
+const uniqueViolation = "23505"
+
+// AddFolder adds folder with folder.Name to the parent folder folder.ParentID of folder.OwnerID.
+// Folder without parent is added to the root folder.
+//
+// Folder structure of the owner is locked while folder is added,
+// so path of the parent folder can't be changed concurrently.
+//
+// Returns ErrWrongFolderOptions if parent folder does not exist,
+// or ErrFolderExists if parent folder already has folder with the same name.
+// Returns folder with filled ID, Path and Created fields if add was successful.
+func (p PostgresRepository) AddFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return models.Folder{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	var parentPath string
+
+	parentPath, err = lockFolders(ctx, tx, *folder.OwnerID, folder.ParentID)
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	folder.Path = parentPath + "/" + folder.Name
+
+	var createdTime time.Time
+
+	err = tx.QueryRow(
+		ctx,
+		queryAddFolder,
+		folder.OwnerID,
+		folder.ParentID,
+		folder.Name,
+		folder.Path,
+	).Scan(&folder.ID, &createdTime)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = apperrors.ErrFolderExists
+	}
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	folder.Created = createdTime.Format(time.DateTime)
+
+	if err = tx.Commit(ctx); err != nil {
+		slog.Error("Error while committing transaction", slog.Any("err", err))
+		return models.Folder{}, err
+	}
+
+	return folder, nil
+}
+
+// GetFolder retrieves folder with given id owned by user.
+// Returns ErrNotFound if folder does not exist or is owned by another user.
+func (p PostgresRepository) GetFolder(ctx context.Context, id, ownerID uuid.UUID) (models.Folder, error) {
+	folder, err := scanFolder(p.pool.QueryRow(ctx, queryGetFolder, id, ownerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Folder{}, apperrors.ErrNotFound
+	}
+
+	return folder, err
+}
+
+// GetFolders retrieves all folders owned by user ordered by path.
+// Returns error if get failed.
+func (p PostgresRepository) GetFolders(ctx context.Context, ownerID uuid.UUID) ([]models.Folder, error) {
+	rows, err := p.pool.Query(ctx, queryGetFolders, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Folder, error) {
+		return scanFolder(row)
+	})
+}
+
+// UpdateFolder renames folder owned by user or moves it to another parent folder.
+// Paths of the folder and all its subfolders are changed.
+// Nil fields of patch are not changed, nil UUID parent moves folder to the root folder.
+//
+// Folder structure of the owner is locked while folder is changed.
+//
+// Returns ErrNotFound if folder does not exist or is owned by another user.
+// Returns ErrWrongFolderOptions if new parent folder does not exist or is the folder itself or its subfolder.
+// Returns ErrFolderExists if new parent folder already has folder with the same name.
+// Returns changed folder if update was successful.
+func (p PostgresRepository) UpdateFolder(
+	ctx context.Context,
+	id, ownerID uuid.UUID,
+	patch models.FolderPatch,
+) (models.Folder, error) {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return models.Folder{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	// New parent folder, nil is the root folder
+	parentID := patch.ParentID
+	if parentID != nil && *parentID == uuid.Nil {
+		parentID = nil
+	}
+
+	var (
+		folder     models.Folder
+		parentPath string
+	)
+
+	parentPath, err = lockFolders(ctx, tx, ownerID, parentID)
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	folder, err = scanFolder(tx.QueryRow(ctx, queryGetFolder, id, ownerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = apperrors.ErrNotFound
+	}
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	oldPath := folder.Path
+
+	if patch.ParentID == nil {
+		parentPath = strings.TrimSuffix(oldPath, "/"+folder.Name)
+	} else {
+		// Folder can't be moved into itself
+		if parentPath == oldPath || strings.HasPrefix(parentPath, oldPath+"/") {
+			err = apperrors.ErrWrongFolderOptions
+			return models.Folder{}, err
+		}
+
+		folder.ParentID = parentID
+	}
+
+	if patch.Name != nil {
+		folder.Name = *patch.Name
+	}
+
+	folder.Path = parentPath + "/" + folder.Name
+
+	_, err = tx.Exec(ctx, queryMoveFolders, ownerID, oldPath, folder.Path)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		err = apperrors.ErrFolderExists
+	}
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	_, err = tx.Exec(ctx, queryUpdateFolder, id, folder.Name, folder.ParentID)
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		slog.Error("Error while committing transaction", slog.Any("err", err))
+		return models.Folder{}, err
+	}
+
+	return folder, nil
+}
+
+// DeleteFolder deletes empty folder owned by user.
+// Trashed documents of the folder are moved to the root folder.
+//
+// Returns ErrNotFound if folder does not exist or is owned by another user.
+// Returns ErrFolderNotEmpty if folder has subfolders or not deleted documents.
+func (p PostgresRepository) DeleteFolder(ctx context.Context, id, ownerID uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, queryDeleteFolder, id, ownerID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	if _, err = p.GetFolder(ctx, id, ownerID); err != nil {
+		return err
+	}
+
+	return apperrors.ErrFolderNotEmpty
+}
+
+// GetFolderGrantees retrieves ids of users documents of the folder and its subfolders are shared with.
+func (p PostgresRepository) GetFolderGrantees(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := p.pool.Query(ctx, queryGetFolderGrantees, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+// lockFolders locks folder structure of the owner until the end of transaction
+// and returns path of the parent folder.
+// Root folder path is empty.
+//
+// Returns ErrWrongFolderOptions if parent folder does not exist or is owned by another user.
+func lockFolders(ctx context.Context, tx pgx.Tx, ownerID uuid.UUID, parentID *uuid.UUID) (string, error) {
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, queryLockUser, ownerID).Scan(&id); err != nil {
+		return "", err
+	}
+
+	if parentID == nil {
+		return "", nil
+	}
+
+	parent, err := scanFolder(tx.QueryRow(ctx, queryGetFolder, parentID, ownerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", apperrors.ErrWrongFolderOptions
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return parent.Path, nil
+}
+
+// scanFolder scans folder selected with querySelectFolder columns.
+func scanFolder(row pgx.Row) (models.Folder, error) {
+	var (
+		folder      models.Folder
+		createdTime time.Time
+	)
+
+	err := row.Scan(
+		&folder.ID,
+		&folder.ParentID,
+		&folder.OwnerID,
+		&folder.Name,
+		&folder.Path,
+		&createdTime,
+	)
+	if err != nil {
+		return models.Folder{}, err
+	}
+
+	folder.Created = createdTime.Format(time.DateTime)
+
+	return folder, nil
+}
@@ -0,0 +1,108 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GrantAccess grants access of given level to the document to users with given logins.
+//
+// It begins a transaction and adds entries to the meta_access table.
+// Access is granted on behalf of user grantedBy, or of the document owner if grantedBy is nil.
+// Level of already granted access is replaced, access granted by other user
+// can be replaced only on behalf of the owner.
+//
+// Returns ids of users access was granted to.
+// Returns ErrWrongGrantOptions if user with some login does not exist, is the document owner
+// or has access granted by other user.
+func (p PostgresRepository) GrantAccess(
+	ctx context.Context,
+	id uuid.UUID,
+	logins []string,
+	level models.AccessLevel,
+	grantedBy *uuid.UUID,
+) ([]uuid.UUID, error) {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	userIDs := make([]uuid.UUID, 0, len(logins))
+	for _, login := range logins {
+		var userID uuid.UUID
+		err = tx.QueryRow(ctx, queryGrantAccess, id, login, level, grantedBy).Scan(&userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = apperrors.ErrWrongGrantOptions
+			return nil, err
+		}
+		if err != nil {
+			slog.Error("Error while inserting access grant", slog.Any("err", err))
+			return nil, err
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	// Commit transaction
+	err = tx.Commit(ctx)
+	if err != nil {
+		slog.Error("Error while committing transaction", slog.Any("err", err))
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// RevokeAccess removes access to the document from users with given logins.
+// If grantedBy is not nil, only access granted by this user is removed.
+// Unknown logins and users without such access are ignored.
+//
+// Returns ids of users access was revoked from.
+func (p PostgresRepository) RevokeAccess(
+	ctx context.Context,
+	id uuid.UUID,
+	logins []string,
+	grantedBy *uuid.UUID,
+) ([]uuid.UUID, error) {
+	rows, err := p.pool.Query(ctx, queryRevokeAccess, id, logins, grantedBy)
+	if err != nil {
+		slog.Error("Error while deleting access grants", slog.Any("err", err))
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+// GetAccessLevel retrieves level of access to the document granted to the user.
+//
+// Returns ErrNotFound if access is not granted.
+func (p PostgresRepository) GetAccessLevel(
+	ctx context.Context,
+	id, userID uuid.UUID,
+) (models.AccessLevel, error) {
+	var level models.AccessLevel
+	err := p.pool.QueryRow(ctx, queryGetAccessLevel, id, userID).Scan(&level)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", apperrors.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return level, nil
+}
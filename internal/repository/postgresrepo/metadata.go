@@ -6,21 +6,28 @@ import (
 	"strings"
 	"time"
 
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/docfilter"
 	"github.com/FlutterDizaster/file-server/internal/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // UploadMetadata uploads metadata to the PostgreSQL database.
 //
-// It begins a transaction, inserts metadata into the metadata table,
+// It begins a transaction, checks that the new document fits into owner's quota,
+// inserts metadata into the metadata table, saves it as the first document version,
 // and grants access to specified users by adding entries to the meta_access table.
 //
 // If any step fails, the transaction is rolled back, and an error is returned.
 //
+// Returns ErrQuotaExceeded if the document doesn't fit into quota.
 // Returns the UUID of the newly inserted metadata if successful, or an error if not.
 func (p PostgresRepository) UploadMetadata(
 	ctx context.Context,
 	meta models.Metadata,
+	quota models.Quota,
 ) (uuid.UUID, error) {
 	// Start transaction
 	tx, err := p.pool.Begin(ctx)
@@ -29,12 +36,17 @@ func (p PostgresRepository) UploadMetadata(
 		return uuid.Nil, err
 	}
 
-	defer func(e error) {
-		if e != nil {
+	defer func() {
+		if err != nil {
 			//nolint:errcheck // ignore
 			tx.Rollback(ctx)
 		}
-	}(err)
+	}()
+
+	err = checkQuota(ctx, tx, *meta.OwnerID, quota, meta.FileSize, 1)
+	if err != nil {
+		return uuid.Nil, err
+	}
 
 	// Add metadata to metadata table
 	row := tx.QueryRow(
@@ -47,6 +59,11 @@ func (p PostgresRepository) UploadMetadata(
 		meta.OwnerID,
 		meta.JSON,
 		meta.FileSize,
+		meta.Status,
+		meta.ExpiresAt,
+		meta.FolderID,
+		tagsOf(meta),
+		attributesOf(meta),
 	)
 
 	var id uuid.UUID
@@ -57,6 +74,24 @@ func (p PostgresRepository) UploadMetadata(
 		return uuid.Nil, err
 	}
 
+	// Add first version to metadata_versions table
+	_, err = tx.Exec(
+		ctx,
+		queryUploadVersion,
+		id,
+		1,
+		meta.Name,
+		meta.File,
+		meta.Mime,
+		meta.JSON,
+		meta.FileSize,
+		meta.Status,
+	)
+	if err != nil {
+		slog.Error("Error while inserting metadata version", slog.Any("err", err))
+		return uuid.Nil, err
+	}
+
 	// Add users to meta_access table
 	for _, login := range meta.Grant {
 		_, err = tx.Exec(ctx, queryGrantMetadataAcsess, id, login)
@@ -78,7 +113,8 @@ func (p PostgresRepository) UploadMetadata(
 
 // GetMetadataByUserID retrieves metadata associated with a given user ID from the PostgreSQL database.
 //
-// It queries the metadata table to fetch all metadata records belonging to the specified user ID.
+// It queries the metadata table to fetch all metadata records belonging to the specified user ID
+// or shared with the user through the meta_access table.
 // Each record includes information such as ID, name, MIME type, file status, public visibility,
 // creation time, owner ID, JSON data, file size, and access grants.
 //
@@ -97,50 +133,299 @@ func (p PostgresRepository) GetMetadataByUserID(
 		return nil, err
 	}
 
+	return scanMetadataRows(rows)
+}
+
+// GetMetadataByQuery retrieves metadata visible to the given user that matches compiled filter query.
+//
+// Query predicates are joined with AND and added to the same query as in GetMetadataByUserID.
+// Query order is used if set, otherwise documents are ordered the same way as in GetMetadataByUserID.
+// If query is paginated, limit and offset are applied too.
+//
+// Returns a slice of models.Metadata if successful, or an error if the query fails or if there is an issue
+// scanning the rows.
+func (p PostgresRepository) GetMetadataByQuery(
+	ctx context.Context,
+	userID uuid.UUID,
+	query docfilter.SQLQuery,
+) ([]models.Metadata, error) {
+	var sb strings.Builder
+
+	sb.WriteString(querySelectMetadata)
+	sb.WriteString(visibleTo(query.Args.Add(userID)))
+
+	for _, predicate := range query.Where {
+		sb.WriteString(" AND (")
+		sb.WriteString(predicate)
+		sb.WriteString(")")
+	}
+
+	sb.WriteString(queryGroupMetadata)
+
+	if len(query.OrderBy) > 0 {
+		sb.WriteString("\nORDER BY ")
+		sb.WriteString(strings.Join(query.OrderBy, ", "))
+	} else {
+		sb.WriteString(queryOrderMetadata)
+	}
+
+	if query.Paginated {
+		sb.WriteString("\nLIMIT ")
+		sb.WriteString(query.Args.Add(query.Limit))
+		sb.WriteString(" OFFSET ")
+		sb.WriteString(query.Args.Add(query.Offset))
+	}
+
+	rows, err := p.pool.Query(ctx, sb.String(), query.Args.Values()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanMetadataRows(rows)
+}
+
+// CountMetadataByQuery counts metadata visible to the given user that matches compiled filter query.
+//
+// Query predicates are joined with AND. Order, limit and offset are ignored.
+//
+// Returns number of matching metadata if successful, or an error if the query fails.
+func (p PostgresRepository) CountMetadataByQuery(
+	ctx context.Context,
+	userID uuid.UUID,
+	query docfilter.SQLQuery,
+) (int, error) {
+	var sb strings.Builder
+
+	sb.WriteString(queryCountMetadata)
+	sb.WriteString(visibleTo(query.Args.Add(userID)))
+
+	for _, predicate := range query.Where {
+		sb.WriteString(" AND (")
+		sb.WriteString(predicate)
+		sb.WriteString(")")
+	}
+
+	var count int
+	err := p.pool.QueryRow(ctx, sb.String(), query.Args.Values()...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetMetadataByID retrieves metadata of the document with given id regardless of its owner.
+//
+// Returns ErrNotFound if document does not exist, is deleted or not uploaded yet.
+func (p PostgresRepository) GetMetadataByID(ctx context.Context, id uuid.UUID) (models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetMetadataByID, id)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	metaList, err := scanMetadataRows(rows)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if len(metaList) == 0 {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+
+	return metaList[0], nil
+}
+
+// GetMetadataByPath retrieves metadata of the document owned by user with given name
+// in the folder with given path.
+// If there are several such documents, the newest one is returned.
+//
+// Returns ErrNotFound if document does not exist, is deleted or not uploaded yet.
+func (p PostgresRepository) GetMetadataByPath(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	folderPath, name string,
+) (models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetMetadataByPath, ownerID, folderPath, name)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	metaList, err := scanMetadataRows(rows)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	if len(metaList) == 0 {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+
+	return metaList[0], nil
+}
+
+// GetGrantees retrieves ids of users the document with given id is shared with.
+func (p PostgresRepository) GetGrantees(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := p.pool.Query(ctx, queryGetGrantees, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+// visibleTo returns predicate selecting metadata owned by or shared with user.
+// placeholder is the query placeholder of user id.
+func visibleTo(placeholder string) string {
+	return strings.ReplaceAll(queryVisibleMetadata, "$1", placeholder)
+}
+
+// scanMetadataRows scans rows returned by queries based on querySelectMetadata.
+// Rows are closed after scanning.
+func scanMetadataRows(rows pgx.Rows) ([]models.Metadata, error) {
+	defer rows.Close()
+
 	var metaList []models.Metadata
 
 	for rows.Next() {
-		var (
-			meta        models.Metadata
-			grantStr    string
-			createdTime time.Time
-		)
-
-		err = rows.Scan(
-			&meta.ID,
-			&meta.Name,
-			&meta.Mime,
-			&meta.File,
-			&meta.Public,
-			&createdTime,
-			&meta.OwnerID,
-			&meta.JSON,
-			&meta.FileSize,
-			&grantStr,
-		)
+		meta, err := scanMetadata(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		meta.Created = createdTime.Format(time.DateTime)
-
-		meta.Grant = strings.Split(grantStr, ",")
-
 		metaList = append(metaList, meta)
 	}
 
-	defer rows.Close()
-
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
 	return metaList, nil
 }
 
-// DeleteMetadata delete metadata from repository.
+// scanMetadata scans row selected with querySelectMetadata columns.
+// Values of extra columns selected after them are scanned into extra.
+func scanMetadata(row pgx.Row, extra ...any) (models.Metadata, error) {
+	var (
+		meta        models.Metadata
+		grantStr    string
+		createdTime time.Time
+	)
+
+	dest := []any{
+		&meta.ID,
+		&meta.Name,
+		&meta.Mime,
+		&meta.File,
+		&meta.Public,
+		&createdTime,
+		&meta.OwnerID,
+		&meta.JSON,
+		&meta.FileSize,
+		&meta.Version,
+		&meta.SHA256,
+		&meta.MD5,
+		&meta.Blob,
+		&meta.StoredSize,
+		&meta.Encoding,
+		&meta.ExpiresAt,
+		&meta.Updated,
+		&meta.FolderID,
+		&meta.Path,
+		&meta.Tags,
+		&meta.Attributes,
+		&grantStr,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return models.Metadata{}, err
+	}
+
+	meta.Created = createdTime.Format(time.DateTime)
+
+	meta.Grant = strings.Split(grantStr, ",")
+	meta.Status = models.MetadataStatusReady
+
+	return meta, nil
+}
+
+// UpdateMetadata changes metadata of document with given id and its current version in place.
+// Nil fields of patch are not changed.
+// If updated is not nil, document is changed only if it was not updated since then.
+// Returns ErrNotFound if document does not exist or is deleted,
+// or ErrPreconditionFailed if it was updated.
+func (p PostgresRepository) UpdateMetadata(
+	ctx context.Context,
+	id uuid.UUID,
+	patch models.MetadataPatch,
+	updated *time.Time,
+) error {
+	tag, err := p.pool.Exec(
+		ctx,
+		queryUpdateMetadata,
+		id,
+		patch.Name,
+		patch.Public,
+		patch.Mime,
+		patch.JSON,
+		patch.ExpiresAt,
+		updated,
+		patch.FolderID,
+		patch.Tags,
+		patch.Attributes,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkUpdated(tag, updated)
+}
+
+// DeleteMetadata moves metadata to trash.
+// Document versions keep their files and references to deduplicated blobs until purge.
+// If updated is not nil, document is deleted only if it was not updated since then.
+// Returns ErrPreconditionFailed if document was updated.
 // Returns error if delete failed.
-func (p PostgresRepository) DeleteMetadata(ctx context.Context, id, userID uuid.UUID) error {
-	_, err := p.pool.Exec(ctx, queryDeleteMetadata, id, userID)
-	return err
+func (p PostgresRepository) DeleteMetadata(ctx context.Context, id, userID uuid.UUID, updated *time.Time) error {
+	tag, err := p.pool.Exec(ctx, queryDeleteMetadata, id, userID, updated)
+	if err != nil {
+		return err
+	}
+
+	// Document not owned by user is ignored
+	if updated == nil {
+		return nil
+	}
+
+	return checkUpdated(tag, updated)
+}
+
+// checkUpdated checks result of update conditional on update time of document.
+// Returns ErrPreconditionFailed if nothing was changed because document was updated,
+// or ErrNotFound if update was not conditional.
+func checkUpdated(tag pgconn.CommandTag, updated *time.Time) error {
+	switch {
+	case tag.RowsAffected() > 0:
+		return nil
+	case updated != nil:
+		return apperrors.ErrPreconditionFailed
+	default:
+		return apperrors.ErrNotFound
+	}
+}
+
+// tagsOf returns tags of document, nil tags are saved as empty array.
+func tagsOf(meta models.Metadata) []string {
+	if meta.Tags == nil {
+		return []string{}
+	}
+
+	return meta.Tags
+}
+
+// attributesOf returns attributes of document, nil attributes are saved as empty object.
+func attributesOf(meta models.Metadata) map[string]string {
+	if meta.Attributes == nil {
+		return map[string]string{}
+	}
+
+	return meta.Attributes
 }
@@ -6,10 +6,12 @@ const (
 	queryGetUser = "SELECT id, login, pass_hash FROM users WHERE login = $1"
 
 	// Metadata management queries.
+	// Zero expiration time means that document doesn't expire.
 	queryUploadMetadata = `INSERT INTO metadata 
-(name, is_file, public, mime, owner_id, json_data, file_size) 
-VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
-	queryGetUsersMetadata = `SELECT 
+(name, is_file, public, mime, owner_id, json_data, file_size, status, expires_at, folder_id, tags, attributes) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, '0001-01-01 00:00:00'::timestamp), $10, $11, $12) RETURNING id`
+	querySelectMetadata  = queryMetadataColumns + queryFromMetadata
+	queryMetadataColumns = `SELECT 
     m.id,
     m.name,
     m.mime,
@@ -19,22 +21,285 @@ VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
     m.owner_id,
     m.json_data,
     m.file_size,
-    COALESCE(string_agg(u.username, ','), '') AS grant
+    m.version,
+    m.sha256,
+    m.md5,
+    m.blob,
+    m.stored_size,
+    m.encoding,
+    m.expires_at,
+    m.updated,
+    m.folder_id,
+    COALESCE(f.path, '/') AS path,
+    m.tags,
+    m.attributes,
+    COALESCE(string_agg(u.username, ','), '') AS grant`
+	queryFromMetadata = `
 FROM 
     metadata m
+LEFT JOIN 
+    folders f ON f.id = m.folder_id
 LEFT JOIN 
     meta_access ma ON m.id = ma.meta_id
 LEFT JOIN 
     users u ON ma.user_id = u.id
 WHERE 
-    m.id = $1 AND m.deleted = false
+    m.deleted = false AND m.status = 'ready' AND (m.expires_at IS NULL OR m.expires_at > CURRENT_TIMESTAMP)`
+	queryGroupMetadata = `
 GROUP BY 
-    m.id, m.name, m.mime, m.is_file, m.public, m.created
+    m.id, m.name, m.mime, m.is_file, m.public, m.created, m.version, f.path`
+	queryOrderMetadata = `
 ORDER BY 
     m.name ASC, 
-    m.created DESC;
-`
-	queryDeleteMetadata = `UPDATE metadata SET deleted = true WHERE id = $1 AND owner_id = $2`
+    m.created DESC`
+	queryCountMetadata = `SELECT COUNT(*) FROM metadata m
+WHERE m.deleted = false AND m.status = 'ready' AND (m.expires_at IS NULL OR m.expires_at > CURRENT_TIMESTAMP)`
+	// queryVisibleMetadata selects metadata owned by user $1 or shared with the user.
+	queryVisibleMetadata = ` AND (m.owner_id = $1 OR EXISTS (
+    SELECT 1 FROM meta_access va WHERE va.meta_id = m.id AND va.user_id = $1
+))`
+	queryGetUsersMetadata = querySelectMetadata + queryVisibleMetadata + queryGroupMetadata + queryOrderMetadata
+	queryGetGrantees      = `SELECT user_id FROM meta_access WHERE meta_id = $1`
+	queryGetMetadataByID  = querySelectMetadata + " AND m.id = $1" + queryGroupMetadata
+	// queryGetMetadataByPath selects the newest document of user $1 with name $3 in folder with path $2.
+	queryGetMetadataByPath = querySelectMetadata + " AND m.owner_id = $1 AND COALESCE(f.path, '/') = $2 AND m.name = $3" +
+		queryGroupMetadata + `
+ORDER BY 
+    m.created DESC, 
+    m.id
+LIMIT 1`
+	// queryDeleteMetadata moves document to trash if it was not updated since $3, if $3 is set.
+	queryDeleteMetadata = `UPDATE metadata SET deleted = true, deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND owner_id = $2 AND deleted = false AND ($3::timestamp IS NULL OR updated = $3)`
+
+	// queryUpdateMetadata changes not deleted document and its current version in place
+	// if it was not updated since $7, if $7 is set.
+	// NULL arguments keep current values, zero expiration time $6 clears expiration time,
+	// nil UUID folder $8 moves document to the root folder.
+	queryUpdateMetadata = `WITH m AS (
+    UPDATE metadata SET
+        name = COALESCE($2, name),
+        public = COALESCE($3, public),
+        mime = COALESCE($4, mime),
+        json_data = COALESCE($5, json_data),
+        expires_at = CASE WHEN $6::timestamp IS NULL THEN expires_at
+            ELSE NULLIF($6, '0001-01-01 00:00:00'::timestamp) END,
+        folder_id = CASE WHEN $8::uuid IS NULL THEN folder_id
+            ELSE NULLIF($8, '00000000-0000-0000-0000-000000000000') END,
+        tags = COALESCE($9, tags),
+        attributes = COALESCE($10, attributes),
+        updated = CURRENT_TIMESTAMP
+    WHERE id = $1 AND deleted = false AND ($7::timestamp IS NULL OR updated = $7)
+    RETURNING id, version
+)
+UPDATE metadata_versions v SET
+    name = COALESCE($2, v.name),
+    mime = COALESCE($4, v.mime),
+    json_data = COALESCE($5, v.json_data)
+FROM m
+WHERE v.meta_id = m.id AND v.version = m.version`
+
+	// Trash queries.
+	// Documents deleted before trash was introduced have no deletion time and can't be restored.
+	queryGetTrash = `SELECT m.id, m.name, m.mime, m.is_file, m.public, m.created, m.owner_id, m.json_data,
+    m.file_size, m.version, m.sha256, m.md5, m.blob, m.stored_size, m.updated, m.deleted_at
+FROM metadata m
+WHERE m.owner_id = $1 AND m.deleted = true AND m.deleted_at IS NOT NULL
+ORDER BY m.deleted_at DESC`
+	queryRestoreMetadata = `UPDATE metadata SET deleted = false, deleted_at = NULL
+WHERE id = $1 AND owner_id = $2 AND deleted = true AND deleted_at IS NOT NULL`
+	queryGetExpiredTrash = `SELECT id, owner_id FROM metadata
+WHERE deleted = true AND COALESCE(deleted_at, '-infinity') < CURRENT_TIMESTAMP - make_interval(secs => $1)`
+	queryPurgeMetadata = `DELETE FROM metadata
+WHERE id = $1 AND (deleted = true OR expires_at <= CURRENT_TIMESTAMP)`
+
+	// Full-text search queries.
+	// querySearchMetadata selects documents visible to user $1 matching web search query $2,
+	// most relevant first, with snippets of matching text.
+	// Matched words in snippets are marked with snippetStart and snippetStop.
+	querySearchMetadata = queryMetadataColumns + `,
+    ts_rank(m.search_vector, websearch_to_tsquery('simple', $2)) AS rank,
+    ts_headline(
+        'simple',
+        concat_ws(' ', m.name, array_to_string(m.tags, ' '), m.json_data::text, m.content_text),
+        websearch_to_tsquery('simple', $2),
+        'MaxFragments=3, MaxWords=20, MinWords=5, FragmentDelimiter=" ... ", ' ||
+        'StartSel=' || chr(1) || ', StopSel=' || chr(2)
+    ) AS snippet` + queryFromMetadata + queryVisibleMetadata + `
+    AND m.search_vector @@ websearch_to_tsquery('simple', $2)` + queryGroupMetadata + `
+ORDER BY 
+    rank DESC, 
+    m.created DESC
+LIMIT $3 OFFSET $4`
+
+	// Expiration queries.
+	// querySetExpiration sets expiration time of document, zero time clears it.
+	querySetExpiration = `UPDATE metadata SET expires_at = NULLIF($2, '0001-01-01 00:00:00'::timestamp),
+    updated = CURRENT_TIMESTAMP
+WHERE id = $1`
+	queryGetExpiredMetadata = `SELECT id, owner_id FROM metadata WHERE expires_at <= CURRENT_TIMESTAMP`
+
+	// Folders queries.
+	queryAddFolder = `INSERT INTO folders (owner_id, parent_id, name, path) VALUES ($1, $2, $3, $4)
+ON CONFLICT (owner_id, path) DO NOTHING
+RETURNING id, created`
+	querySelectFolder = `SELECT id, parent_id, owner_id, name, path, created FROM folders`
+	queryGetFolder    = querySelectFolder + " WHERE id = $1 AND owner_id = $2"
+	queryGetFolders   = querySelectFolder + " WHERE owner_id = $1 ORDER BY path"
+	// queryMoveFolders replaces path prefix $2 of folder and its subfolders with $3.
+	queryMoveFolders = `UPDATE folders SET path = $3 || substr(path, length($2) + 1)
+WHERE owner_id = $1 AND (path = $2 OR starts_with(path, $2 || '/'))`
+	queryUpdateFolder = `UPDATE folders SET name = $2, parent_id = $3 WHERE id = $1`
+	// queryDeleteFolder deletes folder without subfolders and not deleted documents.
+	// Trashed documents are moved to the root folder.
+	queryDeleteFolder = `DELETE FROM folders f
+WHERE f.id = $1 AND f.owner_id = $2
+AND NOT EXISTS (SELECT 1 FROM folders c WHERE c.parent_id = f.id)
+AND NOT EXISTS (SELECT 1 FROM metadata m WHERE m.folder_id = f.id AND m.deleted = false)`
+	// queryGetFolderGrantees selects users documents of folder and its subfolders are shared with.
+	queryGetFolderGrantees = `SELECT DISTINCT ma.user_id
+FROM folders r
+JOIN folders f ON f.owner_id = r.owner_id AND (f.path = r.path OR starts_with(f.path, r.path || '/'))
+JOIN metadata m ON m.folder_id = f.id
+JOIN meta_access ma ON ma.meta_id = m.id
+WHERE r.id = $1`
+
+	// Metadata versions queries.
+	queryUploadVersion = `INSERT INTO metadata_versions
+(meta_id, version, name, is_file, mime, json_data, file_size, stored_size, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8)`
+	queryAddVersion = `INSERT INTO metadata_versions
+(meta_id, version, name, is_file, mime, json_data, file_size, stored_size, status)
+SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5, $6, $6, 'pending'
+FROM metadata_versions WHERE meta_id = $1
+RETURNING version`
+	queryLockMetadata    = `SELECT id FROM metadata WHERE id = $1 FOR NO KEY UPDATE`
+	querySetVersionReady = `UPDATE metadata_versions SET status = 'ready'
+WHERE meta_id = $1 AND version = $2`
+	querySetVersionFile = `WITH v AS (
+    UPDATE metadata_versions SET file_size = $3, sha256 = $4, md5 = $5, stored_size = $6, content_text = $7,
+        encoding = $8
+    WHERE meta_id = $1 AND version = $2
+    RETURNING meta_id, version
+)
+UPDATE metadata SET file_size = $3, sha256 = $4, md5 = $5, stored_size = $6, content_text = $7,
+    encoding = $8, updated = CURRENT_TIMESTAMP
+FROM v
+WHERE metadata.id = v.meta_id AND metadata.version = v.version`
+	// querySetChecksums keeps update time, as content of the document is not changed.
+	querySetChecksums = `WITH v AS (
+    UPDATE metadata_versions SET sha256 = $3, md5 = $4
+    WHERE meta_id = $1 AND version = $2
+    RETURNING meta_id, version
+)
+UPDATE metadata SET sha256 = $3, md5 = $4
+FROM v
+WHERE metadata.id = v.meta_id AND metadata.version = v.version`
+	// queryPromoteVersion makes ready version current if document was not updated since $3, if $3 is set.
+	queryPromoteVersion = `UPDATE metadata m SET
+    version = v.version,
+    name = v.name,
+    is_file = v.is_file,
+    mime = v.mime,
+    json_data = v.json_data,
+    file_size = v.file_size,
+    sha256 = v.sha256,
+    md5 = v.md5,
+    blob = v.blob,
+    stored_size = v.stored_size,
+    encoding = v.encoding,
+    content_text = v.content_text,
+    status = 'ready',
+    updated = CURRENT_TIMESTAMP
+FROM metadata_versions v
+WHERE m.id = $1 AND v.meta_id = $1 AND v.version = $2 AND v.status = 'ready'
+AND ($3::timestamp IS NULL OR m.updated = $3)`
+	queryRemoveVersion = `WITH v AS (
+    DELETE FROM metadata_versions WHERE meta_id = $1 AND version = $2
+    RETURNING blob
+)
+UPDATE blobs b SET refcount = b.refcount - 1, updated = CURRENT_TIMESTAMP
+FROM v
+WHERE b.sha256 = v.blob`
+	queryRemovePendingMetadata = `DELETE FROM metadata WHERE id = $1 AND status = 'pending'`
+	queryGetVersions           = `SELECT version, name, is_file, mime, json_data, file_size, sha256, md5, blob,
+    stored_size, encoding, created
+FROM metadata_versions
+WHERE meta_id = $1 AND status = 'ready'
+ORDER BY version DESC`
+	queryGetVersion = `SELECT version, name, is_file, mime, json_data, file_size, sha256, md5, blob,
+    stored_size, encoding, created
+FROM metadata_versions
+WHERE meta_id = $1 AND version = $2 AND status = 'ready'`
+	queryGetPendingVersion = `SELECT v.meta_id, m.owner_id, v.version, v.name, v.is_file, v.mime, v.file_size
+FROM metadata_versions v
+JOIN metadata m ON m.id = v.meta_id
+WHERE v.meta_id = $1 AND v.status = 'pending' AND m.deleted = false
+ORDER BY v.version DESC
+LIMIT 1`
+	queryGetFileVersions = `SELECT v.meta_id, m.owner_id, v.version, v.file_size, v.stored_size, v.sha256, v.md5, v.blob,
+    v.encoding
+FROM metadata_versions v
+JOIN metadata m ON m.id = v.meta_id
+WHERE v.status = 'ready' AND v.is_file = true
+ORDER BY v.meta_id, v.version`
+	queryGetStaleVersions = `SELECT v.meta_id, m.owner_id, v.version
+FROM metadata_versions v
+JOIN metadata m ON m.id = v.meta_id
+WHERE v.status = 'pending' AND v.created < CURRENT_TIMESTAMP - make_interval(secs => $1)`
+	queryVersionExists = `SELECT EXISTS (
+    SELECT 1 FROM metadata_versions v
+    JOIN metadata m ON m.id = v.meta_id
+    WHERE v.meta_id = $1 AND v.version = $2 AND v.blob = ''
+)`
+
+	// Blobs queries.
+	queryLinkBlob = `WITH b AS (
+    UPDATE blobs SET refcount = refcount + 1, updated = CURRENT_TIMESTAMP
+    WHERE sha256 = $3
+    RETURNING sha256, encoding
+)
+UPDATE metadata_versions v SET blob = b.sha256, encoding = b.encoding
+FROM b
+WHERE v.meta_id = $1 AND v.version = $2`
+	// queryAddBlob waits for concurrent insert of the same blob and skips existing blob.
+	queryAddBlob = `INSERT INTO blobs (sha256, size, refcount, encoding) VALUES ($1, $2, 1, $3)
+ON CONFLICT (sha256) DO NOTHING
+RETURNING sha256`
+	querySetVersionBlob = `UPDATE metadata_versions SET blob = $3 WHERE meta_id = $1 AND version = $2`
+	// queryUnlinkBlobs removes blob references of all versions of the document.
+	// Self join returns blob values from before the update.
+	queryUnlinkBlobs = `WITH v AS (
+    UPDATE metadata_versions mv SET blob = ''
+    FROM metadata_versions old
+    WHERE mv.meta_id = $1 AND old.meta_id = mv.meta_id AND old.version = mv.version AND old.blob <> ''
+    RETURNING old.blob
+)
+UPDATE blobs b SET refcount = b.refcount - r.refs, updated = CURRENT_TIMESTAMP
+FROM (SELECT blob, COUNT(*) AS refs FROM v GROUP BY blob) r
+WHERE b.sha256 = r.blob`
+	queryGetUnreferencedBlobs = `SELECT sha256 FROM blobs
+WHERE refcount <= 0 AND updated < CURRENT_TIMESTAMP - make_interval(secs => $1)`
+	queryLockUnreferencedBlob = `SELECT sha256 FROM blobs
+WHERE sha256 = $1 AND refcount <= 0
+FOR UPDATE SKIP LOCKED`
+	queryRemoveBlob = `DELETE FROM blobs WHERE sha256 = $1`
+
+	// Quota queries.
+	queryGetQuota = `SELECT max_bytes, max_documents FROM quotas WHERE user_id = $1`
+	querySetQuota = `INSERT INTO quotas (user_id, max_bytes, max_documents) VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET max_bytes = EXCLUDED.max_bytes, max_documents = EXCLUDED.max_documents`
+	queryLockUser = `SELECT id FROM users WHERE id = $1 FOR NO KEY UPDATE`
+	// queryGetUsage counts documents of user, trashed included, and file size of all their versions.
+	// Documents deleted before trash was introduced have no files and are not counted.
+	// Pending versions reserve their declared size, unknown size is not counted until it is known.
+	// Resumable uploads reserve their whole length.
+	queryGetUsage = `SELECT COALESCE(SUM(GREATEST(v.file_size, 0)), 0) +
+    (SELECT COALESCE(SUM(u.upload_length), 0) FROM uploads u WHERE u.owner_id = $1),
+    COUNT(DISTINCT m.id)
+FROM metadata m
+LEFT JOIN metadata_versions v ON v.meta_id = m.id
+WHERE m.owner_id = $1 AND NOT (m.deleted = true AND m.deleted_at IS NULL)`
 
 	// Metadata Access queries.
 	queryGrantMetadataAcsess = `INSERT INTO meta_access (meta_id, user_id)
@@ -42,4 +307,50 @@ VALUES (
     $1,
     (SELECT id FROM users WHERE username = $2)
 )`
+
+	// Access management queries.
+	// queryGrantAccess grants access on behalf of user $4, or of the owner if $4 is NULL.
+	// Access granted by other user can be changed only by the owner.
+	queryGrantAccess = `INSERT INTO meta_access (meta_id, user_id, level, granted_by)
+SELECT $1, u.id, $3, $4 FROM users u
+WHERE u.username = $2 AND u.id <> (SELECT owner_id FROM metadata WHERE id = $1)
+ON CONFLICT (meta_id, user_id) DO UPDATE SET level = EXCLUDED.level, granted_by = EXCLUDED.granted_by
+WHERE EXCLUDED.granted_by IS NULL OR meta_access.granted_by = EXCLUDED.granted_by
+RETURNING user_id`
+	// queryRevokeAccess revokes access granted by user $3, or any access if $3 is NULL.
+	queryRevokeAccess = `DELETE FROM meta_access ma USING users u
+WHERE ma.meta_id = $1 AND ma.user_id = u.id AND u.username = ANY($2)
+    AND ($3::uuid IS NULL OR ma.granted_by = $3)
+RETURNING ma.user_id`
+	queryGetAccessLevel = `SELECT level FROM meta_access WHERE meta_id = $1 AND user_id = $2`
+
+	// Share links queries.
+	queryAddShareLink = `INSERT INTO share_links
+(meta_id, created_by, password_hash, max_downloads, expires_at)
+VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	queryGetShareLink = `SELECT id, meta_id, created_by, password_hash, max_downloads, downloads, expires_at
+FROM share_links WHERE id = $1`
+	queryCountShareLinkDownload = `UPDATE share_links SET downloads = downloads + 1
+WHERE id = $1 AND (max_downloads = 0 OR downloads < max_downloads)`
+	// queryRemoveShareLinks removes share links of document created by user $2, or all links if $2 is NULL.
+	queryRemoveShareLinks = `DELETE FROM share_links WHERE meta_id = $1 AND ($2::uuid IS NULL OR created_by = $2)`
+
+	// Resumable uploads queries.
+	queryAddUpload = `INSERT INTO uploads
+(owner_id, upload_length, name, public, mime, json_data, grants, expires_at, folder_id, tags, attributes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created`
+	querySelectUpload = `SELECT id, owner_id, upload_length, upload_offset,
+    name, public, mime, json_data, grants, expires_at, folder_id, tags, attributes, storage_id, created
+FROM uploads`
+	queryGetUpload        = querySelectUpload + " WHERE id = $1"
+	querySetUploadStorage = `UPDATE uploads SET storage_id = $2 WHERE id = $1`
+	queryLockUpload       = `UPDATE uploads SET locked_until = CURRENT_TIMESTAMP + make_interval(secs => $2)
+WHERE id = $1 AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+RETURNING id, owner_id, upload_length, upload_offset,
+    name, public, mime, json_data, grants, expires_at, folder_id, tags, attributes, storage_id, created`
+	queryUnlockUpload    = `UPDATE uploads SET upload_offset = $2, locked_until = NULL WHERE id = $1`
+	queryRemoveUpload    = `DELETE FROM uploads WHERE id = $1`
+	queryGetStaleUploads = querySelectUpload + `
+WHERE created < CURRENT_TIMESTAMP - make_interval(secs => $1)
+AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)`
 )
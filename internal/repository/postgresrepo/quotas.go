@@ -0,0 +1,87 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetQuota returns storage limits set for user with given id.
+// Nil limits are not set, default limits are used for them.
+// Returns error if get failed.
+func (p PostgresRepository) GetQuota(ctx context.Context, userID uuid.UUID) (models.QuotaRequest, error) {
+	var quota models.QuotaRequest
+
+	err := p.pool.QueryRow(ctx, queryGetQuota, userID).Scan(&quota.MaxBytes, &quota.MaxDocuments)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.QuotaRequest{}, nil
+	}
+	if err != nil {
+		return models.QuotaRequest{}, err
+	}
+
+	return quota, nil
+}
+
+// SetQuota sets storage limits of user with given id.
+// Nil limits are reset to default limits.
+// Returns error if set failed.
+func (p PostgresRepository) SetQuota(ctx context.Context, userID uuid.UUID, quota models.QuotaRequest) error {
+	_, err := p.pool.Exec(ctx, querySetQuota, userID, quota.MaxBytes, quota.MaxDocuments)
+	return err
+}
+
+// GetUsage returns storage usage of user with given id.
+// Only Bytes and Documents fields are filled.
+// Returns error if get failed.
+func (p PostgresRepository) GetUsage(ctx context.Context, userID uuid.UUID) (models.Usage, error) {
+	var usage models.Usage
+
+	err := p.pool.QueryRow(ctx, queryGetUsage, userID).Scan(&usage.Bytes, &usage.Documents)
+	if err != nil {
+		return models.Usage{}, err
+	}
+
+	return usage, nil
+}
+
+// checkQuota checks that user's documents with new documents and files of given size fit into quota.
+//
+// User is locked until the end of transaction, so checks of concurrent uploads of the user
+// are serialized and see metadata inserted by each other.
+// Must be called in transaction before inserting metadata.
+//
+// Returns ErrQuotaExceeded if quota is exceeded.
+func checkQuota(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID uuid.UUID,
+	quota models.Quota,
+	size int64,
+	documents int,
+) error {
+	if quota.MaxBytes <= 0 && quota.MaxDocuments <= 0 {
+		return nil
+	}
+
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, queryLockUser, userID).Scan(&id); err != nil {
+		return err
+	}
+
+	var usage models.Usage
+	if err := tx.QueryRow(ctx, queryGetUsage, userID).Scan(&usage.Bytes, &usage.Documents); err != nil {
+		return err
+	}
+
+	if quota.MaxBytes > 0 && usage.Bytes+max(size, 0) > quota.MaxBytes ||
+		quota.MaxDocuments > 0 && usage.Documents+documents > quota.MaxDocuments {
+		return apperrors.ErrQuotaExceeded
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package postgresrepo
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// snippetStart marks beginning of matched word in snippet, set by querySearchMetadata.
+	snippetStart = "\x01"
+	// snippetStop marks end of matched word in snippet, set by querySearchMetadata.
+	snippetStop = "\x02"
+)
+
+// snippetHighlighter replaces match markers with html tags.
+var snippetHighlighter = strings.NewReplacer(snippetStart, "<b>", snippetStop, "</b>")
+
+// SearchMetadata searches documents visible to user by names, tags, JSON data and extracted text of files.
+// Query uses web search syntax: quoted phrases, "or" and "-" to exclude words.
+//
+// Documents are ordered by relevance, limit and offset select the page.
+//
+// Snippet text is html escaped, matched words are wrapped in <b> and </b>.
+//
+// Returns found documents with rank and snippet of matching text if successful,
+// or an error if the query fails.
+func (p PostgresRepository) SearchMetadata(
+	ctx context.Context,
+	userID uuid.UUID,
+	query string,
+	limit, offset int,
+) ([]models.SearchResult, error) {
+	rows, err := p.pool.Query(ctx, querySearchMetadata, userID, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+
+	for rows.Next() {
+		var result models.SearchResult
+
+		result.Metadata, err = scanMetadata(rows, &result.Rank, &result.Snippet)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Snippet = highlightSnippet(result.Snippet)
+
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// highlightSnippet escapes snippet text and wraps matched words in <b> and </b>.
+// Text is escaped first, so html from documents is never returned as markup.
+func highlightSnippet(snippet string) string {
+	return snippetHighlighter.Replace(html.EscapeString(snippet))
+}
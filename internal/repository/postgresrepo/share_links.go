@@ -0,0 +1,89 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddShareLink adds share link to the share_links table.
+//
+// Returns id of the added link, or an error if insert failed.
+func (p PostgresRepository) AddShareLink(ctx context.Context, link models.ShareLink) (uuid.UUID, error) {
+	row := p.pool.QueryRow(
+		ctx,
+		queryAddShareLink,
+		link.MetaID,
+		link.CreatedBy,
+		link.PasswordHash,
+		link.MaxDownloads,
+		link.ExpiresAt,
+	)
+
+	var id uuid.UUID
+	err := row.Scan(&id)
+	if err != nil {
+		slog.Error("Error while inserting share link", slog.Any("err", err))
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// GetShareLink retrieves share link with given id.
+//
+// Returns ErrNotFound if link does not exist.
+func (p PostgresRepository) GetShareLink(ctx context.Context, id uuid.UUID) (models.ShareLink, error) {
+	var link models.ShareLink
+	err := p.pool.QueryRow(ctx, queryGetShareLink, id).Scan(
+		&link.ID,
+		&link.MetaID,
+		&link.CreatedBy,
+		&link.PasswordHash,
+		&link.MaxDownloads,
+		&link.Downloads,
+		&link.ExpiresAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ShareLink{}, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	return link, nil
+}
+
+// CountShareLinkDownload increments downloads counter of share link.
+//
+// Counter is checked and incremented atomically.
+// Returns ErrShareLinkExhausted if downloads limit is reached.
+func (p PostgresRepository) CountShareLinkDownload(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, queryCountShareLinkDownload, id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperrors.ErrShareLinkExhausted
+	}
+
+	return nil
+}
+
+// RemoveShareLinks removes share links of the document with given id.
+// If createdBy is not nil, only links created by this user are removed.
+func (p PostgresRepository) RemoveShareLinks(ctx context.Context, id uuid.UUID, createdBy *uuid.UUID) error {
+	_, err := p.pool.Exec(ctx, queryRemoveShareLinks, id, createdBy)
+	if err != nil {
+		slog.Error("Error while deleting share links", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
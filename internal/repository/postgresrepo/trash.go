@@ -0,0 +1,152 @@
+package postgresrepo
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+)
+
+// GetTrash returns trashed documents owned by user with given id, most recently deleted first.
+// Grants are not filled.
+// Returns error if get failed.
+func (p PostgresRepository) GetTrash(ctx context.Context, userID uuid.UUID) ([]models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetTrash, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metaList []models.Metadata
+
+	for rows.Next() {
+		var (
+			meta        models.Metadata
+			createdTime time.Time
+			deletedTime time.Time
+		)
+
+		err = rows.Scan(
+			&meta.ID,
+			&meta.Name,
+			&meta.Mime,
+			&meta.File,
+			&meta.Public,
+			&createdTime,
+			&meta.OwnerID,
+			&meta.JSON,
+			&meta.FileSize,
+			&meta.Version,
+			&meta.SHA256,
+			&meta.MD5,
+			&meta.Blob,
+			&meta.StoredSize,
+			&meta.Updated,
+			&deletedTime,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		meta.Created = createdTime.Format(time.DateTime)
+		meta.Deleted = deletedTime.Format(time.DateTime)
+		meta.Status = models.MetadataStatusReady
+
+		metaList = append(metaList, meta)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return metaList, nil
+}
+
+// RestoreMetadata moves trashed document owned by user back from trash.
+// Returns ErrNotFound if document is not in trash of user.
+func (p PostgresRepository) RestoreMetadata(ctx context.Context, id, userID uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, queryRestoreMetadata, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetExpiredTrash returns documents trashed earlier than olderThan ago.
+// Only ID and OwnerID fields are filled.
+// Returns error if get failed.
+func (p PostgresRepository) GetExpiredTrash(
+	ctx context.Context,
+	olderThan time.Duration,
+) ([]models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetExpiredTrash, olderThan.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var metaList []models.Metadata
+
+	for rows.Next() {
+		var meta models.Metadata
+
+		err = rows.Scan(&meta.ID, &meta.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+
+		metaList = append(metaList, meta)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return metaList, nil
+}
+
+// PurgeMetadata permanently removes trashed or expired document with all its versions, grants and share links.
+// References of document versions to deduplicated blobs are released.
+// Returns ErrNotFound if document is not trashed or expired.
+// Returns error if purge failed.
+func (p PostgresRepository) PurgeMetadata(ctx context.Context, id uuid.UUID) error {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, queryUnlinkBlobs, id)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, queryPurgeMetadata, id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		err = apperrors.ErrNotFound
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
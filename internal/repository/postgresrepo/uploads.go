@@ -0,0 +1,192 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddUpload adds resumable upload to the uploads table.
+//
+// Only OwnerID, Length and Metadata fields of upload are saved.
+// Upload reserves its length in quota of the owner until it is removed.
+//
+// Returns ErrQuotaExceeded if the upload doesn't fit into quota.
+// Returns upload with filled ID and Created fields, or an error if insert failed.
+func (p PostgresRepository) AddUpload(
+	ctx context.Context,
+	upload models.Upload,
+	quota models.Quota,
+) (models.Upload, error) {
+	meta := upload.Metadata
+	if meta.Grant == nil {
+		meta.Grant = []string{}
+	}
+
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return models.Upload{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	err = checkQuota(ctx, tx, upload.OwnerID, quota, upload.Length, 1)
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	row := tx.QueryRow(
+		ctx,
+		queryAddUpload,
+		upload.OwnerID,
+		upload.Length,
+		meta.Name,
+		meta.Public,
+		meta.Mime,
+		meta.JSON,
+		meta.Grant,
+		meta.ExpiresAt,
+		meta.FolderID,
+		tagsOf(meta),
+		attributesOf(meta),
+	)
+
+	err = row.Scan(&upload.ID, &upload.Created)
+	if err != nil {
+		slog.Error("Error while inserting upload", slog.Any("err", err))
+		return models.Upload{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	return upload, nil
+}
+
+// SetUploadStorage saves id of the staged upload in file repository.
+func (p PostgresRepository) SetUploadStorage(ctx context.Context, id uuid.UUID, storageID string) error {
+	_, err := p.pool.Exec(ctx, querySetUploadStorage, id, storageID)
+	return err
+}
+
+// GetUpload retrieves resumable upload with given id.
+//
+// Returns ErrNotFound if upload does not exist.
+func (p PostgresRepository) GetUpload(ctx context.Context, id uuid.UUID) (models.Upload, error) {
+	upload, err := scanUpload(p.pool.QueryRow(ctx, queryGetUpload, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Upload{}, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	return upload, nil
+}
+
+// LockUpload locks resumable upload for writing for lease duration.
+//
+// Lock expires after lease, so upload of crashed writer can be resumed.
+//
+// Returns locked upload with actual offset.
+// Returns ErrNotFound if upload does not exist and ErrUploadLocked if it is already locked.
+func (p PostgresRepository) LockUpload(
+	ctx context.Context,
+	id uuid.UUID,
+	lease time.Duration,
+) (models.Upload, error) {
+	upload, err := scanUpload(p.pool.QueryRow(ctx, queryLockUpload, id, lease.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Check if upload exists
+		if _, err = p.GetUpload(ctx, id); err != nil {
+			return models.Upload{}, err
+		}
+		return models.Upload{}, apperrors.ErrUploadLocked
+	}
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	return upload, nil
+}
+
+// UnlockUpload saves offset of resumable upload and releases the lock.
+func (p PostgresRepository) UnlockUpload(ctx context.Context, id uuid.UUID, offset int64) error {
+	_, err := p.pool.Exec(ctx, queryUnlockUpload, id, offset)
+	return err
+}
+
+// RemoveUpload removes resumable upload from the uploads table.
+func (p PostgresRepository) RemoveUpload(ctx context.Context, id uuid.UUID) error {
+	_, err := p.pool.Exec(ctx, queryRemoveUpload, id)
+	return err
+}
+
+// GetStaleUploads returns not locked resumable uploads created earlier than olderThan ago.
+// Returns error if get failed.
+func (p PostgresRepository) GetStaleUploads(
+	ctx context.Context,
+	olderThan time.Duration,
+) ([]models.Upload, error) {
+	rows, err := p.pool.Query(ctx, queryGetStaleUploads, olderThan.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Upload, error) {
+		return scanUpload(row)
+	})
+}
+
+// scanUpload scans upload selected with querySelectUpload columns.
+func scanUpload(row pgx.Row) (models.Upload, error) {
+	var (
+		upload      models.Upload
+		createdTime time.Time
+	)
+
+	err := row.Scan(
+		&upload.ID,
+		&upload.OwnerID,
+		&upload.Length,
+		&upload.Offset,
+		&upload.Metadata.Name,
+		&upload.Metadata.Public,
+		&upload.Metadata.Mime,
+		&upload.Metadata.JSON,
+		&upload.Metadata.Grant,
+		&upload.Metadata.ExpiresAt,
+		&upload.Metadata.FolderID,
+		&upload.Metadata.Tags,
+		&upload.Metadata.Attributes,
+		&upload.StorageID,
+		&createdTime,
+	)
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	ownerID := upload.OwnerID
+
+	upload.Created = createdTime
+	upload.Metadata.File = true
+	upload.Metadata.OwnerID = &ownerID
+	upload.Metadata.FileSize = upload.Length
+
+	return upload, nil
+}
@@ -0,0 +1,462 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddVersion adds a new pending version of the document with id meta.ID.
+//
+// Version number is the next number after the latest existing version,
+// the document is locked until the end of transaction to keep numbers unique.
+// Version becomes current only after PromoteVersion is called.
+// Version file must fit into quota of the document owner meta.OwnerID.
+//
+// Returns ErrQuotaExceeded if the version doesn't fit into quota.
+// Returns the number of the added version, or an error if insert failed.
+func (p PostgresRepository) AddVersion(
+	ctx context.Context,
+	meta models.Metadata,
+	quota models.Quota,
+) (int, error) {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	err = checkQuota(ctx, tx, *meta.OwnerID, quota, meta.FileSize, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	// Concurrent uploads of the document get different version numbers
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, queryLockMetadata, meta.ID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = apperrors.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	row := tx.QueryRow(
+		ctx,
+		queryAddVersion,
+		meta.ID,
+		meta.Name,
+		meta.File,
+		meta.Mime,
+		meta.JSON,
+		meta.FileSize,
+	)
+
+	var version int
+	err = row.Scan(&version)
+	if err != nil {
+		slog.Error("Error while inserting metadata version", slog.Any("err", err))
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// SetChecksums sets checksums of file of the document version with id meta.ID.
+//
+// Used to save checksums of already stored files, so other fields and update time are kept.
+// Metadata of the document is updated too if the version is current.
+func (p PostgresRepository) SetChecksums(ctx context.Context, meta models.Metadata) error {
+	_, err := p.pool.Exec(ctx, querySetChecksums, meta.ID, meta.Version, meta.SHA256, meta.MD5)
+	return err
+}
+
+// SetUploadedFile sets file size, stored size, encoding, checksums and extracted text of uploaded file
+// of the document version with id meta.ID.
+// Metadata of the document is updated too if the version is current.
+//
+// Uploaded file must fit into quota of the document owner meta.OwnerID. Size of file of unknown size
+// is counted only now, the owner is locked while it is checked, so concurrent uploads can't exceed quota.
+//
+// Returns ErrQuotaExceeded if the file doesn't fit into quota, version is not changed then.
+func (p PostgresRepository) SetUploadedFile(ctx context.Context, meta models.Metadata, quota models.Quota) error {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, querySetVersionFile, versionFileArgs(meta)...)
+	if err != nil {
+		return err
+	}
+
+	// File is already counted in usage
+	err = checkQuota(ctx, tx, *meta.OwnerID, quota, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// versionFileArgs returns arguments of querySetVersionFile.
+func versionFileArgs(meta models.Metadata) []any {
+	return []any{
+		meta.ID,
+		meta.Version,
+		meta.FileSize,
+		meta.SHA256,
+		meta.MD5,
+		meta.StoredSize,
+		meta.ContentText,
+		meta.Encoding,
+	}
+}
+
+// PromoteVersion makes version meta.Version the current version of the document with id meta.ID.
+//
+// It begins a transaction, marks version as ready, and copies version
+// data to the metadata table. Metadata of a pending upload becomes ready.
+// If meta.ExpiresAt is set, expiration time of the document is changed in the same transaction,
+// zero time clears it.
+// If updated is not nil, version is promoted only if the document was not updated since then.
+//
+// Returns ErrNotFound if version does not exist.
+// Returns ErrPreconditionFailed if document was updated.
+func (p PostgresRepository) PromoteVersion(ctx context.Context, meta models.Metadata, updated *time.Time) error {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	// Mark version as ready
+	tag, err := tx.Exec(ctx, querySetVersionReady, meta.ID, meta.Version)
+	if err != nil {
+		slog.Error("Error while updating metadata version", slog.Any("err", err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		err = apperrors.ErrNotFound
+		return err
+	}
+
+	// Copy version data to metadata
+	tag, err = tx.Exec(ctx, queryPromoteVersion, meta.ID, meta.Version, updated)
+	if err != nil {
+		slog.Error("Error while promoting metadata version", slog.Any("err", err))
+		return err
+	}
+
+	// Version exists, so document was updated or removed
+	if tag.RowsAffected() == 0 {
+		err = apperrors.ErrNotFound
+		if updated != nil {
+			err = apperrors.ErrPreconditionFailed
+		}
+		return err
+	}
+
+	// Expiration time is set for the whole document
+	if meta.ExpiresAt != nil {
+		_, err = tx.Exec(ctx, querySetExpiration, meta.ID, *meta.ExpiresAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Commit transaction
+	err = tx.Commit(ctx)
+	if err != nil {
+		slog.Error("Error while committing transaction", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// RestoreVersion makes already uploaded version the current version of the document.
+// Unlike PromoteVersion, pending versions can't be restored, as their files may be not uploaded yet.
+// If updated is not nil, version is restored only if the document was not updated since then.
+//
+// Returns ErrNotFound if version does not exist or is pending.
+// Returns ErrPreconditionFailed if updated is not nil and version was not restored.
+func (p PostgresRepository) RestoreVersion(
+	ctx context.Context,
+	id uuid.UUID,
+	version int,
+	updated *time.Time,
+) error {
+	tag, err := p.pool.Exec(ctx, queryPromoteVersion, id, version, updated)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		if updated != nil {
+			return apperrors.ErrPreconditionFailed
+		}
+		return apperrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// RemoveVersion permanently removes given version of the document.
+// If the document itself was never promoted, its metadata is removed too.
+// Reference of the version to deduplicated blob is released.
+// Used to compensate failed uploads.
+// Returns error if remove failed.
+func (p PostgresRepository) RemoveVersion(ctx context.Context, id uuid.UUID, version int) error {
+	// Start transaction
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		slog.Error("Error while starting transaction", slog.Any("err", err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // ignore
+			tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, queryRemoveVersion, id, version)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, queryRemovePendingMetadata, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetVersions returns all uploaded versions of the document, newest first.
+//
+// Only version specific fields are filled: Version, Name, File, Mime, JSON, FileSize, SHA256, MD5, Blob,
+// StoredSize, Encoding, Created and Updated.
+//
+// Returns error if get failed.
+func (p PostgresRepository) GetVersions(ctx context.Context, id uuid.UUID) ([]models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetVersions, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.Metadata
+
+	for rows.Next() {
+		var meta models.Metadata
+
+		meta, err = scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, meta)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns given uploaded version of the document.
+//
+// Only version specific fields are filled: Version, Name, File, Mime, JSON, FileSize, SHA256, MD5, Blob,
+// StoredSize, Encoding, Created and Updated.
+//
+// Returns ErrNotFound if version does not exist.
+func (p PostgresRepository) GetVersion(
+	ctx context.Context,
+	id uuid.UUID,
+	version int,
+) (models.Metadata, error) {
+	meta, err := scanVersion(p.pool.QueryRow(ctx, queryGetVersion, id, version))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+
+	return meta, err
+}
+
+// GetPendingVersion returns the latest pending version of the document with given id.
+// Only ID, OwnerID, Version, Name, File, Mime and FileSize fields are filled.
+// Returns ErrNotFound if document has no pending versions.
+func (p PostgresRepository) GetPendingVersion(ctx context.Context, id uuid.UUID) (models.Metadata, error) {
+	meta := models.Metadata{
+		Status: models.MetadataStatusPending,
+	}
+
+	err := p.pool.QueryRow(ctx, queryGetPendingVersion, id).Scan(
+		&meta.ID,
+		&meta.OwnerID,
+		&meta.Version,
+		&meta.Name,
+		&meta.File,
+		&meta.Mime,
+		&meta.FileSize,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Metadata{}, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	return meta, nil
+}
+
+// GetFileVersions returns all uploaded versions of binary documents, trashed included.
+// Only ID, OwnerID, Version, FileSize, StoredSize, SHA256, MD5, Blob and Encoding fields are filled.
+// Returns error if get failed.
+func (p PostgresRepository) GetFileVersions(ctx context.Context) ([]models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetFileVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Metadata, error) {
+		meta := models.Metadata{
+			File:   true,
+			Status: models.MetadataStatusReady,
+		}
+
+		err := row.Scan(
+			&meta.ID,
+			&meta.OwnerID,
+			&meta.Version,
+			&meta.FileSize,
+			&meta.StoredSize,
+			&meta.SHA256,
+			&meta.MD5,
+			&meta.Blob,
+			&meta.Encoding,
+		)
+		return meta, err
+	})
+}
+
+// GetStaleMetadata returns versions that stay in pending status longer than olderThan.
+// Only ID, OwnerID and Version fields are filled.
+// Returns error if get failed.
+func (p PostgresRepository) GetStaleMetadata(
+	ctx context.Context,
+	olderThan time.Duration,
+) ([]models.Metadata, error) {
+	rows, err := p.pool.Query(ctx, queryGetStaleVersions, olderThan.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metaList []models.Metadata
+
+	for rows.Next() {
+		meta := models.Metadata{
+			Status: models.MetadataStatusPending,
+		}
+
+		err = rows.Scan(&meta.ID, &meta.OwnerID, &meta.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		metaList = append(metaList, meta)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return metaList, nil
+}
+
+// VersionExists checks if given version of document exists in repository.
+// Versions of trashed documents exist until purge.
+// Returns error if check failed.
+func (p PostgresRepository) VersionExists(
+	ctx context.Context,
+	id uuid.UUID,
+	version int,
+) (bool, error) {
+	var exists bool
+	err := p.pool.QueryRow(ctx, queryVersionExists, id, version).Scan(&exists)
+	return exists, err
+}
+
+// scanVersion scans row returned by queryGetVersion or queryGetVersions.
+func scanVersion(row pgx.Row) (models.Metadata, error) {
+	var (
+		meta        models.Metadata
+		createdTime time.Time
+	)
+
+	err := row.Scan(
+		&meta.Version,
+		&meta.Name,
+		&meta.File,
+		&meta.Mime,
+		&meta.JSON,
+		&meta.FileSize,
+		&meta.SHA256,
+		&meta.MD5,
+		&meta.Blob,
+		&meta.StoredSize,
+		&meta.Encoding,
+		&createdTime,
+	)
+	if err != nil {
+		return models.Metadata{}, err
+	}
+
+	meta.Created = createdTime.Format(time.DateTime)
+	meta.Updated = createdTime
+	meta.Status = models.MetadataStatusReady
+
+	return meta, nil
+}
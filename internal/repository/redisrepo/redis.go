@@ -87,8 +87,17 @@ func (r RedisRepository) SaveUserCache(
 ) error {
 	key := casheKey + id.String()
 
+	cached := make(models.CachedMetadatas, len(meta))
+	for i := range meta {
+		cached[i] = models.CachedMetadata{
+			Metadata: meta[i],
+			Blob:     meta[i].Blob,
+			Encoding: meta[i].Encoding,
+		}
+	}
+
 	// Marshal data
-	data, err := models.Metadatas(meta).MarshalJSON()
+	data, err := cached.MarshalJSON()
 	if err != nil {
 		return err
 	}
@@ -118,11 +127,18 @@ func (r RedisRepository) GetUserCache(
 	}
 
 	// Unmarshal data
-	metadata := make(models.Metadatas, 0)
-	err = metadata.UnmarshalJSON([]byte(data))
+	cached := make(models.CachedMetadatas, 0)
+	err = cached.UnmarshalJSON([]byte(data))
 	if err != nil {
 		return nil, err
 	}
 
+	metadata := make([]models.Metadata, len(cached))
+	for i := range cached {
+		metadata[i] = cached[i].Metadata
+		metadata[i].Blob = cached[i].Blob
+		metadata[i].Encoding = cached[i].Encoding
+	}
+
 	return metadata, nil
 }
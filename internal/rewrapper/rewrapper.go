@@ -0,0 +1,121 @@
+// Package rewrapper rotates master key of encrypted files.
+//
+// Data keys of all stored files are re-wrapped with the current master key,
+// after that old master keys can be removed from configuration.
+package rewrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// ErrRewrapFailed is returned by Rewrap if some files were not re-wrapped.
+var ErrRewrapFailed = errors.New("some files were not re-wrapped")
+
+// FileRepository used to re-wrap data keys of stored files.
+type FileRepository interface {
+	// RewrapFile wrap data key of stored file with the current master key.
+	// Returns false if data key is already wrapped with the current master key.
+	RewrapFile(ctx context.Context, meta models.Metadata) (bool, error)
+}
+
+// MetadataRepository used to list stored files.
+type MetadataRepository interface {
+	// GetFileVersions get all uploaded versions of binary documents, trashed included.
+	// Returns error if get failed.
+	GetFileVersions(ctx context.Context) ([]models.Metadata, error)
+}
+
+// Settings used to create Rewrapper.
+// Settings must be provided to New function.
+// All fields are required.
+type Settings struct {
+	// FileRepo used to re-wrap data keys.
+	FileRepo FileRepository
+
+	// MetaRepo used to list files.
+	MetaRepo MetadataRepository
+}
+
+// Rewrapper is a one-shot command that re-wraps data keys of all stored files.
+// Must be initialized with New function.
+type Rewrapper struct {
+	fileRepo FileRepository
+	metaRepo MetadataRepository
+}
+
+// New creates new Rewrapper.
+// Returns pointer to Rewrapper.
+// Accepts Settings as argument.
+func New(settings Settings) *Rewrapper {
+	return &Rewrapper{
+		fileRepo: settings.FileRepo,
+		metaRepo: settings.MetaRepo,
+	}
+}
+
+// Start re-wraps data keys of all stored files and returns.
+// Returns ErrRewrapFailed if some files were not re-wrapped.
+func (r *Rewrapper) Start(ctx context.Context) error {
+	return r.Rewrap(ctx)
+}
+
+// Rewrap re-wraps data keys of all stored files.
+// Blob shared by deduplicated documents is re-wrapped once.
+// Errors are logged and re-wrapping continues with the next file.
+// Returns ErrRewrapFailed if some files were not re-wrapped.
+func (r *Rewrapper) Rewrap(ctx context.Context) error {
+	versions, err := r.metaRepo.GetFileVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rewrapped, failed int
+	blobs := make(map[string]struct{})
+
+	for _, meta := range versions {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		if meta.Blob != "" {
+			if _, ok := blobs[meta.Blob]; ok {
+				continue
+			}
+			blobs[meta.Blob] = struct{}{}
+		}
+
+		var ok bool
+		ok, err = r.fileRepo.RewrapFile(ctx, meta)
+		if err != nil {
+			slog.Error(
+				"Error while re-wrapping file",
+				slog.String("id", meta.ID.String()),
+				slog.Int("version", meta.Version),
+				slog.Any("err", err),
+			)
+			failed++
+			continue
+		}
+
+		if ok {
+			rewrapped++
+		}
+	}
+
+	slog.Info(
+		"Files re-wrapped",
+		slog.Int("rewrapped", rewrapped),
+		slog.Int("failed", failed),
+	)
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d", ErrRewrapFailed, failed)
+	}
+
+	return nil
+}
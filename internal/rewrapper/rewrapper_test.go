@@ -0,0 +1,129 @@
+package rewrapper
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/repository/cryptorepo"
+	"github.com/FlutterDizaster/file-server/internal/repository/fsrepo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMetaRepo struct {
+	versions []models.Metadata
+}
+
+func (f *fakeMetaRepo) GetFileVersions(_ context.Context) ([]models.Metadata, error) {
+	return f.versions, nil
+}
+
+func newMasterKey(t *testing.T, id string) cryptorepo.MasterKey {
+	//nolint:mnd // AES-256 key size
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return cryptorepo.MasterKey{ID: id, Key: key}
+}
+
+func newMeta(data string) models.Metadata {
+	id := uuid.New()
+	ownerID := uuid.New()
+	return models.Metadata{
+		ID:       &id,
+		OwnerID:  &ownerID,
+		Version:  1,
+		FileSize: int64(len(data)),
+	}
+}
+
+func readFile(t *testing.T, repo interface {
+	GetFile(ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error)
+}, meta models.Metadata) string {
+	t.Helper()
+
+	file, err := repo.GetFile(context.Background(), meta)
+	require.NoError(t, err)
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestRewrapper_Rewrap(t *testing.T) {
+	ctx := context.Background()
+
+	fileRepo, err := fsrepo.New(ctx, fsrepo.Settings{Root: t.TempDir()})
+	require.NoError(t, err)
+
+	oldKey := newMasterKey(t, "old")
+	newKey := newMasterKey(t, "new")
+
+	oldRepo, err := cryptorepo.New(cryptorepo.Settings{Repo: fileRepo, MasterKeys: []cryptorepo.MasterKey{oldKey}})
+	require.NoError(t, err)
+
+	// File encrypted with old key
+	encrypted := newMeta("encrypted data")
+	_, err = oldRepo.UploadFile(ctx, strings.NewReader("encrypted data"), encrypted)
+	require.NoError(t, err)
+
+	// File stored before encryption was enabled
+	legacy := newMeta("legacy data")
+	_, err = fileRepo.UploadFile(ctx, strings.NewReader("legacy data"), legacy)
+	require.NoError(t, err)
+
+	repo, err := cryptorepo.New(cryptorepo.Settings{
+		Repo:       fileRepo,
+		MasterKeys: []cryptorepo.MasterKey{newKey, oldKey},
+	})
+	require.NoError(t, err)
+
+	rewrapper := New(Settings{
+		FileRepo: repo,
+		MetaRepo: &fakeMetaRepo{versions: []models.Metadata{encrypted, legacy}},
+	})
+	require.NoError(t, rewrapper.Rewrap(ctx))
+
+	// Old key is not needed anymore
+	newRepo, err := cryptorepo.New(cryptorepo.Settings{Repo: fileRepo, MasterKeys: []cryptorepo.MasterKey{newKey}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "encrypted data", readFile(t, newRepo, encrypted))
+	assert.Equal(t, "legacy data", readFile(t, newRepo, legacy))
+
+	// Legacy file is stored encrypted now
+	assert.NotContains(t, readFile(t, fileRepo, legacy), "legacy data")
+}
+
+func TestRewrapper_RewrapFailed(t *testing.T) {
+	ctx := context.Background()
+
+	fileRepo, err := fsrepo.New(ctx, fsrepo.Settings{Root: t.TempDir()})
+	require.NoError(t, err)
+
+	repo, err := cryptorepo.New(cryptorepo.Settings{
+		Repo:       fileRepo,
+		MasterKeys: []cryptorepo.MasterKey{newMasterKey(t, "new")},
+	})
+	require.NoError(t, err)
+
+	stored := newMeta("stored data")
+	_, err = repo.UploadFile(ctx, strings.NewReader("stored data"), stored)
+	require.NoError(t, err)
+
+	rewrapper := New(Settings{
+		FileRepo: repo,
+		MetaRepo: &fakeMetaRepo{versions: []models.Metadata{newMeta("missing data"), stored}},
+	})
+
+	err = rewrapper.Rewrap(ctx)
+	require.ErrorIs(t, err, ErrRewrapFailed)
+	assert.Equal(t, "stored data", readFile(t, repo, stored))
+}
@@ -27,7 +27,7 @@ func (h *Handler) docDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete document
-	err = h.documentsCtrl.DeleteFile(r.Context(), docID, userID)
+	err = h.documentsCtrl.DeleteFile(r.Context(), docID, userID, r.Header.Get("If-Match"))
 	if err != nil {
 		h.responseWithError(w, r, err, "Error while deleting document")
 		return
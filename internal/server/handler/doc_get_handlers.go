@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/checksum"
+	"github.com/FlutterDizaster/file-server/internal/etag"
 	"github.com/FlutterDizaster/file-server/internal/models"
 	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
 	"github.com/google/uuid"
@@ -14,11 +19,6 @@ import (
 type serveFileStrategy func(http.ResponseWriter, *http.Request, models.Metadata)
 
 func (h Handler) docGetHandler(w http.ResponseWriter, r *http.Request) {
-	strategyMap := map[bool]serveFileStrategy{
-		true:  h.serveBinaryFileHandler,
-		false: h.serveJSONFileHandler,
-	}
-
 	// Get user id
 	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
 	if !ok {
@@ -34,14 +34,14 @@ func (h Handler) docGetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get file info
-	info, err := h.documentsCtrl.GetFileInfo(r.Context(), docID, userID)
+	info, err := h.getDocInfo(r, docID, userID)
 	if err != nil {
 		h.responseWithError(w, r, err, "Error while getting file info")
 		return
 	}
 
 	// Send response
-	strategyMap[info.File](w, r, info)
+	h.serveDocument(w, r, info)
 }
 
 func (h Handler) docGetHeadHandler(w http.ResponseWriter, r *http.Request) {
@@ -60,32 +60,163 @@ func (h Handler) docGetHeadHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get file info
-	info, err := h.documentsCtrl.GetFileInfo(r.Context(), docID, userID)
+	info, err := h.getDocInfo(r, docID, userID)
 	if err != nil {
 		h.responseWithError(w, r, err, "Error while getting file info")
 		return
 	}
 
 	// Send response
-	if info.File {
+	writeDocumentHeaders(w, r, info)
+}
+
+func (h Handler) docGetByPathHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.getDocInfoByPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Send response
+	h.serveDocument(w, r, info)
+}
+
+func (h Handler) docGetByPathHeadHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.getDocInfoByPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Send response
+	writeDocumentHeaders(w, r, info)
+}
+
+// getDocInfoByPath returns metadata of the document of user with requested path.
+// If getting fails, error is written to w and false is returned.
+func (h Handler) getDocInfoByPath(w http.ResponseWriter, r *http.Request) (models.Metadata, bool) {
+	// Get user id
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		h.responseWithError(w, r, nil, "User id not found")
+		return models.Metadata{}, false
+	}
+
+	// Get file info
+	info, err := h.documentsCtrl.GetFileInfoByPath(r.Context(), userID, r.PathValue("path"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting file info")
+		return models.Metadata{}, false
+	}
+
+	return info, true
+}
+
+// serveDocument writes document content to response.
+func (h Handler) serveDocument(w http.ResponseWriter, r *http.Request, meta models.Metadata) {
+	strategyMap := map[bool]serveFileStrategy{
+		true:  h.serveBinaryFileHandler,
+		false: h.serveJSONFileHandler,
+	}
+
+	strategyMap[meta.File](w, r, meta)
+}
+
+// writeDocumentHeaders writes response headers of HEAD document request.
+func writeDocumentHeaders(w http.ResponseWriter, r *http.Request, meta models.Metadata) {
+	writeValidatorHeaders(w, meta)
+	if notModified(w, r, meta) {
+		return
+	}
+
+	if meta.File {
 		w.Header().Set("Content-Type", "application/octet-stream")
-		w.Header().Set("Content-Disposition", "attachment; filename="+info.Name)
-		w.Header().Set("Content-Lenght", strconv.FormatInt(info.FileSize, 10))
+		w.Header().Set("Content-Disposition", "attachment; filename="+meta.Name)
+		w.Header().Set("Content-Lenght", strconv.FormatInt(meta.FileSize, 10))
+		writeChecksumHeaders(w, meta)
+
+		// GET response of stored compressed file depends on Accept-Encoding,
+		// encoding of files uploaded before it was saved is unknown
+		if meta.Encoding != models.FileEncodingIdentity {
+			w.Header().Add("Vary", "Accept-Encoding")
+		}
+
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Content-Lenght", strconv.Itoa(len(info.JSON)))
+	w.Header().Set("Content-Lenght", strconv.Itoa(len(meta.JSON)))
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// writeChecksumHeaders writes Digest header of document file.
+// Nothing is written if checksums are not known yet.
+func writeChecksumHeaders(w http.ResponseWriter, meta models.Metadata) {
+	if digest := checksum.Digest(meta.SHA256, meta.MD5); digest != "" {
+		w.Header().Set("Digest", digest)
+	}
+}
+
+// writeValidatorHeaders writes ETag and Last-Modified headers of the document.
+func writeValidatorHeaders(w http.ResponseWriter, meta models.Metadata) {
+	w.Header().Set("ETag", etag.Of(meta))
+
+	if !meta.Updated.IsZero() {
+		w.Header().Set("Last-Modified", meta.Updated.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified reports whether client already has the current document
+// according to If-None-Match or, if it is not set, If-Modified-Since header.
+// If so, Not Modified response is written.
+func notModified(w http.ResponseWriter, r *http.Request, meta models.Metadata) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		if etag.NoneMatch(header, etag.Of(meta)) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		// Last-Modified has a second precision
+		if err != nil || meta.Updated.IsZero() || meta.Updated.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+// getDocInfo returns metadata of the requested document.
+// If version query parameter is set, metadata of this version is returned.
+func (h Handler) getDocInfo(r *http.Request, docID, userID uuid.UUID) (models.Metadata, error) {
+	versionStr := r.URL.Query().Get("version")
+	if versionStr == "" {
+		return h.documentsCtrl.GetFileInfo(r.Context(), docID, userID)
+	}
+
+	version, err := strconv.Atoi(versionStr)
+	if err != nil {
+		return models.Metadata{}, apperrors.ErrInvalidVersion
+	}
+
+	return h.documentsCtrl.GetFileVersion(r.Context(), docID, userID, version)
+}
+
 func (h Handler) serveBinaryFileHandler(
 	w http.ResponseWriter,
 	r *http.Request,
 	meta models.Metadata,
 ) {
+	writeValidatorHeaders(w, meta)
+	if notModified(w, r, meta) {
+		return
+	}
+
 	// Get file
 	file, err := h.documentsCtrl.GetFile(r.Context(), meta)
 	if err != nil {
@@ -97,8 +228,74 @@ func (h Handler) serveBinaryFileHandler(
 	w.Header().Set("Content-Type", "application/octet-stream")
 	w.Header().Set("Content-Disposition", "attachment; filename="+meta.Name)
 	w.Header().Set("Content-Lenght", strconv.FormatInt(meta.FileSize, 10))
+	writeChecksumHeaders(w, meta)
+
+	if encoded, ok := file.(encodedFile); ok {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		// Ranges are served from decoded file
+		if r.Header.Get("Range") == "" && acceptsEncoding(r, encoded.ContentEncoding()) {
+			h.serveEncodedFile(w, r, encoded)
+			return
+		}
+	}
 
-	http.ServeContent(w, r, meta.Name, time.Now(), file)
+	http.ServeContent(w, r, meta.Name, meta.Updated, file)
+}
+
+// encodedFile is a file stored compressed that can be sent to client as is.
+type encodedFile interface {
+	// ContentEncoding returns HTTP content coding of the stored file.
+	ContentEncoding() string
+
+	// Encoded returns reader of the stored file and its size.
+	Encoded() (io.Reader, int64, error)
+}
+
+// serveEncodedFile writes stored file to response with Content-Encoding header.
+// ETag of the document is weakened, because encoded response is not byte-for-byte equal to the document.
+// Digest of the document is removed, as it doesn't match encoded response.
+func (h Handler) serveEncodedFile(w http.ResponseWriter, r *http.Request, file encodedFile) {
+	body, size, err := file.Encoded()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting file")
+		return
+	}
+
+	if etag := w.Header().Get("ETag"); etag != "" {
+		w.Header().Set("ETag", "W/"+etag)
+	}
+	w.Header().Del("Digest")
+	w.Header().Set("Content-Encoding", file.ContentEncoding())
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = io.Copy(w, body); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+	}
+}
+
+// acceptsEncoding reports whether request Accept-Encoding header allows given content coding.
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, item := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(item, ";")
+			if !strings.EqualFold(strings.TrimSpace(name), encoding) {
+				continue
+			}
+
+			// Coding with zero quality is not acceptable
+			q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+			if !ok {
+				return true
+			}
+
+			quality, err := strconv.ParseFloat(q, 64)
+			return err == nil && quality > 0
+		}
+	}
+
+	return false
 }
 
 func (h Handler) serveJSONFileHandler(
@@ -106,6 +303,11 @@ func (h Handler) serveJSONFileHandler(
 	r *http.Request,
 	meta models.Metadata,
 ) {
+	writeValidatorHeaders(w, meta)
+	if notModified(w, r, meta) {
+		return
+	}
+
 	respData := models.Response{
 		Data: &meta.JSON,
 	}
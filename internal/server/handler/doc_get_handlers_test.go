@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/etag"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotModified(t *testing.T) {
+	id := uuid.New()
+	meta := models.Metadata{
+		ID:      &id,
+		Version: 1,
+		JSON:    `{"a":1}`,
+		Updated: time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC),
+	}
+
+	type test struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}
+	tests := []test{
+		{
+			name:   "without conditions",
+			method: http.MethodGet,
+			want:   false,
+		},
+		{
+			name:    "matching etag",
+			method:  http.MethodGet,
+			headers: map[string]string{"If-None-Match": `"other", ` + etag.Of(meta)},
+			want:    true,
+		},
+		{
+			name:    "weak etag",
+			method:  http.MethodHead,
+			headers: map[string]string{"If-None-Match": "W/" + etag.Of(meta)},
+			want:    true,
+		},
+		{
+			name:    "changed etag",
+			method:  http.MethodGet,
+			headers: map[string]string{"If-None-Match": `"other"`},
+			want:    false,
+		},
+		{
+			name:   "etag takes precedence over date",
+			method: http.MethodGet,
+			headers: map[string]string{
+				"If-None-Match":     `"other"`,
+				"If-Modified-Since": meta.Updated.Format(http.TimeFormat),
+			},
+			want: false,
+		},
+		{
+			name:    "not modified since",
+			method:  http.MethodGet,
+			headers: map[string]string{"If-Modified-Since": meta.Updated.Format(http.TimeFormat)},
+			want:    true,
+		},
+		{
+			name:    "modified since",
+			method:  http.MethodGet,
+			headers: map[string]string{"If-Modified-Since": meta.Updated.Add(-time.Hour).Format(http.TimeFormat)},
+			want:    false,
+		},
+		{
+			name:    "not a read request",
+			method:  http.MethodPut,
+			headers: map[string]string{"If-None-Match": "*"},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/api/docs/"+id.String(), nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			got := notModified(w, r, meta)
+
+			assert.Equal(t, tt.want, got)
+			if tt.want {
+				assert.Equal(t, http.StatusNotModified, w.Code)
+			}
+		})
+	}
+}
+
+func TestWriteDocumentHeaders(t *testing.T) {
+	id := uuid.New()
+
+	type test struct {
+		name     string
+		encoding string
+		wantVary bool
+	}
+	tests := []test{
+		{
+			name:     "stored as is",
+			encoding: models.FileEncodingIdentity,
+			wantVary: false,
+		},
+		{
+			name:     "stored compressed",
+			encoding: "zstd",
+			wantVary: true,
+		},
+		{
+			name:     "unknown encoding",
+			encoding: "",
+			wantVary: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := models.Metadata{
+				ID:       &id,
+				Name:     "a.txt",
+				File:     true,
+				FileSize: 4,
+				SHA256:   "916f0027a575074ce72a331777c3478d6513f786a591bd892da1a577bf2335f9",
+				Encoding: tt.encoding,
+			}
+			r := httptest.NewRequest(http.MethodHead, "/api/docs/"+id.String(), nil)
+			w := httptest.NewRecorder()
+
+			writeDocumentHeaders(w, r, meta)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotEmpty(t, w.Header().Get("Digest"))
+			if tt.wantVary {
+				assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			} else {
+				assert.Empty(t, w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+type fakeEncodedFile struct {
+	data string
+}
+
+func (f fakeEncodedFile) ContentEncoding() string {
+	return "gzip"
+}
+
+func (f fakeEncodedFile) Encoded() (io.Reader, int64, error) {
+	return strings.NewReader(f.data), int64(len(f.data)), nil
+}
+
+func TestHandler_serveEncodedFile(t *testing.T) {
+	h := Handler{}
+	r := httptest.NewRequest(http.MethodGet, "/api/docs/"+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	w.Header().Set("ETag", `"etag"`)
+	w.Header().Set("Digest", "sha-256=abc")
+
+	h.serveEncodedFile(w, r, fakeEncodedFile{data: "encoded"})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "encoded", w.Body.String())
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "7", w.Header().Get("Content-Length"))
+	assert.Equal(t, `W/"etag"`, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Digest"))
+}
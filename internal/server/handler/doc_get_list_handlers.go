@@ -72,7 +72,7 @@ func (h Handler) prepareDocListResponse(
 	}
 
 	// Execute method
-	files, err := h.documentsCtrl.GetFilesInfo(r.Context(), userID, filesListReq)
+	filesList, err := h.documentsCtrl.GetFilesInfo(r.Context(), userID, filesListReq)
 	if err != nil {
 		h.responseWithError(w, r, err, "Error while getting files list")
 		return nil
@@ -80,9 +80,7 @@ func (h Handler) prepareDocListResponse(
 
 	// Prepare response
 	resp := &models.Response{
-		Data: &models.ResponseFilesList{
-			Docs: files,
-		},
+		Data: &filesList,
 	}
 
 	// Marshalling response
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+// grantsAction grants or revokes access to the document.
+type grantsAction func(r *http.Request, id, userID uuid.UUID, req models.GrantsRequest) error
+
+func (h Handler) docPostGrantsHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleGrants(w, r, func(r *http.Request, id, userID uuid.UUID, req models.GrantsRequest) error {
+		return h.documentsCtrl.GrantAccess(r.Context(), id, userID, req)
+	})
+}
+
+func (h Handler) docDeleteGrantsHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleGrants(w, r, func(r *http.Request, id, userID uuid.UUID, req models.GrantsRequest) error {
+		return h.documentsCtrl.RevokeAccess(r.Context(), id, userID, req)
+	})
+}
+
+// handleGrants parses grants request and executes action.
+func (h Handler) handleGrants(w http.ResponseWriter, r *http.Request, action grantsAction) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Check content type
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		err = apperrors.ErrInvalidContentType
+		h.responseWithError(w, r, err, r.Header.Get("Content-Type"))
+		return
+	}
+
+	// Reading body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while reading body")
+		return
+	}
+	defer r.Body.Close()
+
+	var grantsReq models.GrantsRequest
+	if err = grantsReq.UnmarshalJSON(body); err != nil {
+		h.responseWithError(w, r, err, "Error while unmarshaling body")
+		return
+	}
+
+	// Execute method
+	if err = action(r, docID, userID, grantsReq); err != nil {
+		h.responseWithError(w, r, err, "Error while changing document grants")
+		return
+	}
+
+	// Prepare response
+	respString := models.JSONString(fmt.Sprintf(`{"%s": true}`, docID))
+	respData := models.Response{
+		Response: &respString,
+	}
+
+	// Marshal response
+	resp, err := respData.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(resp); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/FlutterDizaster/file-server/internal/apperrors"
@@ -13,6 +15,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxFormFieldSize is the maximum size of upload form fields except file.
+const maxFormFieldSize = 10 << 20
+
 func (h Handler) docPostHandler(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
 	if !ok {
@@ -20,64 +25,190 @@ func (h Handler) docPostHandler(w http.ResponseWriter, r *http.Request) {
 		h.responseWithError(w, r, nil, "User id not found")
 		return
 	}
+
+	metadata, file, ok := h.parseUploadForm(w, r)
+	if !ok {
+		return
+	}
+	if file != nil {
+		defer file.Close()
+	}
+
+	// Set file owner ID
+	metadata.OwnerID = &userID
+
+	// Upload document
+	if err := h.documentsCtrl.UploadDocument(r.Context(), metadata, file); err != nil {
+		h.responseWithError(w, r, err, "Error while uploading document")
+		return
+	}
+
+	// Prepare response
+	resp := models.Response{
+		Data: &models.ResponseUploading{
+			JSON: metadata.JSON,
+			File: metadata.Name,
+		},
+	}
+
+	h.writeUploadResponse(w, r, resp)
+}
+
+// parseUploadForm reads multipart form of upload request without buffering the file.
+// It extracts metadata from "meta" part and JSON data from "json" part.
+// If metadata.File is true, "file" part must follow them and is returned as file
+// to be streamed directly to the repository.
+// Request body size is limited by maxUploadFileSize, file reading fails
+// with ErrUploadTooLarge when the limit is exceeded.
+// File size is taken from "file-size" metadata field or Content-Length header of
+// the file part, otherwise it is set to -1 and is counted while uploading.
+// If parsing fails, error is written to w and false is returned.
+// Returned file must be closed by caller if not nil.
+func (h Handler) parseUploadForm(
+	w http.ResponseWriter,
+	r *http.Request,
+) (models.Metadata, io.ReadCloser, bool) {
 	// Check content-type
 	if !strings.Contains(r.Header.Get("Content-Type"), "multipart/form-data") {
 		err := apperrors.ErrInvalidContentType
 		h.responseWithError(w, r, err, r.Header.Get("Content-Type"))
-		return
+		return models.Metadata{}, nil, false
 	}
 
-	// Parsing multipart form
-	err := r.ParseMultipartForm(h.maxUploadFileSize)
+	if h.maxUploadFileSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadFileSize)
+	}
+
+	reader, err := r.MultipartReader()
 	if err != nil {
-		h.responseWithError(w, r, err, "Error while parsing multipart form")
-		return
+		h.responseWithError(w, r, err, "Error while reading multipart form")
+		return models.Metadata{}, nil, false
 	}
 
-	// Extract metadata
-	metaStr := r.FormValue("meta")
-	var metadata models.Metadata
-	if err = metadata.UnmarshalJSON([]byte(metaStr)); err != nil {
-		h.responseWithError(w, r, err, "Error while unmarshaling metadata")
-		return
+	// Unknown file size unless it is set in metadata
+	metadata := models.Metadata{
+		FileSize: -1,
 	}
+	metaFound := false
 
-	// Set file owner ID
-	metadata.OwnerID = &userID
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			h.responseWithError(w, r, uploadError(err), "Error while reading multipart form")
+			return models.Metadata{}, nil, false
+		}
 
-	// Extract JSON data
-	jsonStr := r.FormValue("json")
-	metadata.JSON = models.JSONString(jsonStr)
+		switch part.FormName() {
+		case "meta":
+			// Extract metadata
+			var data []byte
+			if data, err = readFormField(part); err == nil {
+				err = metadata.UnmarshalJSON(data)
+			}
+			if err != nil {
+				h.responseWithError(w, r, err, "Error while unmarshaling metadata")
+				return models.Metadata{}, nil, false
+			}
+			metaFound = true
+		case "json":
+			// Extract JSON data
+			data, err := readFormField(part)
+			if err != nil {
+				h.responseWithError(w, r, err, "Error while reading JSON data")
+				return models.Metadata{}, nil, false
+			}
+			metadata.JSON = models.JSONString(data)
+		case "file":
+			if !metaFound {
+				h.responseWithError(w, r, apperrors.ErrWrongMetadata, "Meta part must precede file part")
+				return models.Metadata{}, nil, false
+			}
 
-	// Extract file
-	var file io.ReadCloser
-	if metadata.File {
-		var fileHeader *multipart.FileHeader
-		file, fileHeader, err = r.FormFile("file")
-		if err != nil {
-			h.responseWithError(w, r, err, "Error while getting file")
-			return
+			if !metadata.File {
+				continue
+			}
+
+			// File size may be sent in part header
+			if metadata.FileSize < 0 {
+				metadata.FileSize = partSize(part)
+			}
+
+			// File is streamed by caller
+			return metadata, &uploadFile{body: part}, true
 		}
-		defer file.Close()
+	}
 
-		// Set file size
-		metadata.FileSize = fileHeader.Size
+	if !metaFound {
+		h.responseWithError(w, r, apperrors.ErrWrongMetadata, "Meta part not found")
+		return models.Metadata{}, nil, false
 	}
 
-	// Upload document
-	if err = h.documentsCtrl.UploadDocument(r.Context(), metadata, file); err != nil {
-		h.responseWithError(w, r, err, "Error while uploading document")
-		return
+	if metadata.File {
+		h.responseWithError(w, r, apperrors.ErrWrongMetadata, "File part not found")
+		return models.Metadata{}, nil, false
 	}
 
-	// Prepare response
-	resp := models.Response{
-		Data: &models.ResponseUploading{
-			JSON: models.JSONString(jsonStr),
-			File: metadata.Name,
-		},
+	metadata.FileSize = 0
+
+	return metadata, nil, true
+}
+
+// readFormField reads value of multipart form field.
+// Returns ErrWrongMetadata if value is longer than maxFormFieldSize.
+func readFormField(part *multipart.Part) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize+1))
+	if err != nil {
+		return nil, uploadError(err)
+	}
+
+	if len(data) > maxFormFieldSize {
+		return nil, apperrors.ErrWrongMetadata
 	}
 
+	return data, nil
+}
+
+// partSize returns size of multipart form part from its Content-Length header.
+// Returns -1 if size is unknown.
+func partSize(part *multipart.Part) int64 {
+	size, err := strconv.ParseInt(part.Header.Get("Content-Length"), 10, 64)
+	if err != nil || size < 0 {
+		return -1
+	}
+	return size
+}
+
+// uploadFile is a file part of upload form or a file sent as request body.
+// Reading fails with ErrUploadTooLarge when request body size limit is exceeded.
+type uploadFile struct {
+	body io.ReadCloser
+}
+
+// Read implements io.Reader interface.
+func (f *uploadFile) Read(p []byte) (int, error) {
+	n, err := f.body.Read(p)
+	return n, uploadError(err)
+}
+
+// Close implements io.Closer interface.
+func (f *uploadFile) Close() error {
+	return f.body.Close()
+}
+
+// uploadError replaces error of exceeded request body size limit with ErrUploadTooLarge.
+func uploadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return apperrors.ErrUploadTooLarge
+	}
+	return err
+}
+
+// writeUploadResponse marshals and writes response of upload request.
+func (h Handler) writeUploadResponse(w http.ResponseWriter, r *http.Request, resp models.Response) {
 	// Marshal response
 	respData, err := resp.MarshalJSON()
 	if err != nil {
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_parseUploadForm(t *testing.T) {
+	type field struct {
+		name  string
+		value string
+	}
+	type test struct {
+		name     string
+		fields   []field
+		limit    int64
+		wantOK   bool
+		wantSize int64
+		wantFile string
+		wantErr  error
+	}
+	tests := []test{
+		{
+			name: "file of unknown size",
+			fields: []field{
+				{name: "meta", value: `{"name":"doc.txt","file":true}`},
+				{name: "json", value: `{"a":1}`},
+				{name: "file", value: "test data"},
+			},
+			wantOK:   true,
+			wantSize: -1,
+			wantFile: "test data",
+		},
+		{
+			name: "file size in metadata",
+			fields: []field{
+				{name: "meta", value: `{"name":"doc.txt","file":true,"file-size":9}`},
+				{name: "file", value: "test data"},
+			},
+			wantOK:   true,
+			wantSize: 9,
+			wantFile: "test data",
+		},
+		{
+			name: "json document",
+			fields: []field{
+				{name: "meta", value: `{"name":"doc.json"}`},
+				{name: "json", value: `{"a":1}`},
+			},
+			wantOK: true,
+		},
+		{
+			name: "file before meta",
+			fields: []field{
+				{name: "file", value: "test data"},
+				{name: "meta", value: `{"name":"doc.txt","file":true}`},
+			},
+		},
+		{
+			name: "file not found",
+			fields: []field{
+				{name: "meta", value: `{"name":"doc.txt","file":true}`},
+			},
+		},
+		{
+			name: "file too large",
+			fields: []field{
+				{name: "meta", value: `{"name":"doc.txt","file":true}`},
+				{name: "file", value: string(bytes.Repeat([]byte("a"), 1024))},
+			},
+			limit:    512,
+			wantOK:   true,
+			wantSize: -1,
+			wantErr:  apperrors.ErrUploadTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			for _, f := range tt.fields {
+				require.NoError(t, writer.WriteField(f.name, f.value))
+			}
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/docs", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			h := Handler{maxUploadFileSize: tt.limit}
+			meta, file, ok := h.parseUploadForm(rec, req)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				return
+			}
+
+			assert.Equal(t, tt.wantSize, meta.FileSize)
+			if file == nil {
+				return
+			}
+			defer file.Close()
+
+			data, err := io.ReadAll(file)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFile, string(data))
+		})
+	}
+}
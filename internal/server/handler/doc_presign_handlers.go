@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+func (h Handler) docPostPresignHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Check content type
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		err := apperrors.ErrInvalidContentType
+		h.responseWithError(w, r, err, r.Header.Get("Content-Type"))
+		return
+	}
+
+	// Reading body
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxFormFieldSize))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while reading body")
+		return
+	}
+	defer r.Body.Close()
+
+	// Unknown file size is not allowed
+	metadata := models.Metadata{
+		FileSize: -1,
+	}
+	if err = metadata.UnmarshalJSON(body); err != nil {
+		h.responseWithError(w, r, err, "Error while unmarshaling metadata")
+		return
+	}
+
+	// Create upload URL
+	presigned, err := h.documentsCtrl.PresignUpload(r.Context(), userID, metadata)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while creating upload url")
+		return
+	}
+
+	h.writePresignResponse(w, r, presigned)
+}
+
+func (h Handler) docCompletePresignHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Complete upload
+	metadata, err := h.documentsCtrl.CompletePresignedUpload(r.Context(), docID, userID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while completing upload")
+		return
+	}
+
+	// Prepare response
+	resp := models.Response{
+		Data: &models.ResponseUploading{
+			File:    metadata.Name,
+			Version: metadata.Version,
+		},
+	}
+
+	h.writeUploadResponse(w, r, resp)
+}
+
+func (h Handler) docGetPresignHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Create download URL
+	presigned, err := h.documentsCtrl.PresignDownload(r.Context(), docID, userID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while creating download url")
+		return
+	}
+
+	h.writePresignResponse(w, r, presigned)
+}
+
+// writePresignResponse marshals and writes presigned URL response.
+func (h Handler) writePresignResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	presigned models.ResponsePresignedURL,
+) {
+	// Prepare response
+	resp := &models.Response{
+		Data: &presigned,
+	}
+
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
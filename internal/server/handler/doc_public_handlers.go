@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+// sharePasswordHeader is a request header with share link password.
+const sharePasswordHeader = "X-Share-Password"
+
+func (h Handler) publicDocGetHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.getPublicDocInfo(w, r)
+	if !ok {
+		return
+	}
+
+	// Send response
+	h.serveDocument(w, r, info)
+}
+
+func (h Handler) publicDocGetHeadHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.getPublicDocInfo(w, r)
+	if !ok {
+		return
+	}
+
+	// Send response
+	writeDocumentHeaders(w, r, info)
+}
+
+// getPublicDocInfo returns metadata of the requested public document.
+// If getting fails, error is written to w and false is returned.
+func (h Handler) getPublicDocInfo(w http.ResponseWriter, r *http.Request) (models.Metadata, bool) {
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return models.Metadata{}, false
+	}
+
+	// Get file info
+	info, err := h.documentsCtrl.GetPublicFileInfo(r.Context(), docID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting file info")
+		return models.Metadata{}, false
+	}
+
+	return info, true
+}
+
+func (h Handler) shareLinkGetHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.openShareLink(w, r)
+	if !ok {
+		return
+	}
+
+	// Document client already has is not counted as download
+	writeValidatorHeaders(w, info)
+	if notModified(w, r, info) {
+		return
+	}
+
+	err := h.documentsCtrl.CountShareLinkDownload(r.Context(), r.PathValue("token"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while counting share link download")
+		return
+	}
+
+	// Send response
+	h.serveDocument(w, r, info)
+}
+
+func (h Handler) shareLinkGetHeadHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.openShareLink(w, r)
+	if !ok {
+		return
+	}
+
+	// Send response
+	writeDocumentHeaders(w, r, info)
+}
+
+// openShareLink returns metadata of the document share link points to.
+// Password is taken from X-Share-Password header, so it doesn't get into access logs.
+// If opening fails, error is written to w and false is returned.
+func (h Handler) openShareLink(w http.ResponseWriter, r *http.Request) (models.Metadata, bool) {
+	password := r.Header.Get(sharePasswordHeader)
+
+	info, err := h.documentsCtrl.OpenShareLink(r.Context(), r.PathValue("token"), password)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while opening share link")
+		return models.Metadata{}, false
+	}
+
+	return info, true
+}
+
+func (h Handler) docPostShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Check content type
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		err = apperrors.ErrInvalidContentType
+		h.responseWithError(w, r, err, r.Header.Get("Content-Type"))
+		return
+	}
+
+	// Reading body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while reading body")
+		return
+	}
+	defer r.Body.Close()
+
+	var linkReq models.ShareLinkRequest
+	if err = linkReq.UnmarshalJSON(body); err != nil {
+		h.responseWithError(w, r, err, "Error while unmarshaling body")
+		return
+	}
+
+	// Create share link
+	link, err := h.documentsCtrl.CreateShareLink(r.Context(), docID, userID, linkReq)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while creating share link")
+		return
+	}
+
+	// Prepare response
+	resp := &models.Response{
+		Data: &link,
+	}
+
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
+
+func (h Handler) docDeleteShareLinksHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Revoke share links
+	if err = h.documentsCtrl.RevokeShareLinks(r.Context(), docID, userID); err != nil {
+		h.responseWithError(w, r, err, "Error while revoking share links")
+		return
+	}
+
+	// Prepare response
+	respString := models.JSONString(fmt.Sprintf(`{"%s": true}`, docID))
+	respData := models.Response{
+		Response: &respString,
+	}
+
+	// Marshal response
+	resp, err := respData.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(resp); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
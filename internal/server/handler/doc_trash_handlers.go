@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+func (h Handler) docGetTrashHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get trash
+	docs, err := h.documentsCtrl.GetTrash(r.Context(), userID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting trash")
+		return
+	}
+
+	// Prepare response
+	resp := &models.Response{
+		Data: &models.ResponseFilesList{
+			Docs:  docs,
+			Total: len(docs),
+		},
+	}
+
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
+
+func (h Handler) docRestoreHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Restore document
+	if err = h.documentsCtrl.RestoreFile(r.Context(), docID, userID); err != nil {
+		h.responseWithError(w, r, err, "Error while restoring document")
+		return
+	}
+
+	// Prepare response
+	respString := models.JSONString(fmt.Sprintf(`{"%s": true}`, docID))
+	respData := models.Response{
+		Response: &respString,
+	}
+
+	// Marshal response
+	resp, err := respData.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(resp); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
+
+func (h Handler) docPurgeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Purge document
+	if err = h.documentsCtrl.PurgeFile(r.Context(), docID, userID); err != nil {
+		h.responseWithError(w, r, err, "Error while purging document")
+		return
+	}
+
+	// Prepare response
+	respString := models.JSONString(fmt.Sprintf(`{"%s": true}`, docID))
+	respData := models.Response{
+		Response: &respString,
+	}
+
+	// Marshal response
+	resp, err := respData.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(resp); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
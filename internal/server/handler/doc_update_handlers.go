@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+func (h Handler) docPatchHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Check content type
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		err = apperrors.ErrInvalidContentType
+		h.responseWithError(w, r, err, r.Header.Get("Content-Type"))
+		return
+	}
+
+	// Reading body
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxFormFieldSize))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while reading body")
+		return
+	}
+	defer r.Body.Close()
+
+	var patch models.MetadataPatch
+	if err = patch.UnmarshalJSON(body); err != nil {
+		h.responseWithError(w, r, err, "Error while unmarshaling body")
+		return
+	}
+
+	// Update document
+	metadata, err := h.documentsCtrl.UpdateFile(r.Context(), docID, userID, patch, r.Header.Get("If-Match"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while updating document")
+		return
+	}
+
+	// Prepare response
+	resp := &models.Response{
+		Data: &metadata,
+	}
+
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
+
+// docPutHandler replaces content of the document with request body.
+// Content-Type header sets MIME type of binary document, Content-Length sets file size.
+func (h Handler) docPutHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	if h.maxUploadFileSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadFileSize)
+	}
+
+	file := &uploadFile{body: r.Body}
+	defer file.Close()
+
+	// Unknown content length is -1
+	metadata := models.Metadata{
+		Mime:     r.Header.Get("Content-Type"),
+		FileSize: r.ContentLength,
+	}
+
+	// Replace content
+	version, err := h.documentsCtrl.ReplaceFile(
+		r.Context(),
+		docID,
+		userID,
+		metadata,
+		file,
+		r.Header.Get("If-Match"),
+	)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while replacing document content")
+		return
+	}
+
+	// Prepare response
+	resp := models.Response{
+		Data: &models.ResponseUploading{
+			Version: version,
+		},
+	}
+
+	h.writeUploadResponse(w, r, resp)
+}
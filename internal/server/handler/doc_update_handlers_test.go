@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocumentsCtrl records arguments of ReplaceFile and reads replaced content.
+// Methods not used by tests are not implemented and panic.
+type fakeDocumentsCtrl struct {
+	DocumentsController
+
+	meta    models.Metadata
+	data    string
+	ifMatch string
+}
+
+func (f *fakeDocumentsCtrl) ReplaceFile(
+	_ context.Context,
+	_, _ uuid.UUID,
+	meta models.Metadata,
+	file io.Reader,
+	ifMatch string,
+) (int, error) {
+	f.meta = meta
+	f.ifMatch = ifMatch
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return 0, err
+	}
+	f.data = string(data)
+
+	return 2, nil
+}
+
+func TestHandler_docPutHandler(t *testing.T) {
+	type test struct {
+		name          string
+		body          string
+		contentType   string
+		contentLength int64
+		ifMatch       string
+		limit         int64
+		wantCode      int
+		wantMeta      models.Metadata
+	}
+	tests := []test{
+		{
+			name:          "binary content",
+			body:          "test data",
+			contentType:   "image/png",
+			contentLength: 9,
+			ifMatch:       `"etag"`,
+			wantCode:      http.StatusOK,
+			wantMeta:      models.Metadata{Mime: "image/png", FileSize: 9},
+		},
+		{
+			name:          "unknown content length",
+			body:          "test data",
+			contentType:   "text/plain",
+			contentLength: -1,
+			wantCode:      http.StatusOK,
+			wantMeta:      models.Metadata{Mime: "text/plain", FileSize: -1},
+		},
+		{
+			name:          "no content type",
+			body:          `{"a":1}`,
+			contentLength: 7,
+			wantCode:      http.StatusOK,
+			wantMeta:      models.Metadata{FileSize: 7},
+		},
+		{
+			name:          "content too large",
+			body:          strings.Repeat("a", 1024),
+			contentType:   "text/plain",
+			contentLength: -1,
+			limit:         512,
+			wantCode:      http.StatusRequestEntityTooLarge,
+			wantMeta:      models.Metadata{Mime: "text/plain", FileSize: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := &fakeDocumentsCtrl{}
+			h := Handler{documentsCtrl: ctrl, maxUploadFileSize: tt.limit}
+
+			id := uuid.New()
+			req := httptest.NewRequest(http.MethodPut, "/api/docs/"+id.String(), strings.NewReader(tt.body))
+			req.SetPathValue("id", id.String())
+			req.ContentLength = tt.contentLength
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			req = req.WithContext(context.WithValue(req.Context(), middlewares.KeyUserID, uuid.New()))
+			rec := httptest.NewRecorder()
+
+			h.docPutHandler(rec, req)
+
+			require.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantMeta, ctrl.meta)
+			assert.Equal(t, tt.ifMatch, ctrl.ifMatch)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.body, ctrl.data)
+				assert.JSONEq(t, `{"data":{"version":2}}`, rec.Body.String())
+			}
+		})
+	}
+}
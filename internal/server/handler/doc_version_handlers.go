@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+func (h Handler) docPostVersionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	metadata, file, ok := h.parseUploadForm(w, r)
+	if !ok {
+		return
+	}
+	if file != nil {
+		defer file.Close()
+	}
+
+	// Upload new version
+	version, err := h.documentsCtrl.UploadVersion(r.Context(), docID, userID, metadata, file)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while uploading document version")
+		return
+	}
+
+	// Prepare response
+	resp := models.Response{
+		Data: &models.ResponseUploading{
+			JSON:    metadata.JSON,
+			File:    metadata.Name,
+			Version: version,
+		},
+	}
+
+	h.writeUploadResponse(w, r, resp)
+}
+
+func (h Handler) docGetVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Get versions
+	versions, err := h.documentsCtrl.GetVersions(r.Context(), docID, userID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting document versions")
+		return
+	}
+
+	// Prepare response
+	resp := &models.Response{
+		Data: &models.ResponseFilesList{
+			Docs: versions,
+		},
+	}
+
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
+
+func (h Handler) docRestoreVersionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get doc id
+	docID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid document id")
+		return
+	}
+
+	// Get version
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil {
+		h.responseWithError(w, r, apperrors.ErrInvalidVersion, "Invalid document version")
+		return
+	}
+
+	// Restore version
+	err = h.documentsCtrl.RestoreVersion(r.Context(), docID, userID, version, r.Header.Get("If-Match"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while restoring document version")
+		return
+	}
+
+	// Prepare response
+	respString := models.JSONString(fmt.Sprintf(`{"%s": %d}`, docID, version))
+	respData := models.Response{
+		Response: &respString,
+	}
+
+	// Marshal response
+	resp, err := respData.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(resp); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
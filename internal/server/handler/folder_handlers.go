@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+	"github.com/mailru/easyjson"
+)
+
+func (h Handler) folderGetListHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get folders
+	folders, err := h.documentsCtrl.GetFolders(r.Context(), userID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting folders")
+		return
+	}
+
+	respFolders := models.Folders(folders)
+	h.writeFolderResponse(w, r, http.StatusOK, models.Response{Data: &respFolders})
+}
+
+func (h Handler) folderPostHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	var folder models.Folder
+	if !h.readFolderRequest(w, r, &folder) {
+		return
+	}
+
+	// Create folder
+	folder, err := h.documentsCtrl.CreateFolder(r.Context(), userID, folder)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while creating folder")
+		return
+	}
+
+	h.writeFolderResponse(w, r, http.StatusCreated, models.Response{Data: &folder})
+}
+
+func (h Handler) folderPatchHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get folder id
+	folderID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid folder id")
+		return
+	}
+
+	var patch models.FolderPatch
+	if !h.readFolderRequest(w, r, &patch) {
+		return
+	}
+
+	// Rename or move folder
+	folder, err := h.documentsCtrl.UpdateFolder(r.Context(), folderID, userID, patch)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while updating folder")
+		return
+	}
+
+	h.writeFolderResponse(w, r, http.StatusOK, models.Response{Data: &folder})
+}
+
+func (h Handler) folderDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get folder id
+	folderID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid folder id")
+		return
+	}
+
+	// Delete folder
+	if err = h.documentsCtrl.DeleteFolder(r.Context(), folderID, userID); err != nil {
+		h.responseWithError(w, r, err, "Error while deleting folder")
+		return
+	}
+
+	respString := models.JSONString(fmt.Sprintf(`{"%s": true}`, folderID))
+	h.writeFolderResponse(w, r, http.StatusOK, models.Response{Response: &respString})
+}
+
+// readFolderRequest reads JSON request body into v.
+// If reading fails, error is written to w and false is returned.
+func (h Handler) readFolderRequest(w http.ResponseWriter, r *http.Request, v easyjson.Unmarshaler) bool {
+	// Check content type
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		h.responseWithError(w, r, apperrors.ErrInvalidContentType, r.Header.Get("Content-Type"))
+		return false
+	}
+
+	// Reading body
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxFormFieldSize))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while reading body")
+		return false
+	}
+	defer r.Body.Close()
+
+	if err = easyjson.Unmarshal(body, v); err != nil {
+		h.responseWithError(w, r, err, "Error while unmarshaling body")
+		return false
+	}
+
+	return true
+}
+
+// writeFolderResponse marshals and writes folders response with given status code.
+func (h Handler) writeFolderResponse(w http.ResponseWriter, r *http.Request, status int, resp models.Response) {
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
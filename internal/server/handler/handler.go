@@ -28,33 +28,117 @@ type DocumentsController interface {
 		ctx context.Context,
 		userID uuid.UUID,
 		filesListRequest models.FilesListRequest,
-	) ([]models.Metadata, error)
+	) (models.ResponseFilesList, error)
 	GetFileInfo(ctx context.Context, id, userID uuid.UUID) (models.Metadata, error)
 	GetFile(Ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error)
-	DeleteFile(ctx context.Context, id, userID uuid.UUID) error
+	DeleteFile(ctx context.Context, id, userID uuid.UUID, ifMatch string) error
+	UpdateFile(
+		ctx context.Context,
+		id, userID uuid.UUID,
+		patch models.MetadataPatch,
+		ifMatch string,
+	) (models.Metadata, error)
+	ReplaceFile(
+		ctx context.Context,
+		id, userID uuid.UUID,
+		meta models.Metadata,
+		file io.Reader,
+		ifMatch string,
+	) (int, error)
+	GetTrash(ctx context.Context, userID uuid.UUID) ([]models.Metadata, error)
+	RestoreFile(ctx context.Context, id, userID uuid.UUID) error
+	PurgeFile(ctx context.Context, id, userID uuid.UUID) error
+	GetFileInfoByPath(ctx context.Context, userID uuid.UUID, docPath string) (models.Metadata, error)
+	SearchDocuments(
+		ctx context.Context,
+		userID uuid.UUID,
+		query string,
+		limit, offset int,
+	) ([]models.SearchResult, error)
+
+	CreateFolder(ctx context.Context, userID uuid.UUID, folder models.Folder) (models.Folder, error)
+	GetFolders(ctx context.Context, userID uuid.UUID) ([]models.Folder, error)
+	UpdateFolder(ctx context.Context, id, userID uuid.UUID, patch models.FolderPatch) (models.Folder, error)
+	DeleteFolder(ctx context.Context, id, userID uuid.UUID) error
+
+	UploadVersion(
+		ctx context.Context,
+		id, userID uuid.UUID,
+		meta models.Metadata,
+		file io.Reader,
+	) (int, error)
+	GetFileVersion(ctx context.Context, id, userID uuid.UUID, version int) (models.Metadata, error)
+	GetVersions(ctx context.Context, id, userID uuid.UUID) ([]models.Metadata, error)
+	RestoreVersion(ctx context.Context, id, userID uuid.UUID, version int, ifMatch string) error
+
+	GrantAccess(ctx context.Context, id, userID uuid.UUID, req models.GrantsRequest) error
+	RevokeAccess(ctx context.Context, id, userID uuid.UUID, req models.GrantsRequest) error
+
+	GetPublicFileInfo(ctx context.Context, id uuid.UUID) (models.Metadata, error)
+	CreateShareLink(
+		ctx context.Context,
+		id, userID uuid.UUID,
+		req models.ShareLinkRequest,
+	) (models.ResponseShareLink, error)
+	RevokeShareLinks(ctx context.Context, id, userID uuid.UUID) error
+	OpenShareLink(ctx context.Context, token, password string) (models.Metadata, error)
+	CountShareLinkDownload(ctx context.Context, token string) error
+
+	CreateUpload(
+		ctx context.Context,
+		userID uuid.UUID,
+		meta models.Metadata,
+		length int64,
+	) (models.Upload, error)
+	GetUpload(ctx context.Context, id, userID uuid.UUID) (models.Upload, error)
+	WriteUpload(
+		ctx context.Context,
+		id, userID uuid.UUID,
+		offset int64,
+		data io.Reader,
+	) (models.Upload, error)
+	TerminateUpload(ctx context.Context, id, userID uuid.UUID) error
+
+	PresignUpload(
+		ctx context.Context,
+		userID uuid.UUID,
+		meta models.Metadata,
+	) (models.ResponsePresignedURL, error)
+	CompletePresignedUpload(ctx context.Context, id, userID uuid.UUID) (models.Metadata, error)
+	PresignDownload(ctx context.Context, id, userID uuid.UUID) (models.ResponsePresignedURL, error)
+
+	GetUsage(ctx context.Context, userID uuid.UUID) (models.Usage, error)
+	GetUserUsage(ctx context.Context, login string) (models.Usage, error)
+	SetUserQuota(ctx context.Context, login string, req models.QuotaRequest) (models.Usage, error)
 }
 
 type Settings struct {
-	JWTResolver       *jwtresolver.JWTResolver
-	UserCtrl          UserController
-	DocumentsCtrl     DocumentsController
-	MaxUploadFileSize int64
+	JWTResolver            *jwtresolver.JWTResolver
+	UserCtrl               UserController
+	DocumentsCtrl          DocumentsController
+	MaxUploadFileSize      int64
+	MaxResumableUploadSize int64
+	AdminToken             string
 }
 
 type Handler struct {
-	router            *http.ServeMux
-	jwtResolver       *jwtresolver.JWTResolver
-	userCtrl          UserController
-	documentsCtrl     DocumentsController
-	maxUploadFileSize int64
+	router                 *http.ServeMux
+	jwtResolver            *jwtresolver.JWTResolver
+	userCtrl               UserController
+	documentsCtrl          DocumentsController
+	maxUploadFileSize      int64
+	maxResumableUploadSize int64
+	adminToken             string
 }
 
 func New(settings Settings) *Handler {
 	h := &Handler{
-		jwtResolver:       settings.JWTResolver,
-		userCtrl:          settings.UserCtrl,
-		documentsCtrl:     settings.DocumentsCtrl,
-		maxUploadFileSize: settings.MaxUploadFileSize,
+		jwtResolver:            settings.JWTResolver,
+		userCtrl:               settings.UserCtrl,
+		documentsCtrl:          settings.DocumentsCtrl,
+		maxUploadFileSize:      settings.MaxUploadFileSize,
+		maxResumableUploadSize: settings.MaxResumableUploadSize,
+		adminToken:             settings.AdminToken,
 	}
 
 	h.setupRouter()
@@ -76,14 +160,58 @@ func (h *Handler) setupRouter() {
 	userRouter.HandleFunc("POST /auth", h.userAuthHandler)
 	userRouter.HandleFunc("POST /register", h.userRegisterHandler)
 
+	// Public documents routes
+	publicDocRouter := http.NewServeMux()
+	publicDocRouter.HandleFunc("GET /public/{id}", h.publicDocGetHandler)
+	publicDocRouter.HandleFunc("HEAD /public/{id}", h.publicDocGetHeadHandler)
+	publicDocRouter.HandleFunc("GET /share/{token}", h.shareLinkGetHandler)
+	publicDocRouter.HandleFunc("HEAD /share/{token}", h.shareLinkGetHeadHandler)
+
 	// Private routes
 	docRouter := http.NewServeMux()
 	docRouter.HandleFunc("GET /{id}", h.docGetHandler)
 	docRouter.HandleFunc("HEAD /{id}", h.docGetHeadHandler)
-	docRouter.HandleFunc("GET /", h.docGetListHandler)
-	docRouter.HandleFunc("HEAD /", h.docGetListHeadHandler)
-	docRouter.HandleFunc("POST /", h.docPostHandler)
+	docRouter.HandleFunc("GET /{$}", h.docGetListHandler)
+	docRouter.HandleFunc("HEAD /{$}", h.docGetListHeadHandler)
+	docRouter.HandleFunc("POST /{$}", h.docPostHandler)
 	docRouter.HandleFunc("DELETE /{id}", h.docDeleteHandler)
+	docRouter.HandleFunc("PATCH /{id}", h.docPatchHandler)
+	docRouter.HandleFunc("PUT /{id}", h.docPutHandler)
+	docRouter.HandleFunc("POST /{id}", h.docPostVersionHandler)
+	docRouter.HandleFunc("GET /{id}/versions", h.docGetVersionsHandler)
+	docRouter.HandleFunc("POST /{id}/versions/{version}/restore", h.docRestoreVersionHandler)
+	docRouter.HandleFunc("POST /{id}/grants", h.docPostGrantsHandler)
+	docRouter.HandleFunc("DELETE /{id}/grants", h.docDeleteGrantsHandler)
+	docRouter.HandleFunc("POST /{id}/links", h.docPostShareLinkHandler)
+	docRouter.HandleFunc("DELETE /{id}/links", h.docDeleteShareLinksHandler)
+	docRouter.HandleFunc("POST /presign", h.docPostPresignHandler)
+	docRouter.HandleFunc("POST /{id}/complete", h.docCompletePresignHandler)
+	docRouter.HandleFunc("GET /{id}/presign", h.docGetPresignHandler)
+
+	// Resumable uploads routes
+	uploadRouter := http.NewServeMux()
+	uploadRouter.HandleFunc("POST /{$}", h.uploadPostHandler)
+	uploadRouter.HandleFunc("HEAD /{id}", h.uploadHeadHandler)
+	uploadRouter.HandleFunc("PATCH /{id}", h.uploadPatchHandler)
+	uploadRouter.HandleFunc("DELETE /{id}", h.uploadDeleteHandler)
+
+	// Folders routes
+	folderRouter := http.NewServeMux()
+	folderRouter.HandleFunc("GET /{$}", h.folderGetListHandler)
+	folderRouter.HandleFunc("POST /{$}", h.folderPostHandler)
+	folderRouter.HandleFunc("PATCH /{id}", h.folderPatchHandler)
+	folderRouter.HandleFunc("DELETE /{id}", h.folderDeleteHandler)
+
+	// Trash routes
+	trashRouter := http.NewServeMux()
+	trashRouter.HandleFunc("GET /{$}", h.docGetTrashHandler)
+	trashRouter.HandleFunc("POST /{id}/restore", h.docRestoreHandler)
+	trashRouter.HandleFunc("DELETE /{id}", h.docPurgeHandler)
+
+	// Administrator routes
+	adminRouter := http.NewServeMux()
+	adminRouter.HandleFunc("GET /users/{login}/usage", h.adminUsageGetHandler)
+	adminRouter.HandleFunc("PUT /users/{login}/quota", h.adminQuotaPutHandler)
 
 	// Public middleware chain
 	publicChain := middlewares.MakeChain(
@@ -99,9 +227,34 @@ func (h *Handler) setupRouter() {
 		authMw.Handle,
 	)
 
+	// Administrator middleware chain
+	adminMw := middlewares.Admin{
+		Token: h.adminToken,
+	}
+	adminChain := middlewares.MakeChain(
+		middlewares.Logger,
+		adminMw.Handle,
+	)
+
 	// Setup general router
 	router.Handle("/api/", publicChain(http.StripPrefix("/api/", userRouter)))
+	router.Handle("/api/public/", publicChain(http.StripPrefix("/api", publicDocRouter)))
+	router.Handle("/api/share/", publicChain(http.StripPrefix("/api", publicDocRouter)))
 	router.Handle("/api/docs", privateChain(http.StripPrefix("/api/docs", docRouter)))
+	router.Handle("/api/docs/", privateChain(http.StripPrefix("/api/docs", docRouter)))
+	router.Handle("GET /api/docs/by-path/{path...}", privateChain(http.HandlerFunc(h.docGetByPathHandler)))
+	router.Handle("HEAD /api/docs/by-path/{path...}", privateChain(http.HandlerFunc(h.docGetByPathHeadHandler)))
+	router.Handle("/api/folders", privateChain(http.StripPrefix("/api/folders", folderRouter)))
+	router.Handle("/api/folders/", privateChain(http.StripPrefix("/api/folders", folderRouter)))
+	router.Handle("OPTIONS /api/uploads", publicChain(http.HandlerFunc(h.uploadOptionsHandler)))
+	router.Handle("OPTIONS /api/uploads/", publicChain(http.HandlerFunc(h.uploadOptionsHandler)))
+	router.Handle("/api/uploads", privateChain(http.StripPrefix("/api/uploads", uploadRouter)))
+	router.Handle("/api/uploads/", privateChain(http.StripPrefix("/api/uploads", uploadRouter)))
+	router.Handle("/api/trash", privateChain(http.StripPrefix("/api/trash", trashRouter)))
+	router.Handle("/api/trash/", privateChain(http.StripPrefix("/api/trash", trashRouter)))
+	router.Handle("GET /api/search", privateChain(http.HandlerFunc(h.searchGetHandler)))
+	router.Handle("GET /api/usage", privateChain(http.HandlerFunc(h.usageGetHandler)))
+	router.Handle("/api/admin/", adminChain(http.StripPrefix("/api/admin", adminRouter)))
 
 	h.router = router
 }
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_setupRouter(t *testing.T) {
+	var h *Handler
+	require.NotPanics(t, func() {
+		h = New(Settings{})
+	})
+
+	type test struct {
+		name   string
+		method string
+		path   string
+		code   int
+	}
+	tests := []test{
+		{
+			name:   "private route without token",
+			method: http.MethodGet,
+			path:   "/api/docs/3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "private list route without token",
+			method: http.MethodGet,
+			path:   "/api/docs",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "grants route without token",
+			method: http.MethodDelete,
+			path:   "/api/docs/3f2504e0-4f89-11d3-9a0c-0305e82c3301/grants",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "share links route without token",
+			method: http.MethodDelete,
+			path:   "/api/docs/3f2504e0-4f89-11d3-9a0c-0305e82c3301/links",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "patch route without token",
+			method: http.MethodPatch,
+			path:   "/api/docs/3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "presign route without token",
+			method: http.MethodPost,
+			path:   "/api/docs/presign",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "uploads route without token",
+			method: http.MethodPost,
+			path:   "/api/uploads/",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "uploads options without token",
+			method: http.MethodOptions,
+			path:   "/api/uploads/",
+			code:   http.StatusNoContent,
+		},
+		{
+			name:   "trash route without token",
+			method: http.MethodGet,
+			path:   "/api/trash",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "by path route without token",
+			method: http.MethodGet,
+			path:   "/api/docs/by-path/projects/report.pdf",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "folders route without token",
+			method: http.MethodPost,
+			path:   "/api/folders",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "search route without token",
+			method: http.MethodGet,
+			path:   "/api/search?q=report",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "usage route without token",
+			method: http.MethodGet,
+			path:   "/api/usage",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "admin route without token",
+			method: http.MethodPut,
+			path:   "/api/admin/users/user/quota",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "unknown route",
+			method: http.MethodGet,
+			path:   "/unknown",
+			code:   http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
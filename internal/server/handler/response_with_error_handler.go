@@ -16,7 +16,7 @@ func (h Handler) responseWithError(w http.ResponseWriter, r *http.Request, err e
 			Text: msg,
 		},
 	}
-	var appserror *apperrors.Error
+	var appserror apperrors.Error
 
 	switch {
 	case errors.As(err, &appserror):
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+func (h Handler) searchGetHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	// Get page
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		h.responseWithError(w, r, apperrors.ErrWrongSearchOptions, "Invalid limit")
+		return
+	}
+
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		h.responseWithError(w, r, apperrors.ErrWrongSearchOptions, "Invalid offset")
+		return
+	}
+
+	// Search documents
+	results, err := h.documentsCtrl.SearchDocuments(r.Context(), userID, r.URL.Query().Get("q"), limit, offset)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while searching documents")
+		return
+	}
+
+	respResults := models.SearchResults(results)
+	resp := models.Response{Data: &respResults}
+
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
+
+// queryInt returns integer value of query parameter with given key.
+// Missing parameter is zero.
+func queryInt(r *http.Request, key string) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+const (
+	// tusVersion is the supported version of tus resumable upload protocol.
+	tusVersion = "1.0.0"
+	// tusExtensions is the list of supported tus protocol extensions.
+	tusExtensions = "creation,termination"
+	// tusContentType is the content type of upload PATCH request body.
+	tusContentType = "application/offset+octet-stream"
+
+	// uploadsPath is the path resumable uploads are available at.
+	uploadsPath = "/api/uploads/"
+)
+
+func (h Handler) uploadOptionsHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	if h.maxResumableUploadSize > 0 {
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.maxResumableUploadSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h Handler) uploadPostHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.checkTusRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Get upload length
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		h.responseWithError(w, r, apperrors.ErrWrongUploadOptions, "Invalid upload length")
+		return
+	}
+
+	if h.maxResumableUploadSize > 0 && length > h.maxResumableUploadSize {
+		h.responseWithError(w, r, apperrors.ErrUploadTooLarge, "Invalid upload length")
+		return
+	}
+
+	// Get document metadata
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Invalid upload metadata")
+		return
+	}
+
+	// Create upload
+	upload, err := h.documentsCtrl.CreateUpload(r.Context(), userID, metadata, length)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while creating upload")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Location", uploadsPath+upload.ID.String())
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h Handler) uploadHeadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.checkTusRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Get upload id
+	uploadID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, apperrors.ErrNotFound, "Invalid upload id")
+		return
+	}
+
+	// Get upload
+	upload, err := h.documentsCtrl.GetUpload(r.Context(), uploadID, userID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting upload")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h Handler) uploadPatchHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.checkTusRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Check content-type
+	if r.Header.Get("Content-Type") != tusContentType {
+		err := apperrors.ErrUnsupportedUploadContentType
+		h.responseWithError(w, r, err, r.Header.Get("Content-Type"))
+		return
+	}
+
+	// Get upload id
+	uploadID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, apperrors.ErrNotFound, "Invalid upload id")
+		return
+	}
+
+	// Get upload offset
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		h.responseWithError(w, r, apperrors.ErrWrongUploadOptions, "Invalid upload offset")
+		return
+	}
+
+	// Write upload data
+	upload, err := h.documentsCtrl.WriteUpload(r.Context(), uploadID, userID, offset, r.Body)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while writing upload")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h Handler) uploadDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.checkTusRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Get upload id
+	uploadID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.responseWithError(w, r, apperrors.ErrNotFound, "Invalid upload id")
+		return
+	}
+
+	// Terminate upload
+	if err = h.documentsCtrl.TerminateUpload(r.Context(), uploadID, userID); err != nil {
+		h.responseWithError(w, r, err, "Error while terminating upload")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkTusRequest checks tus protocol version of request and returns id of user.
+// Tus-Resumable header is set for response.
+// If checking fails, error is written to w and false is returned.
+func (h Handler) checkTusRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		h.responseWithError(w, r, apperrors.ErrUnsupportedTusVersion, r.Header.Get("Tus-Resumable"))
+		return uuid.Nil, false
+	}
+
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// parseUploadMetadata parses Upload-Metadata header of tus creation request.
+//
+// Header contains comma separated key and base64 encoded value pairs.
+// Document metadata is taken from "meta" key in the same format as in multipart
+// upload form, JSON data from "json" key. Name and mime type are taken from
+// "filename" and "filetype" keys if they are not set in metadata.
+func parseUploadMetadata(header string) (models.Metadata, error) {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return models.Metadata{}, apperrors.ErrWrongUploadOptions
+		}
+
+		values[key] = string(value)
+	}
+
+	var metadata models.Metadata
+	if metaStr, ok := values["meta"]; ok {
+		if err := metadata.UnmarshalJSON([]byte(metaStr)); err != nil {
+			return models.Metadata{}, err
+		}
+	}
+
+	metadata.JSON = models.JSONString(values["json"])
+
+	if metadata.Name == "" {
+		metadata.Name = values["filename"]
+	}
+	if metadata.Mime == "" {
+		metadata.Mime = values["filetype"]
+	}
+
+	if metadata.Name == "" {
+		return models.Metadata{}, apperrors.ErrWrongMetadata
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	encode := base64.StdEncoding.EncodeToString
+
+	type test struct {
+		name    string
+		header  string
+		want    models.Metadata
+		wantErr bool
+	}
+	tests := []test{
+		{
+			name:   "tus standard keys",
+			header: "filename " + encode([]byte("photo.jpg")) + ",filetype " + encode([]byte("image/jpeg")),
+			want: models.Metadata{
+				Name: "photo.jpg",
+				Mime: "image/jpeg",
+			},
+		},
+		{
+			name: "document metadata",
+			header: "meta " + encode([]byte(`{"name":"doc.txt","mime":"text/plain","public":true}`)) +
+				", json " + encode([]byte(`{"a":1}`)) +
+				", filename " + encode([]byte("other.txt")),
+			want: models.Metadata{
+				Name:   "doc.txt",
+				Mime:   "text/plain",
+				Public: true,
+				JSON:   `{"a":1}`,
+			},
+		},
+		{
+			name:    "invalid base64",
+			header:  "filename !!!",
+			wantErr: true,
+		},
+		{
+			name:    "without name",
+			header:  "filetype " + encode([]byte("image/jpeg")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUploadMetadata(tt.header)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/server/middlewares"
+	"github.com/google/uuid"
+)
+
+func (h Handler) usageGetHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middlewares.KeyUserID).(uuid.UUID)
+	if !ok {
+		slog.Error("User id not found in context")
+		h.responseWithError(w, r, nil, "User id not found")
+		return
+	}
+
+	usage, err := h.documentsCtrl.GetUsage(r.Context(), userID)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting usage")
+		return
+	}
+
+	h.writeUsageResponse(w, r, usage)
+}
+
+func (h Handler) adminUsageGetHandler(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.documentsCtrl.GetUserUsage(r.Context(), r.PathValue("login"))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while getting usage")
+		return
+	}
+
+	h.writeUsageResponse(w, r, usage)
+}
+
+func (h Handler) adminQuotaPutHandler(w http.ResponseWriter, r *http.Request) {
+	// Check content type
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		err := apperrors.ErrInvalidContentType
+		h.responseWithError(w, r, err, r.Header.Get("Content-Type"))
+		return
+	}
+
+	// Reading body
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxFormFieldSize))
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while reading body")
+		return
+	}
+	defer r.Body.Close()
+
+	var quotaReq models.QuotaRequest
+	if err = quotaReq.UnmarshalJSON(body); err != nil {
+		h.responseWithError(w, r, err, "Error while unmarshaling body")
+		return
+	}
+
+	usage, err := h.documentsCtrl.SetUserQuota(r.Context(), r.PathValue("login"), quotaReq)
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while setting quota")
+		return
+	}
+
+	h.writeUsageResponse(w, r, usage)
+}
+
+// writeUsageResponse marshals and writes storage usage response.
+func (h Handler) writeUsageResponse(w http.ResponseWriter, r *http.Request, usage models.Usage) {
+	// Prepare response
+	resp := &models.Response{
+		Data: &usage,
+	}
+
+	// Marshal response
+	respData, err := resp.MarshalJSON()
+	if err != nil {
+		h.responseWithError(w, r, err, "Error while marshaling response")
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(respData); err != nil {
+		slog.Error("Error while writing response", slog.Any("err", err))
+		return
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+)
+
+// Admin is a stateful middleware that checks if request is made by administrator.
+// Administrator token must be passed in Authorization header.
+// If token is not set, all requests are rejected.
+type Admin struct {
+	Token string
+}
+
+// Handle method handles incoming requests.
+func (a *Admin) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if token == "" {
+			responseWithError(w, r, apperrors.ErrAuthorizationHeaderNotFound)
+			return
+		}
+
+		if a.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.Token)) != 1 {
+			responseWithError(w, r, apperrors.ErrInvalidToken)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
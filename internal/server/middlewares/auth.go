@@ -33,14 +33,14 @@ func (a *Auth) Handle(next http.Handler) http.Handler {
 		// If token not found, return error
 		// Otherwise, try to decode it
 		if token == "" {
-			a.responseWithError(w, r, apperrors.ErrAuthorizationHeaderNotFound)
+			responseWithError(w, r, apperrors.ErrAuthorizationHeaderNotFound)
 			return
 		}
 
 		// Try to decode token
 		claims, err := a.Resolver.DecryptToken(token)
 		if err != nil {
-			a.responseWithError(w, r, apperrors.ErrInvalidToken)
+			responseWithError(w, r, apperrors.ErrInvalidToken)
 			return
 		}
 
@@ -52,11 +52,12 @@ func (a *Auth) Handle(next http.Handler) http.Handler {
 	})
 }
 
-func (a Auth) responseWithError(w http.ResponseWriter, r *http.Request, err error) {
+// responseWithError writes error response.
+func responseWithError(w http.ResponseWriter, r *http.Request, err error) {
 	resp := &models.Response{
 		Error: &models.ResponseError{},
 	}
-	var appserror *apperrors.Error
+	var appserror apperrors.Error
 
 	switch {
 	case errors.As(err, &appserror):
@@ -0,0 +1,63 @@
+// Package sharelink issues and verifies tokens of public share links.
+//
+// Token contains share link id and expiration time and is signed with HMAC-SHA256.
+// Other share link options, like password and downloads limit,
+// are stored in repository and checked by the caller.
+package sharelink
+
+import (
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/FlutterDizaster/file-server/internal/signedtoken"
+	"github.com/google/uuid"
+)
+
+// signaturePrefix separates share link signatures from other signatures made with the same secret.
+const signaturePrefix = "sharelink:"
+
+// Link is a share link token payload.
+type Link struct {
+	// ID is the share link id.
+	ID uuid.UUID `json:"i"`
+
+	// ExpiresAt is the share link expiration time in unix seconds.
+	ExpiresAt int64 `json:"e"`
+}
+
+// Signer used to sign and verify share link tokens.
+// Must be initialized with New function.
+type Signer struct {
+	signer *signedtoken.Signer
+}
+
+// New creates new Signer with given secret.
+func New(secret string) *Signer {
+	return &Signer{
+		signer: signedtoken.New(secret, signaturePrefix),
+	}
+}
+
+// Sign encodes and signs link.
+// Returned token is URL safe.
+func (s *Signer) Sign(link Link) string {
+	// Link contains only strings and numbers, marshaling can't fail
+	token, _ := s.signer.Encode(link)
+	return token
+}
+
+// Parse verifies token signature and expiration time and decodes link.
+// Returns ErrInvalidShareLink if token is malformed or signature is wrong.
+// Returns ErrShareLinkExpired if link is expired.
+func (s *Signer) Parse(token string) (Link, error) {
+	var link Link
+	if !s.signer.Decode(token, &link) {
+		return Link{}, apperrors.ErrInvalidShareLink
+	}
+
+	if time.Now().Unix() >= link.ExpiresAt {
+		return Link{}, apperrors.ErrShareLinkExpired
+	}
+
+	return link, nil
+}
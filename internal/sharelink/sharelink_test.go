@@ -0,0 +1,57 @@
+package sharelink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FlutterDizaster/file-server/internal/apperrors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	signer := New("secret")
+	valid := Link{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	type test struct {
+		name    string
+		token   string
+		want    Link
+		wantErr error
+	}
+	tests := []test{
+		{
+			name:  "valid",
+			token: signer.Sign(valid),
+			want:  valid,
+		},
+		{
+			name:    "expired",
+			token:   signer.Sign(Link{ID: valid.ID, ExpiresAt: time.Now().Add(-time.Second).Unix()}),
+			wantErr: apperrors.ErrShareLinkExpired,
+		},
+		{
+			name:    "other secret",
+			token:   New("other").Sign(valid),
+			wantErr: apperrors.ErrInvalidShareLink,
+		},
+		{
+			name:    "malformed",
+			token:   "token",
+			wantErr: apperrors.ErrInvalidShareLink,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := signer.Parse(tt.token)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, link)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package signedtoken encodes JSON payloads to URL safe tokens signed with HMAC-SHA256.
+//
+// Token is a base64 encoded payload and its signature separated with a dot.
+// Payload is not encrypted, so tokens must not contain secrets.
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// Signer used to sign and verify tokens.
+// Must be initialized with New function.
+type Signer struct {
+	secret []byte
+	prefix string
+}
+
+// New creates new Signer with given secret.
+// Prefix is signed together with payload, it separates tokens of different kinds
+// signed with the same secret.
+func New(secret, prefix string) *Signer {
+	return &Signer{
+		secret: []byte(secret),
+		prefix: prefix,
+	}
+}
+
+// Encode marshals and signs payload.
+// Payload must be marshalable to JSON.
+func (s *Signer) Encode(payload any) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(data)), nil
+}
+
+// Decode verifies token signature and unmarshals its payload into v.
+// Returns false if token is malformed or signature is wrong.
+func (s *Signer) Decode(token string, v any) bool {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.sign(data)) {
+		return false
+	}
+
+	return json.Unmarshal(data, v) == nil
+}
+
+// sign returns HMAC-SHA256 of prefix and payload.
+func (s *Signer) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(s.prefix))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
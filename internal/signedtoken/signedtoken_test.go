@@ -0,0 +1,71 @@
+package signedtoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	type payload struct {
+		ID string `json:"i"`
+	}
+
+	signer := New("secret", "test:")
+	token, err := signer.Encode(payload{ID: "doc"})
+	require.NoError(t, err)
+
+	type test struct {
+		name  string
+		token string
+		ok    bool
+	}
+	tests := []test{
+		{
+			name:  "valid",
+			token: token,
+			ok:    true,
+		},
+		{
+			name:  "other secret",
+			token: mustEncode(t, New("other", "test:"), payload{ID: "doc"}),
+			ok:    false,
+		},
+		{
+			name:  "other prefix",
+			token: mustEncode(t, New("secret", "other:"), payload{ID: "doc"}),
+			ok:    false,
+		},
+		{
+			name:  "tampered payload",
+			token: mustEncode(t, signer, payload{ID: "other"})[:10] + token[10:],
+			ok:    false,
+		},
+		{
+			name:  "malformed",
+			token: "token",
+			ok:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got payload
+			ok := signer.Decode(tt.token, &got)
+
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, payload{ID: "doc"}, got)
+			}
+		})
+	}
+}
+
+func mustEncode(t *testing.T, signer *Signer, v any) string {
+	t.Helper()
+
+	token, err := signer.Encode(v)
+	require.NoError(t, err)
+
+	return token
+}
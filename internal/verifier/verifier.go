@@ -0,0 +1,167 @@
+// Package verifier re-checks integrity of stored document files.
+//
+// Every uploaded file is read back from file repository and its size and checksums
+// are compared with ones saved in metadata. Missing checksums, like ones of files
+// uploaded with presigned URLs, are computed and saved.
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/FlutterDizaster/file-server/internal/checksum"
+	"github.com/FlutterDizaster/file-server/internal/models"
+)
+
+// ErrCorrupted is returned by Verify if some files don't match their metadata.
+var ErrCorrupted = errors.New("corrupted files found")
+
+// FileRepository used to read stored files.
+type FileRepository interface {
+	// GetFile get file from repository.
+	// Returns error if get failed.
+	GetFile(ctx context.Context, meta models.Metadata) (io.ReadSeekCloser, error)
+}
+
+// MetadataRepository used to list uploaded files and save their checksums.
+type MetadataRepository interface {
+	// GetFileVersions get all uploaded versions of binary documents, trashed included.
+	// Returns error if get failed.
+	GetFileVersions(ctx context.Context) ([]models.Metadata, error)
+
+	// SetChecksums set checksums of version with id meta.ID, other fields are not changed.
+	// Returns error if set failed.
+	SetChecksums(ctx context.Context, meta models.Metadata) error
+}
+
+// Settings used to create Verifier.
+// Settings must be provided to New function.
+// All fields except ComputeMD5 are required.
+type Settings struct {
+	// FileRepo used to read files.
+	FileRepo FileRepository
+
+	// MetaRepo used to list files and save checksums.
+	MetaRepo MetadataRepository
+
+	// ComputeMD5 enables MD5 checksum of files without one.
+	ComputeMD5 bool
+}
+
+// Verifier is a one-shot command that verifies stored files against their metadata.
+// Must be initialized with New function.
+type Verifier struct {
+	fileRepo   FileRepository
+	metaRepo   MetadataRepository
+	computeMD5 bool
+}
+
+// New creates new Verifier.
+// Returns pointer to Verifier.
+// Accepts Settings as argument.
+func New(settings Settings) *Verifier {
+	return &Verifier{
+		fileRepo:   settings.FileRepo,
+		metaRepo:   settings.MetaRepo,
+		computeMD5: settings.ComputeMD5,
+	}
+}
+
+// Start verifies all stored files and returns.
+// Returns ErrCorrupted if some files are missing or don't match their metadata.
+func (v *Verifier) Start(ctx context.Context) error {
+	return v.Verify(ctx)
+}
+
+// Verify reads every uploaded file and checks its size and checksums.
+// Mismatches are logged and verification continues with the next file.
+// Returns ErrCorrupted if some files are missing or don't match their metadata.
+func (v *Verifier) Verify(ctx context.Context) error {
+	versions, err := v.metaRepo.GetFileVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var corrupted int
+	for _, meta := range versions {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		var ok bool
+		ok, err = v.verifyFile(ctx, meta)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			corrupted++
+		}
+	}
+
+	slog.Info(
+		"Files verified",
+		slog.Int("total", len(versions)),
+		slog.Int("corrupted", corrupted),
+	)
+
+	if corrupted > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrCorrupted, corrupted, len(versions))
+	}
+
+	return nil
+}
+
+// verifyFile checks size and checksums of a single file.
+// Missing checksums are saved to metadata.
+// Returns false if file is missing or doesn't match metadata.
+func (v *Verifier) verifyFile(ctx context.Context, meta models.Metadata) (bool, error) {
+	log := slog.With(
+		slog.String("id", meta.ID.String()),
+		slog.Int("version", meta.Version),
+	)
+
+	file, err := v.fileRepo.GetFile(ctx, meta)
+	if err != nil {
+		log.Error("Error while reading file", slog.Any("err", err))
+		return false, nil
+	}
+	defer file.Close()
+
+	hasher := checksum.New(v.computeMD5 || meta.MD5 != "")
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		log.Error("Error while reading file", slog.Any("err", err))
+		return false, nil
+	}
+
+	switch {
+	case size != meta.FileSize:
+		log.Error("File size mismatch", slog.Int64("expected", meta.FileSize), slog.Int64("actual", size))
+		return false, nil
+	case meta.SHA256 != "" && !checksum.Equal(meta.SHA256, hasher.SHA256()):
+		log.Error("SHA-256 mismatch", slog.String("expected", meta.SHA256), slog.String("actual", hasher.SHA256()))
+		return false, nil
+	case meta.MD5 != "" && !checksum.Equal(meta.MD5, hasher.MD5()):
+		log.Error("MD5 mismatch", slog.String("expected", meta.MD5), slog.String("actual", hasher.MD5()))
+		return false, nil
+	}
+
+	if meta.SHA256 != "" && meta.MD5 == hasher.MD5() {
+		return true, nil
+	}
+
+	// Save missing checksums
+	meta.SHA256 = hasher.SHA256()
+	meta.MD5 = hasher.MD5()
+	if err = v.metaRepo.SetChecksums(ctx, meta); err != nil {
+		return false, err
+	}
+
+	log.Info("Checksums saved")
+
+	return true, nil
+}
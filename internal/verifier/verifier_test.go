@@ -0,0 +1,122 @@
+package verifier
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/FlutterDizaster/file-server/internal/models"
+	"github.com/FlutterDizaster/file-server/internal/repository/fsrepo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testData   = "test data"
+	testSHA256 = "916f0027a575074ce72a331777c3478d6513f786a591bd892da1a577bf2335f9"
+	testMD5    = "eb733a00c0c9d336e65691a37ab54293"
+)
+
+type fakeMetaRepo struct {
+	versions []models.Metadata
+	saved    map[uuid.UUID]models.Metadata
+}
+
+func (f *fakeMetaRepo) GetFileVersions(_ context.Context) ([]models.Metadata, error) {
+	return f.versions, nil
+}
+
+func (f *fakeMetaRepo) SetChecksums(_ context.Context, meta models.Metadata) error {
+	f.saved[*meta.ID] = meta
+	return nil
+}
+
+func newMeta(size int64, sha256, md5 string) models.Metadata {
+	id := uuid.New()
+	ownerID := uuid.New()
+	return models.Metadata{
+		ID:       &id,
+		OwnerID:  &ownerID,
+		FileSize: size,
+		SHA256:   sha256,
+		MD5:      md5,
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name       string
+		meta       models.Metadata
+		missing    bool
+		computeMD5 bool
+		wantErr    bool
+		wantSaved  *models.Metadata
+	}{
+		{
+			name: "valid",
+			meta: newMeta(int64(len(testData)), testSHA256, testMD5),
+		},
+		{
+			name:       "missing checksums",
+			meta:       newMeta(int64(len(testData)), "", ""),
+			computeMD5: true,
+			wantSaved:  &models.Metadata{SHA256: testSHA256, MD5: testMD5},
+		},
+		{
+			name:    "size mismatch",
+			meta:    newMeta(1, testSHA256, ""),
+			wantErr: true,
+		},
+		{
+			name:    "checksum mismatch",
+			meta:    newMeta(int64(len(testData)), strings.Repeat("0", len(testSHA256)), ""),
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			meta:    newMeta(int64(len(testData)), testSHA256, ""),
+			missing: true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fileRepo, err := fsrepo.New(ctx, fsrepo.Settings{Root: t.TempDir()})
+			require.NoError(t, err)
+			if !tt.missing {
+				_, err = fileRepo.UploadFile(ctx, strings.NewReader(testData), tt.meta)
+				require.NoError(t, err)
+			}
+			metaRepo := &fakeMetaRepo{
+				versions: []models.Metadata{tt.meta},
+				saved:    map[uuid.UUID]models.Metadata{},
+			}
+
+			v := New(Settings{
+				FileRepo:   fileRepo,
+				MetaRepo:   metaRepo,
+				ComputeMD5: tt.computeMD5,
+			})
+
+			err = v.Verify(ctx)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrCorrupted)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			saved, ok := metaRepo.saved[*tt.meta.ID]
+			if tt.wantSaved == nil {
+				assert.False(t, ok)
+				return
+			}
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantSaved.SHA256, saved.SHA256)
+			assert.Equal(t, tt.wantSaved.MD5, saved.MD5)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Package worker runs periodic jobs of background services.
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// Run calls job every interval and blocks until context is canceled.
+// Job must handle its errors itself, so they don't stop the worker.
+func Run(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx)
+		}
+	}
+}
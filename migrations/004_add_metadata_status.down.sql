@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_metadata_status_created;
+
+ALTER TABLE metadata DROP COLUMN IF EXISTS status;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'ready';
+
+CREATE INDEX idx_metadata_status_created ON metadata(created) WHERE status = 'pending';
+
+COMMIT;
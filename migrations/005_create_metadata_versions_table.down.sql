@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS metadata_versions;
+
+ALTER TABLE metadata DROP COLUMN IF EXISTS version;
+
+ALTER TABLE metadata ADD CONSTRAINT metadata_name_key UNIQUE (name);
+
+COMMIT;
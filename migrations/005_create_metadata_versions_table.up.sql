@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TABLE metadata DROP CONSTRAINT IF EXISTS metadata_name_key;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS metadata_versions (
+    meta_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    is_file BOOLEAN NOT NULL,
+    mime TEXT NOT NULL,
+    json_data JSON NOT NULL,
+    file_size BIGINT,
+    status TEXT NOT NULL DEFAULT 'ready',
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (meta_id, version),
+    FOREIGN KEY (meta_id) REFERENCES metadata(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_metadata_versions_pending ON metadata_versions(created) WHERE status = 'pending';
+
+-- Existing documents become the first version
+INSERT INTO metadata_versions (meta_id, version, name, is_file, mime, json_data, file_size, status, created)
+SELECT id, 1, name, is_file, mime, json_data, file_size, status, created FROM metadata
+ON CONFLICT DO NOTHING;
+
+COMMIT;
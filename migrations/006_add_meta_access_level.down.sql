@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE meta_access DROP COLUMN IF EXISTS granted_by;
+ALTER TABLE meta_access DROP COLUMN IF EXISTS level;
+
+COMMIT;
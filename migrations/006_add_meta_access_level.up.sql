@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE meta_access ADD COLUMN IF NOT EXISTS level TEXT NOT NULL DEFAULT 'read'
+    CHECK (level IN ('read', 'write', 'reshare'));
+
+-- Access without grantor is granted by the document owner
+ALTER TABLE meta_access ADD COLUMN IF NOT EXISTS granted_by UUID REFERENCES users(id) ON DELETE CASCADE;
+
+COMMIT;
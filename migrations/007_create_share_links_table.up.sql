@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    meta_id UUID NOT NULL,
+    created_by UUID NOT NULL,
+    password_hash TEXT NOT NULL DEFAULT '',
+    max_downloads INTEGER NOT NULL DEFAULT 0,
+    downloads INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meta_id) REFERENCES metadata(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_share_links_meta_id ON share_links(meta_id);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS uploads (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    owner_id UUID NOT NULL,
+    upload_length BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    name TEXT NOT NULL,
+    public BOOLEAN NOT NULL,
+    mime TEXT NOT NULL,
+    json_data JSON NOT NULL,
+    grants TEXT[] NOT NULL DEFAULT '{}',
+    storage_id TEXT NOT NULL DEFAULT '',
+    locked_until TIMESTAMP,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+COMMIT;
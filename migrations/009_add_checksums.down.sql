@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE metadata_versions DROP COLUMN IF EXISTS md5;
+ALTER TABLE metadata_versions DROP COLUMN IF EXISTS sha256;
+
+ALTER TABLE metadata DROP COLUMN IF EXISTS md5;
+ALTER TABLE metadata DROP COLUMN IF EXISTS sha256;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS sha256 TEXT NOT NULL DEFAULT '';
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS md5 TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE metadata_versions ADD COLUMN IF NOT EXISTS sha256 TEXT NOT NULL DEFAULT '';
+ALTER TABLE metadata_versions ADD COLUMN IF NOT EXISTS md5 TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE metadata_versions DROP COLUMN IF EXISTS blob;
+ALTER TABLE metadata DROP COLUMN IF EXISTS blob;
+
+DROP TABLE IF EXISTS blobs;
+
+COMMIT;
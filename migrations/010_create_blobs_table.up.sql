@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS blobs (
+    sha256 TEXT PRIMARY KEY,
+    size BIGINT NOT NULL,
+    refcount INTEGER NOT NULL DEFAULT 0,
+    updated TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS blobs_unreferenced_idx ON blobs (updated) WHERE refcount <= 0;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS blob TEXT NOT NULL DEFAULT '';
+ALTER TABLE metadata_versions ADD COLUMN IF NOT EXISTS blob TEXT NOT NULL DEFAULT '';
+
+COMMIT;
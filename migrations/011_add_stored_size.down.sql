@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE metadata_versions DROP COLUMN IF EXISTS stored_size;
+ALTER TABLE metadata DROP COLUMN IF EXISTS stored_size;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS stored_size BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE metadata_versions ADD COLUMN IF NOT EXISTS stored_size BIGINT NOT NULL DEFAULT 0;
+
+-- Files uploaded before are stored as is
+UPDATE metadata SET stored_size = file_size;
+UPDATE metadata_versions SET stored_size = file_size;
+
+COMMIT;
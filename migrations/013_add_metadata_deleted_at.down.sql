@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_metadata_trash;
+
+ALTER TABLE metadata DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Documents deleted before have no deletion time, their files are already removed
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_metadata_trash ON metadata(deleted_at) WHERE deleted = true;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_metadata_expires_at;
+
+ALTER TABLE uploads DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE metadata DROP COLUMN IF EXISTS expires_at;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_metadata_expires_at ON metadata(expires_at) WHERE expires_at IS NOT NULL;
+
+COMMIT;
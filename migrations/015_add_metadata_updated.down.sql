@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE metadata DROP COLUMN IF EXISTS updated;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS updated TIMESTAMP;
+
+UPDATE metadata SET updated = COALESCE(created, CURRENT_TIMESTAMP);
+
+ALTER TABLE metadata ALTER COLUMN updated SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE metadata ALTER COLUMN updated SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE uploads DROP COLUMN IF EXISTS folder_id;
+ALTER TABLE metadata DROP COLUMN IF EXISTS folder_id;
+
+DROP TABLE IF EXISTS folders;
+
+COMMIT;
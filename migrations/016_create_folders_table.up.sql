@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS folders (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    owner_id UUID NOT NULL,
+    parent_id UUID,
+    name TEXT NOT NULL,
+    path TEXT NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES folders(id),
+    UNIQUE (owner_id, path)
+);
+
+CREATE INDEX idx_folders_parent_id ON folders(parent_id);
+
+-- Documents without folder are placed in the root folder
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_metadata_folder_id ON metadata(folder_id);
+
+COMMIT;
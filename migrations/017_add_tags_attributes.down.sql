@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE uploads DROP COLUMN IF EXISTS attributes;
+ALTER TABLE uploads DROP COLUMN IF EXISTS tags;
+ALTER TABLE metadata DROP COLUMN IF EXISTS attributes;
+ALTER TABLE metadata DROP COLUMN IF EXISTS tags;
+
+COMMIT;
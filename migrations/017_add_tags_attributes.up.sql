@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_metadata_tags ON metadata USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_metadata_attributes ON metadata USING GIN (attributes);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS metadata_search_vector ON metadata;
+DROP FUNCTION IF EXISTS metadata_search_vector();
+
+ALTER TABLE metadata DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE metadata_versions DROP COLUMN IF EXISTS content_text;
+ALTER TABLE metadata DROP COLUMN IF EXISTS content_text;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS content_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE metadata_versions ADD COLUMN IF NOT EXISTS content_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS search_vector TSVECTOR NOT NULL DEFAULT ''::tsvector;
+
+-- Name has the highest weight, then tags, JSON data and extracted text of the file.
+CREATE OR REPLACE FUNCTION metadata_search_vector() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', NEW.name), 'A') ||
+        setweight(to_tsvector('simple', array_to_string(NEW.tags, ' ')), 'B') ||
+        setweight(jsonb_to_tsvector('simple', NEW.json_data::jsonb, '["key", "string"]'), 'C') ||
+        setweight(to_tsvector('simple', NEW.content_text), 'D');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER metadata_search_vector
+BEFORE INSERT OR UPDATE OF name, tags, json_data, content_text ON metadata
+FOR EACH ROW EXECUTE FUNCTION metadata_search_vector();
+
+UPDATE metadata SET name = name;
+
+CREATE INDEX IF NOT EXISTS idx_metadata_search_vector ON metadata USING GIN (search_vector);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE blobs DROP COLUMN IF EXISTS encoding;
+ALTER TABLE metadata_versions DROP COLUMN IF EXISTS encoding;
+ALTER TABLE metadata DROP COLUMN IF EXISTS encoding;
+
+COMMIT;
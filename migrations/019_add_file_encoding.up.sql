@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Encoding of files stored before is unknown, it is detected when the file is read
+ALTER TABLE metadata ADD COLUMN IF NOT EXISTS encoding TEXT NOT NULL DEFAULT '';
+ALTER TABLE metadata_versions ADD COLUMN IF NOT EXISTS encoding TEXT NOT NULL DEFAULT '';
+ALTER TABLE blobs ADD COLUMN IF NOT EXISTS encoding TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -36,3 +36,9 @@ func LoadConfig(cfg any) error {
 
 	return nil
 }
+
+// Args returns positional arguments left after flags parsing.
+// Must be called after LoadConfig.
+func Args() []string {
+	return flag.Args()
+}